@@ -0,0 +1,155 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Aggregate functions
+const (
+	AggregateSum   = "sum"
+	AggregateAvg   = "avg"
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+	AggregateCount = "count"
+)
+
+// aggregateFuncs are the supported aggregate functions
+var aggregateFuncs = map[string]bool{
+	AggregateSum:   true,
+	AggregateAvg:   true,
+	AggregateMin:   true,
+	AggregateMax:   true,
+	AggregateCount: true,
+}
+
+// AggregateOptions define an aggregation of an attribute
+type AggregateOptions struct {
+	// Func is one of AggregateSum, AggregateAvg, AggregateMin, AggregateMax
+	// or AggregateCount
+	Func string
+
+	// Key is the key of the aggregated attribute, which must hold numbers.
+	// The values which are not decimal numbers, i.e. -1.5, are ignored by
+	// all functions but AggregateCount, on every backend. May be empty for
+	// AggregateCount, to count the entities
+	Key string
+
+	// GroupBy is the key of the attribute the entities are grouped by,
+	// optional
+	GroupBy string
+
+	// Filter restricts the aggregation to the entities having these
+	// attribute values, by attribute key
+	Filter map[string]string
+}
+
+// AggregateResult is the result of an aggregation, for a group
+type AggregateResult struct {
+	// Group is the value of the GroupBy attribute, empty without GroupBy
+	// and for the entities missing the attribute
+	Group string
+
+	// Value is the result of the function, zero when no entity has the
+	// attribute
+	Value float64
+}
+
+// Aggregate computes an aggregate function of an attribute across the
+// entities of a type, for each group of the GroupBy attribute, ordered by
+// group. Without GroupBy there is a single result
+func (st *Store) Aggregate(entityType string, options AggregateOptions) ([]AggregateResult, error) {
+	if entityType == "" {
+		return nil, errors.New("entity type cannot be empty")
+	}
+
+	if !aggregateFuncs[options.Func] {
+		return nil, errors.New("aggregate function not supported: " + options.Func)
+	}
+
+	if options.Key == "" && options.Func != AggregateCount {
+		return nil, errors.New("attribute key cannot be empty")
+	}
+
+	return st.storage.aggregate(entityType, options)
+}
+
+// aggregate computes the aggregate of Aggregate in one query
+func (st *sqlStorage) aggregate(entityType string, options AggregateOptions) ([]AggregateResult, error) {
+	value := st.attributeValueAsNumber(goqu.I("a.attribute_value"))
+
+	var aggregate exp.SQLFunctionExpression
+
+	switch options.Func {
+	case AggregateSum:
+		aggregate = goqu.SUM(value)
+	case AggregateAvg:
+		aggregate = goqu.AVG(value)
+	case AggregateMin:
+		aggregate = goqu.MIN(value)
+	case AggregateMax:
+		aggregate = goqu.MAX(value)
+	case AggregateCount:
+		if options.Key == "" {
+			aggregate = goqu.COUNT(goqu.I("e.id"))
+		} else {
+			aggregate = goqu.COUNT(goqu.I("a.attribute_value"))
+		}
+	}
+
+	q := st.dialect().From(goqu.T(st.entityTableName).As("e")).Prepared(true).
+		Where(goqu.I("e.entity_type").Eq(entityType))
+
+	if options.Key != "" {
+		q = q.Join(goqu.T(st.attributeTableName).As("a"), goqu.On(
+			goqu.I("a.entity_id").Eq(goqu.I("e.id")),
+			goqu.I("a.attribute_key").Eq(options.Key),
+		))
+	}
+
+	q = st.attributeFilterWhere(q, "e.id", options.Filter)
+
+	if options.GroupBy == "" {
+		q = q.Select(aggregate.As("value"))
+	} else {
+		// The entities missing the attribute are in the empty group. The
+		// literal is not a parameter, which PostgreSQL would not match
+		// between the select and the group by
+		group := goqu.COALESCE(goqu.I("g.attribute_value"), goqu.L("''"))
+
+		q = q.LeftJoin(goqu.T(st.attributeTableName).As("g"), goqu.On(
+			goqu.I("g.entity_id").Eq(goqu.I("e.id")),
+			goqu.I("g.attribute_key").Eq(options.GroupBy),
+		)).
+			Select(group.As("group_value"), aggregate.As("value")).
+			GroupBy(group).
+			Order(goqu.I("group_value").Asc())
+	}
+
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	type aggregateRow struct {
+		Group sql.NullString  `db:"group_value"`
+		Value sql.NullFloat64 `db:"value"`
+	}
+
+	rows := []aggregateRow{}
+
+	if err := st.sqlSelect("Aggregate", st.db, false, &rows, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	results := make([]AggregateResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, AggregateResult{Group: row.Group.String, Value: row.Value.Float64})
+	}
+
+	return results, nil
+}
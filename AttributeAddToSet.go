@@ -0,0 +1,23 @@
+package entitystore
+
+// AttributeAddToSet adds the values missing from the set attribute of an
+// entity, at its end. Entity hooks are not run
+func (st *Store) AttributeAddToSet(entityID string, attributeKey string, values ...string) error {
+	return st.attributeValuesChange("AttributeAddToSet", entityID, attributeKey, func(current []string) ([]string, bool) {
+		existing := map[string]bool{}
+		for _, value := range current {
+			existing[value] = true
+		}
+
+		changed := false
+		for _, value := range values {
+			if !existing[value] {
+				existing[value] = true
+				current = append(current, value)
+				changed = true
+			}
+		}
+
+		return current, changed
+	})
+}
@@ -0,0 +1,9 @@
+package entitystore
+
+// AttributeAppendToList appends the values to the list attribute of an
+// entity, keeping the duplicates. Entity hooks are not run
+func (st *Store) AttributeAppendToList(entityID string, attributeKey string, values ...string) error {
+	return st.attributeValuesChange("AttributeAppendToList", entityID, attributeKey, func(current []string) ([]string, bool) {
+		return append(current, values...), len(values) > 0
+	})
+}
@@ -0,0 +1,34 @@
+package entitystore
+
+import (
+	"errors"
+	"sort"
+)
+
+// AttributeDistinctValues returns the distinct values of an attribute of
+// the entities of a type having the attribute values of the filter, in
+// order. The filter may be nil
+func (st *Store) AttributeDistinctValues(entityType string, attributeKey string, filter map[string]string) ([]string, error) {
+	if entityType == "" {
+		return nil, errors.New("entity type cannot be empty")
+	}
+
+	if attributeKey == "" {
+		return nil, errors.New("attribute key cannot be empty")
+	}
+
+	counts, err := st.storage.attributeValueCounts("AttributeDistinctValues", entityType, attributeKey, filter, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(counts))
+	for _, count := range counts {
+		values = append(values, count.Value)
+	}
+
+	sort.Strings(values)
+
+	return values, nil
+}
@@ -3,7 +3,7 @@ package entitystore
 import "errors"
 
 // AttributeFind finds an entity by ID
-func (st *Store) AttributeFind(entityID string, attributeKey string) (*Attribute, error) {
+func (st *Store) AttributeFind(entityID string, attributeKey string, options ...FindOptions) (*Attribute, error) {
 	if entityID == "" {
 		return nil, errors.New("entity id cannot be empty")
 	}
@@ -12,11 +12,46 @@ func (st *Store) AttributeFind(entityID string, attributeKey string) (*Attribute
 		return nil, errors.New("attribute key cannot be empty")
 	}
 
-	list, err := st.AttributeList(AttributeQueryOptions{
+	if !st.cacheEnabled(options) {
+		return st.attributeFind(entityID, attributeKey)
+	}
+
+	// The attributes of an entity are cached together, keyed by attribute
+	// key, so they are invalidated together with the entity
+	key := st.cacheKey("attributes", entityID)
+	cached, _ := st.cache.Get(key)
+	attrs, _ := cached.(map[string]Attribute)
+
+	if attr, found := attrs[attributeKey]; found {
+		st.cacheCount(true)
+		return &attr, nil
+	}
+
+	st.cacheCount(false)
+
+	generation := st.cacheGeneration.Load()
+	attr, err := st.attributeFind(entityID, attributeKey)
+
+	if err == nil && attr != nil {
+		// The cached map is shared, a copy is extended
+		extended := make(map[string]Attribute, len(attrs)+1)
+		for k, v := range attrs {
+			extended[k] = v
+		}
+		extended[attributeKey] = *attr
+
+		st.cacheSet(key, extended, generation)
+	}
+
+	return attr, err
+}
+
+func (st *Store) attributeFind(entityID string, attributeKey string) (*Attribute, error) {
+	list, err := st.storage.attributeList(AttributeQueryOptions{
 		EntityID:     entityID,
 		AttributeKey: attributeKey,
 		Limit:        1,
-	})
+	}, true)
 
 	if err != nil {
 		return nil, err
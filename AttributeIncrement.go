@@ -0,0 +1,17 @@
+package entitystore
+
+import "strconv"
+
+// AttributeIncrement adds the delta to an int attribute in one atomic
+// update, so concurrent increments are not lost, i.e. for counters and
+// quotas. A missing attribute is created with the delta. Returns the new
+// value. Entity hooks are not run
+func (st *Store) AttributeIncrement(entityID string, attributeKey string, delta int64) (int64, error) {
+	value, err := st.attributeIncrement(entityID, attributeKey, delta)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,16 @@
+package entitystore
+
+import "strconv"
+
+// AttributeIncrementFloat adds the delta to a float attribute in one
+// atomic update. A missing attribute is created with the delta. Returns
+// the new value. Entity hooks are not run
+func (st *Store) AttributeIncrementFloat(entityID string, attributeKey string, delta float64) (float64, error) {
+	value, err := st.attributeIncrement(entityID, attributeKey, delta)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
@@ -1,17 +1,58 @@
 package entitystore
 
 import (
+	"database/sql"
 	"errors"
-	"log"
 	"time"
 
-	"github.com/doug-martin/goqu/v9"
 	"github.com/gouniverse/uid"
 )
 
 // AttributeCreate creates a new attribute
 func (st *Store) AttributeInsert(attr Attribute) (*Attribute, error) {
-	return st.attributeInsertWithTransactionOrDB(st.db, attr)
+	defer st.cacheInvalidate(attr.EntityID())
+
+	entityType, err := st.changeEntityType(attr.EntityID())
+
+	if err != nil {
+		return nil, err
+	}
+
+	change := ChangeEvent{
+		Operation:  ChangeAttributeSet,
+		EntityID:   attr.EntityID(),
+		EntityType: entityType,
+		Attributes: map[string]string{attr.AttributeKey(): attr.AttributeValue()},
+	}
+
+	var inserted *Attribute
+
+	err = st.withTransaction("AttributeInsert", func(tx *sql.Tx) error {
+		var err error
+
+		if inserted, err = st.storage.attributeInsert(tx, attr); err != nil {
+			return err
+		}
+
+		if err := st.storage.entityVersionBump(tx, attr.EntityID(), nil); err != nil {
+			return err
+		}
+
+		return st.changeRecord(tx, &change)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	st.changePublish(change)
+
+	return inserted, nil
+}
+
+// attributeInsert inserts an attribute
+func (st *sqlStorage) attributeInsert(db txOrDB, attr Attribute) (*Attribute, error) {
+	return st.attributeInsertWithTransactionOrDB(db, attr)
 }
 
 func (st *Store) attributeInsertWithTransactionOrDB(db txOrDB, attr Attribute) (*Attribute, error) {
@@ -28,20 +69,17 @@ func (st *Store) attributeInsertWithTransactionOrDB(db txOrDB, attr Attribute) (
 		attr.SetUpdatedAt(time.Now())
 	}
 
-	q := goqu.Dialect(st.dbDriverName).Insert(st.attributeTableName)
+	q := st.dialect().Insert(st.attributeTableName).Prepared(true)
 	q = q.Rows(attr.ToMap())
-	sqlStr, _, _ := q.ToSQL()
+	sqlStr, params, errSql := q.ToSQL()
 
-	if st.GetDebug() {
-		log.Println(sqlStr)
+	if errSql != nil {
+		return nil, errSql
 	}
 
-	_, err := db.Exec(sqlStr)
+	_, err := st.sqlExec("AttributeInsert", db, true, sqlStr, params...)
 
 	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
 		return nil, err
 	}
 
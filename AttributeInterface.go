@@ -0,0 +1,31 @@
+package entitystore
+
+import "time"
+
+// AttributeInterface is the public API of an attribute, implemented by
+// *Attribute
+type AttributeInterface interface {
+	ID() string
+	EntityID() string
+	AttributeKey() string
+	AttributeValue() string
+	CreatedAt() time.Time
+	UpdatedAt() time.Time
+	ToMap() map[string]any
+
+	SetID(id string) *Attribute
+	SetEntityID(entityID string) *Attribute
+	SetAttributeKey(attributeKey string) *Attribute
+	SetAttributeValue(attributeValue string) *Attribute
+	SetCreatedAt(createdAt time.Time) *Attribute
+	SetUpdatedAt(updatedAt time.Time) *Attribute
+
+	GetFloat() (float64, error)
+	GetInt() (int64, error)
+	GetString() string
+	SetFloat(value float64) bool
+	SetInt(value int64) bool
+	SetString(value string) bool
+}
+
+var _ AttributeInterface = (*Attribute)(nil)
@@ -0,0 +1,219 @@
+package entitystore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// Value types inferred from the attribute values
+const (
+	ValueTypeInt    = "int"
+	ValueTypeFloat  = "float"
+	ValueTypeBool   = "bool"
+	ValueTypeJSON   = "json"
+	ValueTypeString = "string"
+)
+
+// attributeKeySampleSize is the number of values of an attribute key its
+// value type is inferred from
+const attributeKeySampleSize = 100
+
+// AttributeKeyInfo describes an attribute key of an entity type
+type AttributeKeyInfo struct {
+	// Key is the attribute key
+	Key string
+
+	// Count is the number of entities having the attribute
+	Count int64
+
+	// ValueType is the type of the values, one of the ValueType constants,
+	// inferred from a sample of the values. The values of different types
+	// are strings, except ints and floats which are floats
+	ValueType string
+
+	// List is true for the keys of the list and set attributes, Count is
+	// then the number of entities having values
+	List bool
+}
+
+// AttributeKeys returns the attribute keys of the entities of a type, with
+// the number of entities having them and the inferred type of their
+// values, ordered by key. The keys of the list and set attributes follow
+// the attribute keys of the same name. The trashed entities are not
+// counted
+func (st *Store) AttributeKeys(entityType string) ([]AttributeKeyInfo, error) {
+	if entityType == "" {
+		return nil, errors.New("entity type cannot be empty")
+	}
+
+	return st.storage.attributeKeys(entityType, attributeKeySampleSize)
+}
+
+// attributeKeys counts the attribute keys of AttributeKeys, inferring the
+// value types from up to sampleSize values
+func (st *sqlStorage) attributeKeys(entityType string, sampleSize int) ([]AttributeKeyInfo, error) {
+	keys, err := st.attributeKeysOf(st.attributeTableName, false, entityType, sampleSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := st.attributeKeysOf(st.attributeValueTableName, true, entityType, sampleSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys = append(keys, lists...)
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+
+	return keys, nil
+}
+
+// attributeKeysOf counts the attribute keys of the attribute table, or of
+// the table of the list and set values, and samples the values of all the
+// keys in a single query, numbering the values of each key
+func (st *sqlStorage) attributeKeysOf(tableName string, list bool, entityType string, sampleSize int) ([]AttributeKeyInfo, error) {
+	sqlStr, params, errSql := st.dialect().From(goqu.T(tableName).As("a")).Prepared(true).
+		Join(goqu.T(st.entityTableName).As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("a.entity_id")))).
+		Select(goqu.I("a.attribute_key").As("attribute_key"), goqu.L("COUNT(DISTINCT ?)", goqu.I("a.entity_id")).As("count")).
+		Where(goqu.I("e.entity_type").Eq(entityType)).
+		GroupBy(goqu.I("a.attribute_key")).
+		Order(goqu.I("a.attribute_key").Asc()).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	type keyCount struct {
+		Key   string `db:"attribute_key"`
+		Count int64  `db:"count"`
+	}
+
+	counts := []keyCount{}
+
+	if err := st.sqlSelect("AttributeKeys", st.db, false, &counts, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	if len(counts) == 0 {
+		return nil, nil
+	}
+
+	// The window function is written as a literal, the goqu dialects of
+	// SQLite and MySQL refuse window functions
+	valueOrder := goqu.L("?", goqu.I("a.id"))
+
+	if list {
+		valueOrder = goqu.L("?, ?", goqu.I("a.entity_id"), goqu.I("a.position"))
+	}
+
+	numbered := st.dialect().From(goqu.T(tableName).As("a")).
+		Join(goqu.T(st.entityTableName).As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("a.entity_id")))).
+		Select(
+			goqu.I("a.attribute_key").As("attribute_key"),
+			goqu.I("a.attribute_value").As("attribute_value"),
+			goqu.L("ROW_NUMBER() OVER (PARTITION BY ? ORDER BY ?)", goqu.I("a.attribute_key"), valueOrder).As("sample_number"),
+		).
+		Where(goqu.I("e.entity_type").Eq(entityType))
+
+	sqlStr, params, errSql = st.dialect().From(numbered.As("s")).Prepared(true).
+		Select(goqu.I("s.attribute_key"), goqu.I("s.attribute_value")).
+		Where(goqu.I("s.sample_number").Lte(sampleSize)).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	type keyValue struct {
+		Key   string         `db:"attribute_key"`
+		Value sql.NullString `db:"attribute_value"`
+	}
+
+	values := []keyValue{}
+
+	if err := st.sqlSelect("AttributeKeys", st.db, false, &values, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	samples := map[string][]string{}
+
+	for _, value := range values {
+		samples[value.Key] = append(samples[value.Key], value.Value.String)
+	}
+
+	keys := make([]AttributeKeyInfo, 0, len(counts))
+
+	for _, count := range counts {
+		keys = append(keys, AttributeKeyInfo{
+			Key:       count.Key,
+			Count:     count.Count,
+			ValueType: attributeValuesType(samples[count.Key]),
+			List:      list,
+		})
+	}
+
+	return keys, nil
+}
+
+// attributeValuesType infers the type of the values, ignoring the empty
+// values. Ints and floats make floats, other mixed types make strings
+func attributeValuesType(values []string) string {
+	valueType := ""
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		current := attributeValueType(value)
+
+		switch {
+		case valueType == "" || valueType == current:
+			valueType = current
+		case (valueType == ValueTypeInt || valueType == ValueTypeFloat) &&
+			(current == ValueTypeInt || current == ValueTypeFloat):
+			valueType = ValueTypeFloat
+		default:
+			return ValueTypeString
+		}
+	}
+
+	if valueType == "" {
+		return ValueTypeString
+	}
+
+	return valueType
+}
+
+// attributeValueType infers the type of a value
+func attributeValueType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ValueTypeInt
+	}
+
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return ValueTypeFloat
+	}
+
+	if value == "true" || value == "false" {
+		return ValueTypeBool
+	}
+
+	if (strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[")) && json.Valid([]byte(value)) {
+		return ValueTypeJSON
+	}
+
+	return ValueTypeString
+}
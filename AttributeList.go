@@ -1,40 +1,25 @@
 package entitystore
 
-import (
-	"context"
-	"database/sql"
-	"log"
-
-	"github.com/georgysavva/scany/sqlscan"
-)
-
 // AttributeList lists attributes
 func (st *Store) AttributeList(options AttributeQueryOptions) (attributeList []Attribute, err error) {
+	return st.storage.attributeList(options, false)
+}
+
+// attributeList lists the attributes, with the statement cached when
+// useCache is set
+func (st *sqlStorage) attributeList(options AttributeQueryOptions, useCache bool) (attributeList []Attribute, err error) {
 	q := st.AttributeQuery(options)
 
-	sqlStr, _, errSql := q.ToSQL()
+	sqlStr, params, errSql := q.ToSQL()
 
 	if errSql != nil {
 		return attributeList, errSql
 	}
 
-	if st.GetDebug() {
-		log.Println(sqlStr)
-	}
-
 	attributeMaps := []map[string]string{}
-	errScan := sqlscan.Select(context.Background(), st.db, &attributeMaps, sqlStr)
+	errScan := st.sqlSelect("AttributeList", st.db, useCache, &attributeMaps, sqlStr, params...)
 	if errScan != nil {
-		if errScan == sql.ErrNoRows {
-			// sqlscan does not use this anymore
-			return nil, errScan
-		}
-
-		if sqlscan.NotFound(errScan) {
-			return nil, nil
-		}
-
-		return nil, err
+		return nil, errScan
 	}
 
 	for i := 0; i < len(attributeMaps); i++ {
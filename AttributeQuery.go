@@ -1,11 +1,16 @@
 package entitystore
 
-import "github.com/doug-martin/goqu/v9"
+import (
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+)
 
 type AttributeQueryOptions struct {
 	ID           string
 	IDs          []string
 	EntityID     string
+	EntityIDs    []string
 	AttributeKey string
 	Limit        uint64
 	Offset       uint64
@@ -14,8 +19,18 @@ type AttributeQueryOptions struct {
 	CountOnly    bool
 }
 
+// attributeSortableColumns are the columns attribute queries can be sorted by
+var attributeSortableColumns = map[string]bool{
+	"id":              true,
+	"entity_id":       true,
+	"attribute_key":   true,
+	"attribute_value": true,
+	"created_at":      true,
+	"updated_at":      true,
+}
+
 func (st *Store) AttributeQuery(options AttributeQueryOptions) *goqu.SelectDataset {
-	q := goqu.Dialect(st.dbDriverName).From(st.attributeTableName)
+	q := st.dialect().From(st.attributeTableName).Prepared(true)
 
 	if len(options.IDs) > 0 {
 		q = q.Where(goqu.C("id").In(options.IDs))
@@ -36,6 +51,10 @@ func (st *Store) AttributeQuery(options AttributeQueryOptions) *goqu.SelectDatas
 		sortByColumn = options.SortBy
 	}
 
+	if !attributeSortableColumns[sortByColumn] {
+		return q.SetError(errors.New("attribute sort column not supported: " + sortByColumn))
+	}
+
 	if sortOrder == "asc" {
 		q = q.Order(goqu.I(sortByColumn).Asc())
 	} else {
@@ -46,6 +65,10 @@ func (st *Store) AttributeQuery(options AttributeQueryOptions) *goqu.SelectDatas
 		q = q.Where(goqu.C("entity_id").Eq(options.EntityID))
 	}
 
+	if len(options.EntityIDs) > 0 {
+		q = q.Where(goqu.C("entity_id").In(options.EntityIDs))
+	}
+
 	if options.AttributeKey != "" {
 		q = q.Where(goqu.C("attribute_key").Eq(options.AttributeKey))
 	}
@@ -0,0 +1,21 @@
+package entitystore
+
+// AttributeRemoveFromSet removes all the occurrences of the values from
+// the set or list attribute of an entity. Entity hooks are not run
+func (st *Store) AttributeRemoveFromSet(entityID string, attributeKey string, values ...string) error {
+	return st.attributeValuesChange("AttributeRemoveFromSet", entityID, attributeKey, func(current []string) ([]string, bool) {
+		removed := map[string]bool{}
+		for _, value := range values {
+			removed[value] = true
+		}
+
+		kept := []string{}
+		for _, value := range current {
+			if !removed[value] {
+				kept = append(kept, value)
+			}
+		}
+
+		return kept, len(kept) != len(current)
+	})
+}
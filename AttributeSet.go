@@ -1,145 +1,111 @@
 package entitystore
 
 import (
-	"log"
+	"database/sql"
 	"time"
 
-	"github.com/doug-martin/goqu/v9"
 	"github.com/gouniverse/uid"
 )
 
 // AttributesSet upserts an entity attribute
 func (st *Store) AttributesSet(entityID string, attributes map[string]string) error {
-	tx, err := st.db.Begin()
-
-	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-		return err
-	}
+	return st.attributesSet(entityID, attributes, nil)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = tx.Rollback()
-			if st.GetDebug() {
-				log.Println(err)
-			}
-		}
-	}()
+// attributesSet upserts the attributes, incrementing the version of the
+// entity. With ifVersion the entity must be at that version
+func (st *Store) attributesSet(entityID string, attributes map[string]string, ifVersion *int64) error {
+	event := &EntityHookEvent{Attributes: map[string]string{}}
 
 	for k, v := range attributes {
-		attr, err := st.AttributeFind(entityID, k)
-
-		if err != nil {
-			if st.GetDebug() {
-				log.Println(err)
-			}
-
-			err = tx.Rollback()
+		event.Attributes[k] = v
+	}
 
-			if st.GetDebug() {
-				log.Println(err)
-			}
+	// The entity type is only looked up when needed, for the hooks
+	// registered per entity type, the outbox and the subscriptions
+	if st.outboxEnabled || st.subscribed() || st.entityHooksRegistered(EntityBeforeAttributeSet, EntityAfterAttributeSet) {
+		entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
 
+		if err != nil {
 			return err
 		}
 
-		if attr == nil {
-			attr = st.NewAttribute(NewAttributeOptions{ID: uid.HumanUid(), EntityID: entityID, AttributeKey: k, CreatedAt: time.Now(), UpdatedAt: time.Now()})
-			attr.SetString(v)
-
-			q := goqu.Dialect(st.dbDriverName).Insert(st.attributeTableName)
-			q = q.Rows(attr.ToMap())
-			sqlStr, _, err := q.ToSQL()
-
-			if err != nil {
-				if st.GetDebug() {
-					log.Println(err)
-				}
-
-				err = tx.Rollback()
-
-				if st.GetDebug() {
-					log.Println(err)
-				}
-
-				return err
-			}
-
-			if st.GetDebug() {
-				log.Println(sqlStr)
-			}
+		event.Entity = entity
+	}
 
-			_, err = tx.Exec(sqlStr)
+	change := ChangeEvent{
+		Operation: ChangeAttributeSet,
+		EntityID:  entityID,
+	}
 
-			if err != nil {
-				log.Println(err)
-				err = tx.Rollback()
+	if event.Entity != nil {
+		change.EntityType = event.Entity.Type()
+	}
 
-				if st.GetDebug() {
-					log.Println(err)
-				}
+	err := st.withTransaction("AttributesSet", func(tx *sql.Tx) error {
+		event.Tx = tx
 
+		if event.Entity != nil {
+			if err := st.entityHooksRun(EntityBeforeAttributeSet, event); err != nil {
 				return err
 			}
-
 		}
 
-		attr.SetString(v)
-		attr.SetUpdatedAt(time.Now())
-
-		q := goqu.Dialect(st.dbDriverName).Update(st.attributeTableName)
-		q = q.Where(goqu.C("id").Eq(attr.ID))
-		q = q.Set(attr.ToMap())
-
-		sqlStr, _, err := q.ToSQL()
-
-		if err != nil {
-			if st.GetDebug() {
-				log.Println(err)
-			}
+		if err := st.storage.entityVersionBump(tx, entityID, ifVersion); err != nil {
+			return err
+		}
 
-			err = tx.Rollback()
+		if err := st.storage.attributesSet(tx, entityID, event.Attributes); err != nil {
+			return err
+		}
 
-			if st.GetDebug() {
-				log.Println(err)
-			}
+		change.Attributes = event.Attributes
 
+		if err := st.changeRecord(tx, &change); err != nil {
 			return err
 		}
 
-		if st.GetDebug() {
-			log.Println(sqlStr)
+		if event.Entity != nil {
+			return st.entityHooksRun(EntityAfterAttributeSet, event)
 		}
 
-		_, err = tx.Exec(sqlStr)
+		return nil
+	})
 
-		if err != nil {
-			if st.GetDebug() {
-				log.Println(err)
-			}
+	st.cacheInvalidate(entityID)
 
-			err = tx.Rollback()
+	if err != nil {
+		return err
+	}
 
-			if st.GetDebug() {
-				log.Println(err)
-			}
+	st.changePublish(change)
 
+	return nil
+}
+
+// attributesSet upserts the attributes of an entity
+func (st *sqlStorage) attributesSet(tx *sql.Tx, entityID string, attributes map[string]string) error {
+	for k, v := range attributes {
+		attr, err := st.AttributeFind(entityID, k, FindOptions{CacheBypass: true})
+
+		if err != nil {
 			return err
 		}
-	}
 
-	err = tx.Commit()
+		if attr == nil {
+			attr = st.NewAttribute(NewAttributeOptions{ID: uid.HumanUid(), EntityID: entityID, AttributeKey: k, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+			attr.SetString(v)
 
-	if err != nil {
-		err = tx.Rollback()
+			_, err = st.attributeInsertWithTransactionOrDB(tx, *attr)
+		} else {
+			attr.SetString(v)
 
-		if st.GetDebug() {
-			log.Println(err)
+			err = st.attributeUpdateWithTransactionOrDB(tx, *attr)
 		}
 
-		return err
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -40,3 +40,40 @@ func TestAttributeString(t *testing.T) {
 		t.Fatal("Attribute value incorrect", "must be 'world'", "found", attr.GetString())
 	}
 }
+
+func TestAttributeStringUpdate(t *testing.T) {
+	db := InitDB("test_attribute_string_update.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err := store.AttributeSetString("default", "hello", "world"); err != nil {
+		t.Fatalf("Attribute could not be created: " + err.Error())
+	}
+
+	if err := store.AttributeSetString("default", "hello", "again"); err != nil {
+		t.Fatalf("Attribute could not be updated: " + err.Error())
+	}
+
+	attrs, err := store.EntityAttributeList("default")
+
+	if err != nil {
+		t.Fatalf("Attributes could not be retrieved: " + err.Error())
+	}
+
+	if len(attrs) != 1 {
+		t.Fatal("Attribute count incorrect", "must be 1", "found", len(attrs))
+	}
+
+	if attrs[0].GetString() != "again" {
+		t.Fatal("Attribute value incorrect", "must be 'again'", "found", attrs[0].GetString())
+	}
+}
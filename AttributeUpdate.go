@@ -1,7 +1,7 @@
 package entitystore
 
 import (
-	"log"
+	"database/sql"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
@@ -9,25 +9,63 @@ import (
 
 // AttributeUpdate updates an attribute
 func (st *Store) AttributeUpdate(attr Attribute) error {
+	defer st.cacheInvalidate(attr.EntityID())
+
+	entityType, err := st.changeEntityType(attr.EntityID())
+
+	if err != nil {
+		return err
+	}
+
+	change := ChangeEvent{
+		Operation:  ChangeAttributeSet,
+		EntityID:   attr.EntityID(),
+		EntityType: entityType,
+		Attributes: map[string]string{attr.AttributeKey(): attr.AttributeValue()},
+	}
+
+	err = st.withTransaction("AttributeUpdate", func(tx *sql.Tx) error {
+		if err := st.storage.attributeUpdate(tx, attr); err != nil {
+			return err
+		}
+
+		if err := st.storage.entityVersionBump(tx, attr.EntityID(), nil); err != nil {
+			return err
+		}
+
+		return st.changeRecord(tx, &change)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	st.changePublish(change)
+
+	return nil
+}
+
+// attributeUpdate updates an attribute
+func (st *sqlStorage) attributeUpdate(db txOrDB, attr Attribute) error {
+	return st.attributeUpdateWithTransactionOrDB(db, attr)
+}
+
+func (st *Store) attributeUpdateWithTransactionOrDB(db txOrDB, attr Attribute) error {
 	attr.SetUpdatedAt(time.Now())
 
-	q := goqu.Dialect(st.dbDriverName).Update(st.attributeTableName)
-	q = q.Where(goqu.C("id").Eq(attr.ID))
+	q := st.dialect().Update(st.attributeTableName).Prepared(true)
+	q = q.Where(goqu.C("id").Eq(attr.ID()))
 	q = q.Set(attr.ToMap())
 
-	sqlStr, _, _ := q.ToSQL()
+	sqlStr, params, errSql := q.ToSQL()
 
-	if st.GetDebug() {
-		log.Println(sqlStr)
+	if errSql != nil {
+		return errSql
 	}
 
-	_, err := st.db.Exec(sqlStr)
+	_, err := st.sqlExec("AttributeUpdate", db, true, sqlStr, params...)
 
 	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-
 		return err
 	}
 
@@ -0,0 +1,17 @@
+package entitystore
+
+import "errors"
+
+// AttributeValues returns the values of the list or set attribute of an
+// entity, in order. Empty if the attribute has no values
+func (st *Store) AttributeValues(entityID string, attributeKey string) ([]string, error) {
+	if entityID == "" {
+		return nil, errors.New("entity id cannot be empty")
+	}
+
+	if attributeKey == "" {
+		return nil, errors.New("attribute key cannot be empty")
+	}
+
+	return st.storage.attributeValues(st.db, entityID, attributeKey)
+}
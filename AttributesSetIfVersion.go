@@ -0,0 +1,9 @@
+package entitystore
+
+// AttributesSetIfVersion upserts the attributes of an entity only if it is
+// still at the version it was read at, i.e. not saved concurrently since.
+// Otherwise a *VersionConflictError, matching ErrVersionConflict, is
+// returned and no attribute is set
+func (st *Store) AttributesSetIfVersion(entityID string, version int64, attributes map[string]string) error {
+	return st.attributesSet(entityID, attributes, &version)
+}
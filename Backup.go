@@ -0,0 +1,128 @@
+package entitystore
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// BackupFormat identifies the backups of an entity store
+const BackupFormat = "entitystore-backup"
+
+// BackupFormatVersion is the version of the backup format written by
+// Backup. Restore reads the backups of this version and older
+const BackupFormatVersion = 1
+
+// BackupManifest describes a backup. It is the first line of the backup,
+// the counts of the rows are written on the last line, once known
+type BackupManifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	Driver    string    `json:"driver"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Tables are the names of the tables in the backup: entity,
+	// attribute, entity_trash, attribute_trash, attribute_value,
+	// attribute_value_trash and handle
+	Tables []string `json:"tables"`
+
+	// Counts are the numbers of rows, by table name
+	Counts map[string]int64 `json:"counts,omitempty"`
+}
+
+// backupLine is a line of a backup: the manifest, a row of a table, or
+// the counts of the rows ending the backup
+type backupLine struct {
+	Manifest *BackupManifest  `json:"manifest,omitempty"`
+	Table    string           `json:"table,omitempty"`
+	Row      json.RawMessage  `json:"row,omitempty"`
+	Counts   map[string]int64 `json:"counts,omitempty"`
+}
+
+// Backup writes a snapshot of the store: the entities, the attributes,
+// the trash bin, the values of the list and set attributes and the
+// handles. The backup is gzipped NDJSON, independent of the database
+// engine, and is loaded with Restore into a store of any engine. The rows
+// are read in one read only transaction, from a snapshot of the store. On
+// SQL Server the writes wait until the backup ends. The outbox and the
+// locks are not backed up
+func (st *Store) Backup(writer io.Writer) (*BackupManifest, error) {
+	tables := st.backupTables()
+
+	manifest := &BackupManifest{
+		Format:    BackupFormat,
+		Version:   BackupFormatVersion,
+		Driver:    st.dbDriverName,
+		CreatedAt: time.Now().UTC(),
+		Tables:    []string{},
+	}
+
+	for _, table := range tables {
+		manifest.Tables = append(manifest.Tables, table.name)
+	}
+
+	gzipWriter := gzip.NewWriter(writer)
+	encoder := json.NewEncoder(gzipWriter)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(backupLine{Manifest: manifest}); err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{}
+
+	err := st.storage.snapshot("Backup", func(db txOrDB) error {
+		for _, table := range tables {
+			counts[table.name] = 0
+
+			var after map[string]any
+
+			for {
+				rows, err := st.storage.backupRows(db, table, after)
+
+				if err != nil {
+					return err
+				}
+
+				for _, row := range rows {
+					data, err := json.Marshal(row)
+
+					if err != nil {
+						return err
+					}
+
+					if err := encoder.Encode(backupLine{Table: table.name, Row: data}); err != nil {
+						return err
+					}
+				}
+
+				counts[table.name] += int64(len(rows))
+
+				if len(rows) < backupPageSize {
+					break
+				}
+
+				after = rows[len(rows)-1]
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Encode(backupLine{Counts: counts}); err != nil {
+		return nil, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	manifest.Counts = counts
+
+	return manifest, nil
+}
@@ -0,0 +1,326 @@
+package entitystore
+
+import (
+	"bytes"
+	"database/sql"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBackupRestore(t *testing.T) {
+	db := InitDB("test_backup.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	post, err := store.EntityCreateWithHandle("post", "hello", map[string]string{"title": "Hello"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if err := post.AppendToList("tags", "go", "db", "go"); err != nil {
+		t.Fatalf("Values could not be appended: " + err.Error())
+	}
+
+	if err := store.EntitySetHandle(post.ID(), "hello-world"); err != nil {
+		t.Fatalf("Handle could not be changed: " + err.Error())
+	}
+
+	draft, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Draft"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if _, err := store.EntityTrash(draft.ID()); err != nil {
+		t.Fatalf("Entity could not be trashed: " + err.Error())
+	}
+
+	var backup bytes.Buffer
+	manifest, err := store.Backup(&backup)
+
+	if err != nil {
+		t.Fatalf("Store could not be backed up: " + err.Error())
+	}
+
+	if manifest.Counts["entity"] != 1 || manifest.Counts["entity_trash"] != 1 || manifest.Counts["attribute_value"] != 3 || manifest.Counts["handle"] != 2 {
+		t.Fatal("Backup counts incorrect", manifest.Counts)
+	}
+
+	memory, err := NewStore(NewStoreOptions{Backend: BackendMemory})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	if _, err := memory.Restore(bytes.NewReader(backup.Bytes())); err != nil {
+		t.Fatalf("Backup could not be restored: " + err.Error())
+	}
+
+	var memoryBackup bytes.Buffer
+	if _, err := memory.Backup(&memoryBackup); err != nil {
+		t.Fatalf("Store could not be backed up: " + err.Error())
+	}
+
+	restored, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_backup_restore.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	for _, target := range []*Store{memory, restored} {
+		if target == restored {
+			if _, err := restored.Restore(bytes.NewReader(memoryBackup.Bytes())); err != nil {
+				t.Fatalf("Backup could not be restored: " + err.Error())
+			}
+		}
+
+		entity, err := target.EntityFindByHandle("post", "hello")
+
+		if err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if entity == nil || entity.ID() != post.ID() || entity.Handle() != "hello-world" {
+			t.Fatal("Entity must be found by its previous handle", entity)
+		}
+
+		if !entity.CreatedAt().Equal(post.CreatedAt()) {
+			t.Fatal("Created at incorrect", "must be", post.CreatedAt(), "found", entity.CreatedAt())
+		}
+
+		if title, _ := entity.GetString("title", ""); title != "Hello" {
+			t.Fatal("Attribute incorrect", "must be Hello", "found", title)
+		}
+
+		tags, err := entity.GetList("tags")
+
+		if err != nil || len(tags) != 3 || tags[0] != "go" || tags[1] != "db" {
+			t.Fatal("List incorrect", tags, err)
+		}
+
+		isRestored, err := target.EntityRestore(draft.ID())
+
+		if err != nil || !isRestored {
+			t.Fatal("Trashed entity must be restored", err)
+		}
+
+		if title, _ := target.AttributeFind(draft.ID(), "title"); title == nil || title.AttributeValue() != "Draft" {
+			t.Fatal("Trashed attribute must be restored")
+		}
+	}
+}
+
+func TestBackupRestoreTruncated(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{Backend: BackendMemory})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	if _, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Hello"}); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	var backup bytes.Buffer
+	if _, err := store.Backup(&backup); err != nil {
+		t.Fatalf("Store could not be backed up: " + err.Error())
+	}
+
+	restored, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_backup_truncated.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	if _, err := restored.Restore(bytes.NewReader(backup.Bytes()[:backup.Len()-20])); err == nil {
+		t.Fatalf("Truncated backup must fail")
+	}
+
+	if count, _ := restored.EntityCount(EntityQueryOptions{}); count != 0 {
+		t.Fatal("Failed restore must be rolled back", "found", count)
+	}
+
+	if _, err := restored.Restore(bytes.NewReader(backup.Bytes())); err != nil {
+		t.Fatalf("Backup could not be restored: " + err.Error())
+	}
+
+	if _, err := restored.Restore(bytes.NewReader(backup.Bytes())); err == nil {
+		t.Fatalf("Restore into a store which is not empty must fail")
+	}
+}
+
+func TestBackupPages(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_backup_pages.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	post, err := store.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	values := []string{}
+	for i := 0; i < backupPageSize*2+1; i++ {
+		values = append(values, strconv.Itoa(i))
+	}
+
+	if err := post.AppendToList("views", values...); err != nil {
+		t.Fatalf("Values could not be appended: " + err.Error())
+	}
+
+	for i := 0; i < backupPageSize; i++ {
+		if _, err := store.EntityCreate("page"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+	}
+
+	var backup bytes.Buffer
+	manifest, err := store.Backup(&backup)
+
+	if err != nil {
+		t.Fatalf("Store could not be backed up: " + err.Error())
+	}
+
+	if manifest.Counts["entity"] != backupPageSize+1 || manifest.Counts["attribute_value"] != int64(len(values)) {
+		t.Fatal("Backup counts incorrect", manifest.Counts)
+	}
+
+	memory, err := NewStore(NewStoreOptions{Backend: BackendMemory})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	if _, err := memory.Restore(bytes.NewReader(backup.Bytes())); err != nil {
+		t.Fatalf("Backup could not be restored: " + err.Error())
+	}
+
+	restored, err := memory.EntityFindByID(post.ID())
+
+	if err != nil || restored == nil {
+		t.Fatal("Entity must be restored", err)
+	}
+
+	list, err := restored.GetList("views")
+
+	if err != nil || strings.Join(list, ",") != strings.Join(values, ",") {
+		t.Fatal("List incorrect", len(list), err)
+	}
+
+	var memoryBackup bytes.Buffer
+	manifest, err = memory.Backup(&memoryBackup)
+
+	if err != nil {
+		t.Fatalf("Store could not be backed up: " + err.Error())
+	}
+
+	if manifest.Counts["entity"] != backupPageSize+1 || manifest.Counts["attribute_value"] != int64(len(values)) {
+		t.Fatal("Backup counts incorrect", manifest.Counts)
+	}
+}
+
+func TestRestoreHandles(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{Backend: BackendMemory})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	var backup bytes.Buffer
+	if _, err := store.Backup(&backup); err != nil {
+		t.Fatalf("Store could not be backed up: " + err.Error())
+	}
+
+	restored, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_restore_handles.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	// A handle left by an entity which no longer exists
+	err = restored.withTransaction("Test", func(tx *sql.Tx) error {
+		return (&sqlStorage{restored}).entityHandleReserve(tx, "page", "about", "gone")
+	})
+
+	if err != nil {
+		t.Fatalf("Handle could not be reserved: " + err.Error())
+	}
+
+	if _, err := restored.Restore(bytes.NewReader(backup.Bytes())); err == nil {
+		t.Fatalf("Restore into a store with handles must fail")
+	}
+}
+
+func TestBackupInsertParams(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_backup_insert_params.db"),
+		DbDriverName:       "mssql",
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	widest := backupTable{}
+	for _, table := range store.backupTables() {
+		if len(table.columns) > len(widest.columns) {
+			widest = table
+		}
+	}
+
+	rows := []map[string]any{}
+	for i := 0; i < widest.insertRows(); i++ {
+		row := map[string]any{}
+		for _, column := range widest.columns {
+			row[column] = strconv.Itoa(i)
+		}
+		rows = append(rows, row)
+	}
+
+	_, params, err := store.backupRowsInsertSQL(widest, rows)
+
+	if err != nil {
+		t.Fatalf("SQL could not be built: " + err.Error())
+	}
+
+	// SQL Server allows 2100 parameters in a statement
+	if len(params) != len(rows)*len(widest.columns) || len(params) > 2100 {
+		t.Fatal("Parameters of "+widest.name+" incorrect", "must be at most 2100", "found", len(params))
+	}
+}
@@ -0,0 +1,41 @@
+package entitystore
+
+// Cache is a read-through cache in front of EntityFindByID,
+// EntityFindByHandle and AttributeFind. The store keeps its own values in
+// it and removes them on every write, so a cache is used by one process.
+// NewLRUCache returns the built in implementation
+type Cache interface {
+	// Get returns the value of the key, and whether it was found
+	Get(key string) (any, bool)
+
+	// Set stores the value of the key
+	Set(key string, value any)
+
+	// Delete removes the key
+	Delete(key string)
+
+	// Clear removes all the keys
+	Clear()
+}
+
+// CacheStats are the cache lookups of a store since it was created
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// FindOptions define the options of a find
+type FindOptions struct {
+	// CacheBypass reads from the database, neither reading nor filling
+	// the cache
+	CacheBypass bool
+}
+
+// CacheStats returns the hits and misses of the cache. Both are zero
+// without a Cache
+func (st *Store) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   st.cacheHits.Load(),
+		Misses: st.cacheMisses.Load(),
+	}
+}
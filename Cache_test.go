@@ -0,0 +1,243 @@
+package entitystore
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_cache.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		Cache:              NewLRUCache(100, 0),
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	entity, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Hello"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := store.EntityFindByID(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if _, err := store.AttributeFind(entity.ID(), "title"); err != nil {
+			t.Fatalf("Attribute could not be found: " + err.Error())
+		}
+	}
+
+	if stats := store.CacheStats(); stats.Hits != 2 || stats.Misses != 2 {
+		t.Fatalf("Expected 2 hits and 2 misses, found %+v", stats)
+	}
+
+	// A write invalidates the cached values
+	if err := entity.SetString("title", "Hello world"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	if title, _ := entity.GetString("title", ""); title != "Hello world" {
+		t.Fatalf("Expected the new title, found %s", title)
+	}
+
+	// A bypass is not counted
+	if _, err := store.AttributeFind(entity.ID(), "title", FindOptions{CacheBypass: true}); err != nil {
+		t.Fatalf("Attribute could not be found: " + err.Error())
+	}
+
+	if stats := store.CacheStats(); stats.Hits != 2 || stats.Misses != 3 {
+		t.Fatalf("Expected 2 hits and 3 misses, found %+v", stats)
+	}
+
+	if _, err := store.EntityTrash(entity.ID()); err != nil {
+		t.Fatalf("Entity could not be trashed: " + err.Error())
+	}
+
+	if found, _ := store.EntityFindByID(entity.ID()); found != nil {
+		t.Fatalf("Trashed entity must not be found")
+	}
+
+	if attr, _ := store.AttributeFind(entity.ID(), "title"); attr != nil {
+		t.Fatalf("Attribute of the trashed entity must not be found")
+	}
+}
+
+func TestCacheHandle(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_cache_handle.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		Cache:              NewLRUCache(100, 0),
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	entity, err := store.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	entity.SetHandle("hello")
+	if _, err := store.EntityUpdate(*entity); err != nil {
+		t.Fatalf("Entity could not be updated: " + err.Error())
+	}
+
+	if found, _ := store.EntityFindByHandle("post", "hello"); found == nil || found.ID() != entity.ID() {
+		t.Fatalf("Entity must be found by handle")
+	}
+
+	if found, _ := store.EntityFindByHandle("page", "hello"); found != nil {
+		t.Fatalf("Entity must not be found by handle of another type")
+	}
+
+	entity.SetHandle("hello-world")
+	if _, err := store.EntityUpdate(*entity); err != nil {
+		t.Fatalf("Entity could not be updated: " + err.Error())
+	}
+
+	if found, _ := store.EntityFindByHandle("post", "hello"); found == nil || found.Handle() != "hello-world" {
+		t.Fatalf("Entity must be found by the old handle, with the new handle")
+	}
+
+	if found, _ := store.EntityFindByHandle("post", "hello-world"); found == nil {
+		t.Fatalf("Entity must be found by the new handle")
+	}
+
+	if _, err := store.EntityDelete(entity.ID()); err != nil {
+		t.Fatalf("Entity could not be deleted: " + err.Error())
+	}
+
+	if found, _ := store.EntityFindByHandle("post", "hello"); found != nil {
+		t.Fatalf("Deleted entity must not be found by the cached handle")
+	}
+}
+
+// cacheSetHook is a Cache running a hook before each set
+type cacheSetHook struct {
+	*LRUCache
+	beforeSet func(key string)
+}
+
+func (c *cacheSetHook) Set(key string, value any) {
+	c.beforeSet(key)
+	c.LRUCache.Set(key, value)
+}
+
+func TestCacheReadThroughRacingWrite(t *testing.T) {
+	var once sync.Once
+	var entity *Entity
+	written := make(chan error, 1)
+
+	// The first value read is about to be cached while the entity is
+	// written by another goroutine
+	cache := &cacheSetHook{LRUCache: NewLRUCache(100, 0)}
+	cache.beforeSet = func(key string) {
+		once.Do(func() {
+			go func() {
+				written <- entity.SetString("title", "New")
+			}()
+
+			select {
+			case err := <-written:
+				written <- err
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_cache_race.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		Cache:              cache,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	entity, err = store.EntityCreateWithAttributes("post", map[string]string{"title": "Old"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if _, err := store.AttributeFind(entity.ID(), "title"); err != nil {
+		t.Fatalf("Attribute could not be found: " + err.Error())
+	}
+
+	if err := <-written; err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	if title, _ := entity.GetString("title", ""); title != "New" {
+		t.Fatalf("Expected the written title, found %s", title)
+	}
+}
+
+func TestCacheClearShared(t *testing.T) {
+	db := InitDB("test_cache_clear_shared.db")
+	cache := NewLRUCache(100, 0)
+
+	newStore := func(prefix string) *Store {
+		store, err := NewStore(NewStoreOptions{
+			DB:                 db,
+			EntityTableName:    prefix + "_entity",
+			AttributeTableName: prefix + "_attribute",
+			AutomigrateEnabled: true,
+			Cache:              cache,
+		})
+
+		if err != nil {
+			t.Fatalf("Store could not be created: " + err.Error())
+		}
+
+		return store
+	}
+
+	cms := newStore("cms")
+	shop := newStore("shop")
+
+	post, err := cms.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	product, err := shop.EntityCreate("product")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	for i := 0; i < 2; i++ {
+		cms.EntityFindByID(post.ID())
+		shop.EntityFindByID(product.ID())
+	}
+
+	cms.cacheClear()
+
+	cms.EntityFindByID(post.ID())
+	shop.EntityFindByID(product.ID())
+
+	if stats := cms.CacheStats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Fatalf("Expected the cleared store to miss, found %+v", stats)
+	}
+
+	if stats := shop.CacheStats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("Expected the other store to hit, found %+v", stats)
+	}
+}
@@ -0,0 +1,31 @@
+package entitystore
+
+import "time"
+
+// Change operations of the change events
+const (
+	ChangeCreate       = "create"
+	ChangeUpdate       = "update"
+	ChangeAttributeSet = "attribute_set"
+	ChangeDelete       = "delete"
+	ChangeTrash        = "trash"
+	ChangeRestore      = "restore"
+
+	// ChangeAttributeValues is a change of the values of a list or set
+	// attribute. The attribute holds the new values as a JSON array
+	ChangeAttributeValues = "attribute_values"
+)
+
+// ChangeEvent describes a mutation of an entity
+type ChangeEvent struct {
+	// ID is the position of the event in the change feed, increasing. It is
+	// zero in the events received by a Subscription
+	ID         int64
+	Operation  string
+	EntityID   string
+	EntityType string
+
+	// Attributes are the attributes created with the entity or set
+	Attributes map[string]string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,185 @@
+package entitystore
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/georgysavva/scany/sqlscan"
+	"github.com/golang-module/carbon/v2"
+)
+
+// ChangeFeed is the durable feed of the change events recorded in the
+// outbox, read by a named consumer. The position of the consumer is
+// persisted by Ack, so each consumer receives every event in order, at
+// least once, also across restarts
+type ChangeFeed struct {
+	st       *Store
+	consumer string
+}
+
+// ChangeFeed returns the change feed of the consumer. A new consumer
+// starts at the oldest event kept in the outbox. Requires OutboxEnabled
+func (st *Store) ChangeFeed(consumer string) (*ChangeFeed, error) {
+	if !st.outboxEnabled {
+		return nil, errors.New("entity store: outbox is not enabled")
+	}
+
+	if consumer == "" {
+		return nil, errors.New("entity store: consumer is required")
+	}
+
+	if err := st.storage.outboxCursorCreate(consumer); err != nil {
+		return nil, err
+	}
+
+	return &ChangeFeed{st: st, consumer: consumer}, nil
+}
+
+// outboxCursorCreate creates the cursor of the consumer at position 0,
+// unless it exists
+func (st *sqlStorage) outboxCursorCreate(consumer string) error {
+	if _, err := st.outboxCursor(consumer); err == nil {
+		return nil
+	} else if !sqlscan.NotFound(err) {
+		return err
+	}
+
+	q := st.dialect().Insert(st.outboxCursorTableName()).Prepared(true)
+	q = q.Rows(goqu.Record{
+		"consumer":   consumer,
+		"position":   0,
+		"updated_at": time.Now(),
+	})
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if _, err := st.sqlExec("ChangeFeed", st.db, false, sqlStr, params...); err != nil {
+		// Another instance may have created the cursor in the meantime
+		if _, errPosition := st.outboxCursor(consumer); errPosition != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Consumer returns the name of the consumer of the feed
+func (f *ChangeFeed) Consumer() string {
+	return f.consumer
+}
+
+// Position returns the ID of the last event acknowledged by the consumer
+func (f *ChangeFeed) Position() (int64, error) {
+	return f.st.storage.outboxCursor(f.consumer)
+}
+
+// outboxCursor returns the position of the consumer, failing when it has
+// no cursor
+func (st *sqlStorage) outboxCursor(consumer string) (int64, error) {
+	sqlStr, params, errSql := st.dialect().From(st.outboxCursorTableName()).Prepared(true).
+		Select("position").
+		Where(goqu.C("consumer").Eq(consumer)).
+		ToSQL()
+
+	if errSql != nil {
+		return 0, errSql
+	}
+
+	var position int64
+	err := st.sqlGet("ChangeFeed", st.db, false, &position, sqlStr, params...)
+
+	return position, err
+}
+
+// Next returns up to limit events after the position of the consumer, in
+// order. The same events are returned until they are acknowledged
+func (f *ChangeFeed) Next(limit uint64) ([]ChangeEvent, error) {
+	if limit == 0 {
+		limit = 100
+	}
+
+	position, err := f.Position()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f.st.storage.outboxList(position, limit)
+}
+
+// outboxList returns up to limit events after the position, in order
+func (st *sqlStorage) outboxList(position int64, limit uint64) ([]ChangeEvent, error) {
+	sqlStr, params, errSql := st.dialect().From(st.outboxTableName).Prepared(true).
+		Select("id", "operation", "entity_id", "entity_type", "attributes", "created_at").
+		Where(goqu.C("id").Gt(position)).
+		Order(goqu.C("id").Asc()).
+		Limit(uint(limit)).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	eventMaps := []map[string]string{}
+	if err := st.sqlSelect("ChangeFeed", st.db, false, &eventMaps, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	events := []ChangeEvent{}
+
+	for _, eventMap := range eventMaps {
+		id, err := strconv.ParseInt(eventMap["id"], 10, 64)
+
+		if err != nil {
+			return nil, err
+		}
+
+		attributes := map[string]string{}
+
+		if eventMap["attributes"] != "" {
+			if err := json.Unmarshal([]byte(eventMap["attributes"]), &attributes); err != nil {
+				return nil, err
+			}
+		}
+
+		events = append(events, ChangeEvent{
+			ID:         id,
+			Operation:  eventMap["operation"],
+			EntityID:   eventMap["entity_id"],
+			EntityType: eventMap["entity_type"],
+			Attributes: attributes,
+			CreatedAt:  carbon.Parse(eventMap["created_at"], carbon.UTC).ToStdTime(),
+		})
+	}
+
+	return events, nil
+}
+
+// Ack acknowledges the events up to and including the event with the ID,
+// moving the position of the consumer forward. Acknowledging an event
+// before the position does nothing
+func (f *ChangeFeed) Ack(eventID int64) error {
+	return f.st.storage.outboxAck(f.consumer, eventID)
+}
+
+// outboxAck moves the position of the consumer forward to the event
+func (st *sqlStorage) outboxAck(consumer string, eventID int64) error {
+	q := st.dialect().Update(st.outboxCursorTableName()).Prepared(true)
+	q = q.Set(goqu.Record{"position": eventID, "updated_at": time.Now()})
+	q = q.Where(goqu.C("consumer").Eq(consumer), goqu.C("position").Lt(eventID))
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("ChangeFeed", st.db, false, sqlStr, params...)
+
+	return err
+}
@@ -0,0 +1,57 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// ChangeFeedPrune deletes the outbox events acknowledged by all change
+// feed consumers, returning the number deleted. Nothing is deleted while
+// there are no consumers
+func (st *Store) ChangeFeedPrune() (int64, error) {
+	if !st.outboxEnabled {
+		return 0, errors.New("entity store: outbox is not enabled")
+	}
+
+	return st.storage.outboxPrune()
+}
+
+// outboxPrune deletes the events up to the lowest position of the
+// consumers
+func (st *sqlStorage) outboxPrune() (int64, error) {
+	sqlStr, params, errSql := st.dialect().From(st.outboxCursorTableName()).Prepared(true).
+		Select(goqu.MIN("position").As("position")).
+		ToSQL()
+
+	if errSql != nil {
+		return 0, errSql
+	}
+
+	var position sql.NullInt64
+	if err := st.sqlGet("ChangeFeedPrune", st.db, false, &position, sqlStr, params...); err != nil {
+		return 0, err
+	}
+
+	if !position.Valid {
+		return 0, nil
+	}
+
+	sqlStr, params, errSql = st.dialect().From(st.outboxTableName).Prepared(true).
+		Where(goqu.C("id").Lte(position.Int64)).
+		Delete().
+		ToSQL()
+
+	if errSql != nil {
+		return 0, errSql
+	}
+
+	result, err := st.sqlExec("ChangeFeedPrune", st.db, false, sqlStr, params...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
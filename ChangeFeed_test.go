@@ -0,0 +1,290 @@
+package entitystore
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestChangeFeed(t *testing.T) {
+	stores := changeFeedStores(t, "test_change_feed.db")
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+
+			indexer, err := store.ChangeFeed("indexer")
+
+			if err != nil {
+				t.Fatalf("Change feed could not be created: " + err.Error())
+			}
+
+			entity, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Hello"})
+
+			if err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+
+			if err := entity.SetString("title", "Hello world"); err != nil {
+				t.Fatalf("Attribute could not be set: " + err.Error())
+			}
+
+			if _, err := store.EntityTrash(entity.ID()); err != nil {
+				t.Fatalf("Entity could not be trashed: " + err.Error())
+			}
+
+			if _, err := store.EntityRestore(entity.ID()); err != nil {
+				t.Fatalf("Entity could not be restored: " + err.Error())
+			}
+
+			if _, err := store.EntityDelete(entity.ID()); err != nil {
+				t.Fatalf("Entity could not be deleted: " + err.Error())
+			}
+
+			events, err := indexer.Next(3)
+
+			if err != nil {
+				t.Fatalf("Events could not be read: " + err.Error())
+			}
+
+			if changeOperations(events) != "create,attribute_set,trash" {
+				t.Fatal("Events incorrect", changeOperations(events))
+			}
+
+			if events[0].EntityID != entity.ID() || events[0].EntityType != "post" || events[0].Attributes["title"] != "Hello" {
+				t.Fatal("Create event incorrect", events[0])
+			}
+
+			if events[1].EntityType != "post" || events[1].Attributes["title"] != "Hello world" {
+				t.Fatal("Attribute set event incorrect", events[1])
+			}
+
+			if events[0].CreatedAt.IsZero() || events[0].ID >= events[1].ID {
+				t.Fatal("Events must have the time and increasing IDs", events[0], events[1])
+			}
+
+			again, _ := indexer.Next(3)
+
+			if changeOperations(again) != "create,attribute_set,trash" {
+				t.Fatal("Unacknowledged events must be returned again", changeOperations(again))
+			}
+
+			if err := indexer.Ack(events[1].ID); err != nil {
+				t.Fatalf("Events could not be acknowledged: " + err.Error())
+			}
+
+			rest, _ := indexer.Next(0)
+
+			if changeOperations(rest) != "trash,restore,delete" {
+				t.Fatal("Events after the acknowledged must be returned", changeOperations(rest))
+			}
+
+			if err := indexer.Ack(events[0].ID); err != nil {
+				t.Fatalf("Events could not be acknowledged: " + err.Error())
+			}
+
+			if position, _ := indexer.Position(); position != events[1].ID {
+				t.Fatal("Position must not move back, found", position)
+			}
+
+			cache, err := store.ChangeFeed("cache")
+
+			if err != nil {
+				t.Fatalf("Change feed could not be created: " + err.Error())
+			}
+
+			all, _ := cache.Next(0)
+
+			if len(all) != 5 {
+				t.Fatal("New consumer must start at the oldest event, found", len(all))
+			}
+
+			pruned, err := store.ChangeFeedPrune()
+
+			if err != nil {
+				t.Fatalf("Outbox could not be pruned: " + err.Error())
+			}
+
+			if pruned != 0 {
+				t.Fatal("Events not acknowledged by all consumers must be kept, pruned", pruned)
+			}
+
+			cache.Ack(all[2].ID)
+
+			pruned, _ = store.ChangeFeedPrune()
+
+			if pruned != 2 {
+				t.Fatal("Events acknowledged by all consumers must be pruned, pruned", pruned)
+			}
+
+			rest, _ = indexer.Next(0)
+
+			if changeOperations(rest) != "trash,restore,delete" {
+				t.Fatal("Pruning must keep the unacknowledged events", changeOperations(rest))
+			}
+		})
+	}
+}
+
+func TestChangeFeedImport(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		Backend:       BackendMemory,
+		OutboxEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	feed, err := store.ChangeFeed("indexer")
+
+	if err != nil {
+		t.Fatalf("Change feed could not be created: " + err.Error())
+	}
+
+	records := `{"id":"p1","entity_type":"post","entity_handle":"hello","attributes":{"title":"Hello"}}` + "\n" +
+		`{"id":"p2","entity_type":"post","entity_handle":"hello","attributes":{"title":"Hello again"}}` + "\n"
+
+	if _, err := store.Import(strings.NewReader(records), ImportOptions{OnConflict: ImportMerge}); err != nil {
+		t.Fatalf("Entities could not be imported: " + err.Error())
+	}
+
+	events, err := feed.Next(0)
+
+	if err != nil {
+		t.Fatalf("Events could not be read: " + err.Error())
+	}
+
+	if changeOperations(events) != "create,update" || events[1].Attributes["title"] != "Hello again" {
+		t.Fatal("Imported entities must record events", events)
+	}
+}
+
+func TestChangeFeedDisabled(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		Backend: BackendMemory,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	if _, err := store.ChangeFeed("indexer"); err == nil {
+		t.Fatalf("Change feed must require the outbox")
+	}
+}
+
+func TestChangeFeedOverlappingTransactions(t *testing.T) {
+	stores := changeFeedStores(t, "test_change_feed_overlapping.db")
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+
+			feed, err := store.ChangeFeed("indexer")
+
+			if err != nil {
+				t.Fatalf("Change feed could not be created: " + err.Error())
+			}
+
+			recorded := make(chan struct{})
+			release := make(chan struct{})
+			first := make(chan error, 1)
+			second := make(chan error, 1)
+
+			go func() {
+				first <- store.withTransaction("Test", func(tx *sql.Tx) error {
+					change := ChangeEvent{Operation: ChangeUpdate, EntityID: "first", EntityType: "post"}
+
+					if err := store.changeRecord(tx, &change); err != nil {
+						return err
+					}
+
+					close(recorded)
+					<-release
+
+					return nil
+				})
+			}()
+
+			select {
+			case <-recorded:
+			case err := <-first:
+				t.Fatal("Event could not be recorded", err)
+			}
+
+			go func() {
+				_, err := store.EntityCreate("post")
+				second <- err
+			}()
+
+			events, err := feed.Next(0)
+
+			if err != nil {
+				t.Fatalf("Events could not be read: " + err.Error())
+			}
+
+			if len(events) != 0 {
+				t.Fatal("Events of a transaction in progress must not be read", changeOperations(events))
+			}
+
+			close(release)
+
+			if err := <-first; err != nil {
+				t.Fatalf("First transaction failed: " + err.Error())
+			}
+
+			if err := <-second; err != nil {
+				t.Fatalf("Second transaction failed: " + err.Error())
+			}
+
+			events, err = feed.Next(0)
+
+			if err != nil {
+				t.Fatalf("Events could not be read: " + err.Error())
+			}
+
+			if changeOperations(events) != "update,create" || events[0].ID >= events[1].ID {
+				t.Fatal("Events must be read in the order of the commits", changeOperations(events))
+			}
+		})
+	}
+}
+
+// changeFeedStores returns the constructors of an SQL and a memory store
+// with the outbox enabled
+func changeFeedStores(t *testing.T, filepath string) map[string]func() *Store {
+	return map[string]func() *Store{
+		"sql": func() *Store {
+			store, err := NewStore(NewStoreOptions{
+				DB:                 InitDB(filepath),
+				EntityTableName:    "cms_entity",
+				AttributeTableName: "cms_attribute",
+				AutomigrateEnabled: true,
+				OutboxEnabled:      true,
+			})
+			if err != nil {
+				t.Fatalf("Store could not be created: " + err.Error())
+			}
+			return store
+		},
+		"memory": func() *Store {
+			store, err := NewStore(NewStoreOptions{
+				Backend:       BackendMemory,
+				OutboxEnabled: true,
+			})
+			if err != nil {
+				t.Fatalf("Store could not be created: " + err.Error())
+			}
+			return store
+		},
+	}
+}
+
+func changeOperations(events []ChangeEvent) string {
+	operations := []string{}
+	for _, event := range events {
+		operations = append(operations, event.Operation)
+	}
+	return strings.Join(operations, ",")
+}
@@ -0,0 +1,112 @@
+package entitystore
+
+import (
+	"errors"
+	"time"
+)
+
+// Reasons of a copy conflict
+const (
+	// CopyConflictModified is the conflict of an entity modified in the
+	// destination store: changed since the last sync, or differing from
+	// the source entity for a copy
+	CopyConflictModified = "modified"
+
+	// CopyConflictHandle is the conflict of an entity the handle of which
+	// belongs to another entity of the destination store. The entity is
+	// not copied
+	CopyConflictHandle = "handle"
+)
+
+// CopyOptions define the options of a copy or a sync between stores
+type CopyOptions struct {
+	// OnConflict is the policy applied to the conflicting entities:
+	// ImportSkip (the default) keeps the destination entity,
+	// ImportOverwrite replaces it with the source entity and ImportMerge
+	// sets the attributes of the source entity on it
+	OnConflict string
+
+	// BatchSize is the number of entities written in a transaction,
+	// DefaultImportBatchSize when zero
+	BatchSize int
+
+	// SyncWindow is how long before the watermark Sync reads the entities
+	// again, to find those written by transactions which committed after
+	// the previous sync, with an earlier updated_at. DefaultSyncWindow
+	// when zero. The entities unchanged since the previous sync are
+	// skipped
+	SyncWindow time.Duration
+}
+
+// DefaultSyncWindow is the SyncWindow of a sync when none is given
+const DefaultSyncWindow = time.Minute
+
+// CopyReport reports the outcome of a copy or a sync. The Line of an
+// error is the position of the entity in the copy, starting at 1
+type CopyReport struct {
+	ImportReport
+
+	// Conflicts are the entities in conflict, whatever the resolution
+	Conflicts []CopyConflict
+
+	// Watermark is the time to sync from next: the greatest updated_at of
+	// the entities copied or skipped, or the updated_at of the oldest
+	// entity which could not be copied, to copy it again. It is not
+	// advanced when the copy fails
+	Watermark time.Time
+}
+
+// CopyConflict is an entity which exists in both stores and was modified
+// in the destination store, or the handle of which is taken there
+type CopyConflict struct {
+	EntityID   string
+	EntityType string
+
+	// Reason is CopyConflictModified or CopyConflictHandle
+	Reason string
+
+	SourceUpdatedAt      time.Time
+	DestinationUpdatedAt time.Time
+
+	// Resolution is the conflict policy applied, ImportSkip when the
+	// entity was not copied
+	Resolution string
+}
+
+// CopyTo copies the entities matching the filter, with their attributes,
+// to another store, possibly of another database engine, keeping their
+// IDs. The entities missing from the destination are created, those
+// differing there are conflicts, resolved by the conflict policy. The
+// entities are read in pages and written in batches of a transaction
+// each, with the Import of the destination. Entity hooks are not run, the
+// destination records and publishes the change events of the entities
+// created and updated, as Import
+func (st *Store) CopyTo(dst StoreInterface, filter EntityQueryOptions, options CopyOptions) (*CopyReport, error) {
+	return st.copyEntities(dst, filter, options, nil)
+}
+
+// copyOptionsValidate validates the options of a copy, setting the
+// defaults
+func copyOptionsValidate(st *Store, dst StoreInterface, options *CopyOptions) error {
+	if dst == nil || dst == StoreInterface(st) {
+		return errors.New("copy destination must be another store")
+	}
+
+	if options.OnConflict == "" {
+		options.OnConflict = ImportSkip
+	}
+
+	if options.OnConflict != ImportSkip && options.OnConflict != ImportOverwrite && options.OnConflict != ImportMerge {
+		return errors.New("copy conflict policy not supported: " + options.OnConflict)
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultImportBatchSize
+	}
+
+	if options.SyncWindow <= 0 {
+		options.SyncWindow = DefaultSyncWindow
+	}
+
+	return nil
+}
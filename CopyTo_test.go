@@ -0,0 +1,276 @@
+package entitystore
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func copyTestStores(t *testing.T) (*Store, *Store) {
+	src, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_copy.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	dst, err := NewStore(NewStoreOptions{Backend: BackendMemory})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	return src, dst
+}
+
+func TestCopyTo(t *testing.T) {
+	src, dst := copyTestStores(t)
+
+	post, err := src.EntityCreateWithHandle("post", "hello", map[string]string{"title": "Hello"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if err := post.AppendToList("tags", "go", "db"); err != nil {
+		t.Fatalf("Values could not be appended: " + err.Error())
+	}
+
+	if _, err := src.EntityCreateWithAttributes("post", map[string]string{"title": "Second"}); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if _, err := src.EntityCreate("page"); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	report, err := src.CopyTo(dst, EntityQueryOptions{EntityType: "post"}, CopyOptions{})
+
+	if err != nil {
+		t.Fatalf("Entities could not be copied: " + err.Error())
+	}
+
+	if report.Created != 2 || len(report.Conflicts) != 0 || len(report.Errors) != 0 {
+		t.Fatal("Copy report incorrect", report)
+	}
+
+	copied, err := dst.EntityFindByHandle("post", "hello")
+
+	if err != nil || copied == nil || copied.ID() != post.ID() {
+		t.Fatal("Entity must be copied with its ID", copied, err)
+	}
+
+	if tags, _ := copied.GetList("tags"); len(tags) != 2 || tags[0] != "go" {
+		t.Fatal("List must be copied", tags)
+	}
+
+	report, err = src.CopyTo(dst, EntityQueryOptions{EntityType: "post"}, CopyOptions{})
+
+	if err != nil || report.Skipped != 2 || len(report.Conflicts) != 0 {
+		t.Fatal("Unchanged entities must be skipped", report, err)
+	}
+
+	if err := copied.SetString("title", "Changed"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	report, err = src.CopyTo(dst, EntityQueryOptions{EntityType: "post"}, CopyOptions{})
+
+	if err != nil || len(report.Conflicts) != 1 || report.Conflicts[0].Reason != CopyConflictModified || report.Conflicts[0].Resolution != ImportSkip {
+		t.Fatal("Modified entity must be a conflict", report, err)
+	}
+
+	if title, _ := dst.AttributeFind(post.ID(), "title"); title == nil || title.AttributeValue() != "Changed" {
+		t.Fatal("Conflicting entity must be kept")
+	}
+
+	report, err = src.CopyTo(dst, EntityQueryOptions{EntityType: "post"}, CopyOptions{OnConflict: ImportOverwrite})
+
+	if err != nil || report.Updated != 1 || len(report.Conflicts) != 1 {
+		t.Fatal("Conflicting entity must be overwritten", report, err)
+	}
+
+	if title, _ := dst.AttributeFind(post.ID(), "title"); title == nil || title.AttributeValue() != "Hello" {
+		t.Fatal("Conflicting entity must be overwritten")
+	}
+
+	if _, err := src.CopyTo(src, EntityQueryOptions{}, CopyOptions{}); err == nil {
+		t.Fatalf("Copy to the same store must fail")
+	}
+}
+
+func TestCopyToHandleConflict(t *testing.T) {
+	src, dst := copyTestStores(t)
+
+	if _, err := src.EntityCreateWithHandle("post", "hello", nil); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	other, err := dst.EntityCreateWithHandle("post", "hello", nil)
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	report, err := src.CopyTo(dst, EntityQueryOptions{}, CopyOptions{OnConflict: ImportOverwrite})
+
+	if err != nil || len(report.Conflicts) != 1 || report.Conflicts[0].Reason != CopyConflictHandle || report.Skipped != 1 {
+		t.Fatal("Taken handle must be a conflict", report, err)
+	}
+
+	if found, _ := dst.EntityFindByHandle("post", "hello"); found == nil || found.ID() != other.ID() {
+		t.Fatal("Entity with the handle must be kept")
+	}
+}
+
+func TestSync(t *testing.T) {
+	src, dst := copyTestStores(t)
+
+	first, err := src.EntityCreateWithAttributes("post", map[string]string{"title": "First"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	second, err := src.EntityCreateWithAttributes("post", map[string]string{"title": "Second"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	report, err := src.Sync(dst, EntityQueryOptions{}, time.Time{}, CopyOptions{})
+
+	if err != nil || report.Created != 2 {
+		t.Fatal("Entities must be synced", report, err)
+	}
+
+	watermark := report.Watermark
+
+	if !watermark.Equal(second.UpdatedAt()) {
+		t.Fatal("Watermark incorrect", "must be", second.UpdatedAt(), "found", watermark)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if err := first.SetString("title", "First changed"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	report, err = src.Sync(dst, EntityQueryOptions{}, watermark, CopyOptions{})
+
+	if err != nil || report.Updated != 1 || report.Skipped != 1 || len(report.Conflicts) != 0 {
+		t.Fatal("Changed entity must be synced", report, err)
+	}
+
+	if title, _ := dst.AttributeFind(first.ID(), "title"); title == nil || title.AttributeValue() != "First changed" {
+		t.Fatal("Changed attribute must be synced")
+	}
+
+	watermark = report.Watermark
+	time.Sleep(10 * time.Millisecond)
+
+	if err := second.SetString("title", "Second in source"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	if err := dst.AttributeSetString(second.ID(), "title", "Second in destination"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	report, err = src.Sync(dst, EntityQueryOptions{}, watermark, CopyOptions{})
+
+	if err != nil || len(report.Conflicts) != 1 || report.Conflicts[0].EntityID != second.ID() {
+		t.Fatal("Entity changed in both stores must be a conflict", report, err)
+	}
+
+	if title, _ := dst.AttributeFind(second.ID(), "title"); title == nil || title.AttributeValue() != "Second in destination" {
+		t.Fatal("Conflicting entity must be kept")
+	}
+
+	if !report.Watermark.After(watermark) {
+		t.Fatal("Watermark must advance", report.Watermark)
+	}
+}
+
+func TestCopyToWatermark(t *testing.T) {
+	src, dst := copyTestStores(t)
+
+	failing, err := src.EntityCreateWithAttributes("post", map[string]string{"title": "Failing"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	copied, err := src.EntityCreateWithAttributes("post", map[string]string{"title": "Copied"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	// An entity of another type with the same ID cannot be merged
+	other := `{"id":"` + failing.ID() + `","entity_type":"page","attributes":{}}`
+
+	if _, err := dst.Import(strings.NewReader(other), ImportOptions{PreserveIDs: true}); err != nil {
+		t.Fatalf("Entity could not be imported: " + err.Error())
+	}
+
+	report, err := src.CopyTo(dst, EntityQueryOptions{}, CopyOptions{OnConflict: ImportMerge})
+
+	if err != nil || report.Created != 1 || len(report.Errors) != 1 || report.Errors[0].Line != 1 {
+		t.Fatal("Copy report incorrect", report, err)
+	}
+
+	if !report.Watermark.Equal(failing.UpdatedAt()) {
+		t.Fatal("Watermark must stop at the failed entity", "must be", failing.UpdatedAt(), "found", report.Watermark)
+	}
+
+	if _, err := dst.EntityDelete(failing.ID()); err != nil {
+		t.Fatalf("Entity could not be deleted: " + err.Error())
+	}
+
+	report, err = src.Sync(dst, EntityQueryOptions{}, report.Watermark, CopyOptions{})
+
+	if err != nil || report.Created != 1 || report.Skipped != 1 {
+		t.Fatal("Failed entity must be synced again", report, err)
+	}
+
+	if !report.Watermark.Equal(copied.UpdatedAt()) {
+		t.Fatal("Watermark incorrect", "must be", copied.UpdatedAt(), "found", report.Watermark)
+	}
+}
+
+func TestSyncWindow(t *testing.T) {
+	src, dst := copyTestStores(t)
+
+	late, err := src.EntityCreateWithAttributes("post", map[string]string{"title": "Late"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	// The entity was written before the watermark, by a transaction which
+	// committed after the previous sync
+	watermark := late.UpdatedAt().Add(time.Second)
+
+	report, err := src.Sync(dst, EntityQueryOptions{}, watermark, CopyOptions{SyncWindow: time.Millisecond})
+
+	if err != nil || report.Created != 0 {
+		t.Fatal("Entity before the window must not be synced", report, err)
+	}
+
+	report, err = src.Sync(dst, EntityQueryOptions{}, watermark, CopyOptions{})
+
+	if err != nil || report.Created != 1 {
+		t.Fatal("Entity in the window must be synced", report, err)
+	}
+
+	if !report.Watermark.Equal(watermark) {
+		t.Fatal("Watermark must not go back", report.Watermark)
+	}
+}
@@ -1,7 +1,6 @@
 package entitystore
 
 import (
-	"log"
 	"time"
 )
 
@@ -12,6 +11,7 @@ type Entity struct {
 	entityHandle string
 	createdAt    time.Time
 	updatedAt    time.Time
+	version      int64
 	st           *Store
 }
 
@@ -22,6 +22,7 @@ func (e *Entity) ToMap() map[string]any {
 	entry["entity_handle"] = e.Handle()
 	entry["created_at"] = e.CreatedAt()
 	entry["updated_at"] = e.UpdatedAt()
+	entry["version"] = e.Version()
 	return entry
 }
 
@@ -45,6 +46,12 @@ func (e *Entity) UpdatedAt() time.Time {
 	return e.updatedAt
 }
 
+// Version returns the version of the entity, incremented by every write
+// of the entity or its attributes
+func (e *Entity) Version() int64 {
+	return e.version
+}
+
 func (e *Entity) SetID(id string) *Entity {
 	e.id = id
 	return e
@@ -70,6 +77,11 @@ func (e *Entity) SetUpdatedAt(updatedAt time.Time) *Entity {
 	return e
 }
 
+func (e *Entity) SetVersion(version int64) *Entity {
+	e.version = version
+	return e
+}
+
 // GetInt the value of the attribute as string or the default value if it does not exist
 func (e *Entity) GetInt(attributeKey string, defaultValue int64) (int64, error) {
 	attr, err := e.GetAttribute(attributeKey)
@@ -100,9 +112,6 @@ func (e *Entity) GetFloat(attributeKey string, defaultValue float64) (float64, e
 	attr, err := e.GetAttribute(attributeKey)
 
 	if err != nil {
-		if e.st.GetDebug() {
-			log.Println(err)
-		}
 		return defaultValue, err
 	}
 
@@ -118,9 +127,6 @@ func (e *Entity) GetString(attributeKey string, defaultValue string) (string, er
 	attr, err := e.GetAttribute(attributeKey)
 
 	if err != nil {
-		if e.st.GetDebug() {
-			log.Println(err)
-		}
 		return defaultValue, err
 	}
 
@@ -131,6 +137,38 @@ func (e *Entity) GetString(attributeKey string, defaultValue string) (string, er
 	return attr.GetString(), nil
 }
 
+// Increment adds the delta to an int attribute atomically and returns the
+// new value
+func (e *Entity) Increment(attributeKey string, delta int64) (int64, error) {
+	return e.st.AttributeIncrement(e.ID(), attributeKey, delta)
+}
+
+// IncrementFloat adds the delta to a float attribute atomically and
+// returns the new value
+func (e *Entity) IncrementFloat(attributeKey string, delta float64) (float64, error) {
+	return e.st.AttributeIncrementFloat(e.ID(), attributeKey, delta)
+}
+
+// AddToSet adds the values missing from a set attribute
+func (e *Entity) AddToSet(attributeKey string, values ...string) error {
+	return e.st.AttributeAddToSet(e.ID(), attributeKey, values...)
+}
+
+// AppendToList appends the values to a list attribute
+func (e *Entity) AppendToList(attributeKey string, values ...string) error {
+	return e.st.AttributeAppendToList(e.ID(), attributeKey, values...)
+}
+
+// GetList returns the values of a list or set attribute, in order
+func (e *Entity) GetList(attributeKey string) ([]string, error) {
+	return e.st.AttributeValues(e.ID(), attributeKey)
+}
+
+// RemoveFromSet removes the values from a set or list attribute
+func (e *Entity) RemoveFromSet(attributeKey string, values ...string) error {
+	return e.st.AttributeRemoveFromSet(e.ID(), attributeKey, values...)
+}
+
 // SetAll upserts the attributes
 func (e *Entity) SetAll(attributes map[string]string) error {
 	return e.st.AttributesSet(e.ID(), attributes)
@@ -1,10 +1,6 @@
 package entitystore
 
 import (
-	"context"
-	"database/sql"
-	"log"
-
 	"github.com/doug-martin/goqu/v9"
 	"github.com/georgysavva/scany/sqlscan"
 )
@@ -12,31 +8,27 @@ import (
 // EntityCount counts the entities of a specified type
 // EntityCount counts entities
 func (st *Store) EntityCount(options EntityQueryOptions) (int64, error) {
+	return st.storage.entityCount(options)
+}
+
+// entityCount counts the entities matching the options in one query
+func (st *sqlStorage) entityCount(options EntityQueryOptions) (int64, error) {
 	options.CountOnly = true
 
 	q := st.EntityQuery(options)
-	sqlStr, _, errSql := q.Limit(1).Select(goqu.COUNT(goqu.Star()).As("count")).ToSQL()
+	sqlStr, params, errSql := q.ClearOrder().Limit(1).Select(goqu.COUNT(goqu.Star()).As("count")).ToSQL()
 
 	if errSql != nil {
 		return 0, errSql
 	}
 
-	if st.debugEnabled {
-		log.Println(sqlStr)
-	}
-
 	type countResult struct {
 		Count int64 `db:"count"`
 	}
 
 	var result countResult
-	err := sqlscan.Get(context.Background(), st.db, &result, sqlStr)
+	err := st.sqlGet("EntityCount", st.db, false, &result, sqlStr, params...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			// sqlscan does not use this anymore
-			return 0, err
-		}
-
 		if sqlscan.NotFound(err) {
 			return 0, nil
 		}
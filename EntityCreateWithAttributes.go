@@ -1,42 +1,94 @@
 package entitystore
 
+import (
+	"database/sql"
+	"time"
+
+	"github.com/gouniverse/uid"
+)
+
 // EntityCreateWithAttributes func
 func (st *Store) EntityCreateWithAttributes(entityType string, attributes map[string]string) (*Entity, error) {
-	// Note the use of tx as the database handle once you are within a transaction
-	tx, err := st.db.Begin()
+	return st.entityCreate(entityType, "", attributes)
+}
 
-	if err != nil {
-		return nil, err
+// entityCreate creates an entity with its attributes, and its handle
+// unless empty
+func (st *Store) entityCreate(entityType string, entityHandle string, attributes map[string]string) (*Entity, error) {
+	entity := st.NewEntity(NewEntityOptions{
+		ID:        uid.HumanUid(),
+		Type:      entityType,
+		Handle:    entityHandle,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Version:   1,
+	})
+
+	event := &EntityHookEvent{Entity: entity, Attributes: map[string]string{}}
+
+	for k, v := range attributes {
+		event.Attributes[k] = v
 	}
 
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	change := ChangeEvent{
+		Operation:  ChangeCreate,
+		EntityID:   entity.ID(),
+		EntityType: entity.Type(),
+	}
+
+	err := st.withTransaction("EntityCreate", func(tx *sql.Tx) error {
+		event.Tx = tx
+
+		if err := st.entityHooksRun(EntityBeforeCreate, event); err != nil {
+			return err
+		}
+
+		if err := st.storage.entityInsert(tx, *entity, event.Attributes); err != nil {
+			return err
+		}
+
+		change.Attributes = event.Attributes
+
+		if err := st.changeRecord(tx, &change); err != nil {
+			return err
 		}
-	}()
 
-	entity, err := st.entityCreateWithTransactionOrDB(tx, entityType)
+		return st.entityHooksRun(EntityAfterCreate, event)
+	})
+
+	st.cacheInvalidate(entity.ID())
 
 	if err != nil {
-		tx.Rollback()
+		if entityHandle != "" {
+			return nil, st.entityHandleTaken(err, entityType, entityHandle, entity.ID())
+		}
+
 		return nil, err
 	}
 
-	for k, v := range attributes {
-		_, err := st.attributeCreateWithTransactionOrDB(tx, entity.ID(), k, v)
+	st.changePublish(change)
 
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	return entity, nil
+}
+
+// entityInsert inserts a new entity with its handle, unless empty, and its
+// attributes
+func (st *sqlStorage) entityInsert(tx *sql.Tx, entity Entity, attributes map[string]string) error {
+	if err := st.entityInsertWithTransactionOrDB(tx, entity); err != nil {
+		return err
 	}
 
-	err = tx.Commit()
+	if entity.Handle() != "" {
+		if err := st.entityHandleReserve(tx, entity.Type(), entity.Handle(), entity.ID()); err != nil {
+			return err
+		}
+	}
 
-	if err != nil {
-		tx.Rollback()
-		return nil, err
+	for k, v := range attributes {
+		if _, err := st.attributeCreateWithTransactionOrDB(tx, entity.ID(), k, v); err != nil {
+			return err
+		}
 	}
 
-	return entity, nil
+	return nil
 }
@@ -0,0 +1,13 @@
+package entitystore
+
+// EntityCreateWithHandle creates an entity with a handle, unique for the
+// entity type, and its attributes, which may be nil. It fails with a
+// *HandleTakenError, matching ErrHandleTaken, when the handle belongs to
+// another entity of the type. See EntityHandleGenerate
+func (st *Store) EntityCreateWithHandle(entityType string, entityHandle string, attributes map[string]string) (*Entity, error) {
+	if err := entityHandleValidate(entityHandle); err != nil {
+		return nil, err
+	}
+
+	return st.entityCreate(entityType, entityHandle, attributes)
+}
@@ -0,0 +1,29 @@
+package entitystore
+
+import "iter"
+
+// EntityCursor returns the entities matching the options as an iterator,
+// read like EntityIterate, each with the attributes read with it. An error
+// ends the iteration, yielded with a zero entity
+//
+//	for item, err := range store.EntityCursor(options) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(item.Entity.ID())
+//	}
+func (st *Store) EntityCursor(options EntityQueryOptions, iterateOptions ...IterateOptions) iter.Seq2[EntityWithAttributes, error] {
+	return func(yield func(EntityWithAttributes, error) bool) {
+		err := st.EntityIterate(options, func(entity Entity, attributes []Attribute) error {
+			if !yield(EntityWithAttributes{Entity: entity, Attributes: attributes}, nil) {
+				return errIterationStopped
+			}
+
+			return nil
+		}, iterateOptions...)
+
+		if err != nil && err != errIterationStopped {
+			yield(EntityWithAttributes{}, err)
+		}
+	}
+}
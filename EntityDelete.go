@@ -1,8 +1,8 @@
 package entitystore
 
 import (
+	"database/sql"
 	"errors"
-	"log"
 
 	"github.com/doug-martin/goqu/v9"
 )
@@ -10,66 +10,96 @@ import (
 // EntityDelete deletes an entity and all attributes
 func (st *Store) EntityDelete(entityID string) (bool, error) {
 	if entityID == "" {
-		if st.GetDebug() {
-			log.Println("in EntityDelete entity ID cannot be empty")
-		}
-		return false, errors.New("in EntityDelete entity ID cannot be empty")
+		err := errors.New("in EntityDelete entity ID cannot be empty")
+		st.logError("EntityDelete", err)
+		return false, err
 	}
 
-	// Note the use of tx as the database handle once you are within a transaction
-	tx, err := st.db.Begin()
+	event := &EntityHookEvent{}
 
-	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
+	// The entity type is only looked up when needed, for the hooks
+	// registered per entity type, the outbox and the subscriptions
+	if st.outboxEnabled || st.subscribed() || st.entityHooksRegistered(EntityBeforeDelete, EntityAfterDelete) {
+		entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
+
+		if err != nil {
+			return false, err
 		}
-		return false, err
+
+		event.Entity = entity
+	}
+
+	change := ChangeEvent{
+		Operation: ChangeDelete,
+		EntityID:  entityID,
 	}
 
-	defer func() {
-		if r := recover(); r != nil {
-			txErr := tx.Rollback()
-			if txErr != nil && st.GetDebug() {
-				log.Println(txErr)
+	if event.Entity != nil {
+		change.EntityType = event.Entity.Type()
+	}
+
+	err := st.withTransaction("EntityDelete", func(tx *sql.Tx) error {
+		event.Tx = tx
+
+		if event.Entity != nil {
+			if err := st.entityHooksRun(EntityBeforeDelete, event); err != nil {
+				return err
 			}
 		}
-	}()
 
-	sqlStr1, _, _ := goqu.Dialect(st.dbDriverName).From(st.attributeTableName).Where(goqu.C("entity_id").Eq(entityID)).Delete().ToSQL()
+		if err := st.storage.entityDelete(tx, entityID); err != nil {
+			return err
+		}
 
-	if _, err := tx.Exec(sqlStr1); err != nil {
-		if st.GetDebug() {
-			log.Println(err)
+		if err := st.changeRecord(tx, &change); err != nil {
+			return err
 		}
-		txErr := tx.Rollback()
-		if txErr != nil && st.GetDebug() {
-			log.Println(txErr)
+
+		if event.Entity != nil {
+			return st.entityHooksRun(EntityAfterDelete, event)
 		}
+
+		return nil
+	})
+
+	st.cacheInvalidate(entityID)
+
+	if err != nil {
 		return false, err
 	}
 
-	sqlStr2, _, _ := goqu.Dialect(st.dbDriverName).From(st.entityTableName).Where(goqu.C("id").Eq(entityID)).Delete().ToSQL()
+	st.changePublish(change)
 
-	if _, err := tx.Exec(sqlStr2); err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-		txErr := tx.Rollback()
-		if txErr != nil && st.GetDebug() {
-			log.Println(txErr)
-		}
-		return false, err
+	return true, nil
+}
+
+// entityDelete deletes an entity with its attributes, values and handles
+func (st *sqlStorage) entityDelete(tx *sql.Tx, entityID string) error {
+	sqlStr1, params1, errSql := st.dialect().From(st.attributeTableName).Prepared(true).Where(goqu.C("entity_id").Eq(entityID)).Delete().ToSQL()
+
+	if errSql != nil {
+		return errSql
 	}
 
-	err = tx.Commit()
+	if _, err := st.sqlExec("EntityDelete", tx, false, sqlStr1, params1...); err != nil {
+		return err
+	}
 
-	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
+	if err := st.attributeValuesDelete(tx, entityID); err != nil {
+		return err
+	}
 
-		return false, err
+	if err := st.entityHandlesDelete(tx, entityID); err != nil {
+		return err
 	}
 
-	return true, nil
+	sqlStr2, params2, errSql := st.dialect().From(st.entityTableName).Prepared(true).Where(goqu.C("id").Eq(entityID)).Delete().ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("EntityDelete", tx, false, sqlStr2, params2...)
+
+	return err
 }
@@ -2,8 +2,10 @@ package entitystore
 
 import "errors"
 
-// EntityFindByHandle finds an entity by handle
-func (st *Store) EntityFindByHandle(entityType string, entityHandle string) (*Entity, error) {
+// EntityFindByHandle finds an entity by handle, the current handle of the
+// entity or a previous one. A trashed entity is not found, though its
+// handles stay reserved
+func (st *Store) EntityFindByHandle(entityType string, entityHandle string, options ...FindOptions) (*Entity, error) {
 	if entityType == "" {
 		return nil, errors.New("entity type cannot be empty")
 	}
@@ -12,6 +14,43 @@ func (st *Store) EntityFindByHandle(entityType string, entityHandle string) (*En
 		return nil, errors.New("entity handle cannot be empty")
 	}
 
+	if !st.cacheEnabled(options) {
+		return st.entityFindByHandle(entityType, entityHandle)
+	}
+
+	// The ID of the entity is cached. A handle keeps belonging to the
+	// entity after a change, only the type is checked against the entity
+	key := st.cacheKey("handle", entityType, entityHandle)
+
+	if cached, found := st.cache.Get(key); found {
+		if entityID, ok := cached.(string); ok {
+			entity, err := st.EntityFindByID(entityID)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if entity != nil && entity.Type() == entityType {
+				return entity, nil
+			}
+		}
+
+		st.cache.Delete(key)
+	}
+
+	st.cacheCount(false)
+
+	generation := st.cacheGeneration.Load()
+	entity, err := st.entityFindByHandle(entityType, entityHandle)
+
+	if err == nil && entity != nil {
+		st.cacheSet(key, entity.ID(), generation)
+	}
+
+	return entity, err
+}
+
+func (st *Store) entityFindByHandle(entityType string, entityHandle string) (*Entity, error) {
 	list, err := st.EntityList(EntityQueryOptions{
 		EntityType:   entityType,
 		EntityHandle: entityHandle,
@@ -26,5 +65,18 @@ func (st *Store) EntityFindByHandle(entityType string, entityHandle string) (*En
 		return &list[0], nil
 	}
 
-	return nil, nil
+	// A previous handle of the entity
+	entityID, err := st.storage.entityHandleFind(st.db, entityType, entityHandle)
+
+	if err != nil || entityID == "" {
+		return nil, err
+	}
+
+	entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
+
+	if err != nil || entity == nil || entity.Type() != entityType {
+		return nil, err
+	}
+
+	return entity, nil
 }
@@ -1,17 +1,47 @@
 package entitystore
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+)
 
 // EntityFindByID finds an entity by ID
-func (st *Store) EntityFindByID(entityID string) (*Entity, error) {
+func (st *Store) EntityFindByID(entityID string, options ...FindOptions) (*Entity, error) {
 	if entityID == "" {
 		return nil, errors.New("entity ID cannot be empty")
 	}
 
-	list, err := st.EntityList(EntityQueryOptions{
+	if !st.cacheEnabled(options) {
+		return st.entityFindByID(entityID)
+	}
+
+	key := st.cacheKey("entity", entityID)
+
+	if cached, found := st.cache.Get(key); found {
+		if entity, ok := cached.(Entity); ok {
+			st.cacheCount(true)
+			return &entity, nil
+		}
+	}
+
+	st.cacheCount(false)
+
+	generation := st.cacheGeneration.Load()
+	entity, err := st.entityFindByID(entityID)
+
+	if err == nil && entity != nil {
+		st.cacheSet(key, *entity, generation)
+	}
+
+	return entity, err
+}
+
+func (st *Store) entityFindByID(entityID string) (*Entity, error) {
+	list, err := st.storage.entityList(EntityQueryOptions{
 		ID:    entityID,
 		Limit: 1,
-	})
+	}, true)
 
 	if err != nil {
 		return nil, err
@@ -23,3 +53,23 @@ func (st *Store) EntityFindByID(entityID string) (*Entity, error) {
 
 	return nil, nil
 }
+
+// entityExists returns whether the entity exists
+func (st *sqlStorage) entityExists(db txOrDB, entityID string) (bool, error) {
+	sqlStr, params, errSql := st.dialect().From(st.entityTableName).Prepared(true).
+		Select(goqu.COUNT("*")).
+		Where(goqu.C("id").Eq(entityID)).
+		ToSQL()
+
+	if errSql != nil {
+		return false, errSql
+	}
+
+	var count int64
+
+	if err := st.sqlGet("EntityExists", db, false, &count, sqlStr, params...); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,54 @@
+package entitystore
+
+import (
+	"errors"
+	"strconv"
+)
+
+// EntityHandleGenerate returns a handle for an entity of the type, the
+// slug of the text, which is not used by another entity of the type. On a
+// collision the slug is suffixed with "-2", "-3" and so on. A concurrent
+// create may still take the handle first, EntityCreateWithHandle then
+// fails with ErrHandleTaken and the handle can be generated again
+func (st *Store) EntityHandleGenerate(entityType string, text string) (string, error) {
+	if entityType == "" {
+		return "", errors.New("entity type cannot be empty")
+	}
+
+	slug := Slugify(text)
+
+	if slug == "" {
+		return "", errors.New("entity handle cannot be generated from " + strconv.Quote(text))
+	}
+
+	// A long slug is cut to fit the suffix, the handles are looked up by
+	// a prefix shared by all the candidates
+	prefix := slug
+	if len(prefix) > handleMaxLength/2 {
+		prefix = prefix[:handleMaxLength/2]
+	}
+
+	taken, err := st.storage.entityHandlesWithPrefix(entityType, prefix)
+
+	if err != nil {
+		return "", err
+	}
+
+	used := map[string]bool{}
+	for _, handle := range taken {
+		used[handle] = true
+	}
+
+	for i := 1; ; i++ {
+		handle := slug
+
+		if i > 1 {
+			suffix := "-" + strconv.Itoa(i)
+			handle = slugTruncate(slug, handleMaxLength-len(suffix)) + suffix
+		}
+
+		if !used[handle] {
+			return handle, nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package entitystore
+
+import "database/sql"
+
+// EntityHookType is the store event an entity hook is registered for
+type EntityHookType string
+
+// Entity hook types. The Before hooks may veto the operation by returning
+// an error, the After hooks may fail it, which rolls it back
+const (
+	EntityBeforeCreate       EntityHookType = "before_create"
+	EntityAfterCreate        EntityHookType = "after_create"
+	EntityBeforeUpdate       EntityHookType = "before_update"
+	EntityAfterUpdate        EntityHookType = "after_update"
+	EntityBeforeAttributeSet EntityHookType = "before_attribute_set"
+	EntityAfterAttributeSet  EntityHookType = "after_attribute_set"
+	EntityBeforeDelete       EntityHookType = "before_delete"
+	EntityAfterDelete        EntityHookType = "after_delete"
+	EntityBeforeTrash        EntityHookType = "before_trash"
+	EntityAfterTrash         EntityHookType = "after_trash"
+	EntityBeforeRestore      EntityHookType = "before_restore"
+	EntityAfterRestore       EntityHookType = "after_restore"
+)
+
+// EntityHookEvent is passed to the entity hooks
+type EntityHookEvent struct {
+	Type   EntityHookType
+	Entity *Entity
+
+	// Attributes are the attributes created with the entity, being set or
+	// restored. The Before hooks of create and attribute set may change
+	// them, i.e. to stamp additional attributes
+	Attributes map[string]string
+
+	// Tx is the transaction of the operation, nil for the memory backend.
+	// Hooks writing to the database must use it, the store methods would
+	// wait for the transaction to complete. On the memory backend they
+	// wait too, the hooks cannot write to the store
+	Tx *sql.Tx
+}
+
+// EntityHook is a function run on a store event of an entity
+type EntityHook func(event *EntityHookEvent) error
+
+// RegisterEntityHook registers a hook run on the event for the entities of
+// the type, or of all types when entityType is empty. The hooks run in
+// the transaction of the operation, in the order registered, the hooks
+// for all types first. A failing hook rolls the operation back, on the
+// memory backend too.
+//
+// Create hooks run for EntityCreate and EntityCreateWithAttributes, update
+// hooks for EntityUpdate, attribute set hooks for AttributesSet and the
+// AttributeSet methods, delete hooks for EntityDelete, trash hooks for
+// EntityTrash and restore hooks for EntityRestore
+func (st *Store) RegisterEntityHook(entityType string, hookType EntityHookType, hook EntityHook) {
+	st.entityHooksMutex.Lock()
+	defer st.entityHooksMutex.Unlock()
+
+	if st.entityHooks == nil {
+		st.entityHooks = map[string]map[EntityHookType][]EntityHook{}
+	}
+
+	if _, exists := st.entityHooks[entityType]; !exists {
+		st.entityHooks[entityType] = map[EntityHookType][]EntityHook{}
+	}
+
+	st.entityHooks[entityType][hookType] = append(st.entityHooks[entityType][hookType], hook)
+}
+
+// entityHooksRegistered checks whether hooks are registered for any of
+// the hook types, for any entity type
+func (st *Store) entityHooksRegistered(hookTypes ...EntityHookType) bool {
+	st.entityHooksMutex.RLock()
+	defer st.entityHooksMutex.RUnlock()
+
+	for _, hooks := range st.entityHooks {
+		for _, hookType := range hookTypes {
+			if len(hooks[hookType]) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// entityHooksRun runs the hooks of the event type registered for all
+// entity types and for the type of the event entity, stopping at the
+// first error
+func (st *Store) entityHooksRun(hookType EntityHookType, event *EntityHookEvent) error {
+	st.entityHooksMutex.RLock()
+	hooks := append([]EntityHook{}, st.entityHooks[""][hookType]...)
+	if event.Entity != nil && event.Entity.Type() != "" {
+		hooks = append(hooks, st.entityHooks[event.Entity.Type()][hookType]...)
+	}
+	st.entityHooksMutex.RUnlock()
+
+	event.Type = hookType
+
+	for _, hook := range hooks {
+		if err := hook(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
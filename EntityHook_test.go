@@ -0,0 +1,59 @@
+package entitystore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEntityHookRollback(t *testing.T) {
+	db := InitDB("test_entity_hook_rollback.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	store.RegisterEntityHook("post", EntityAfterCreate, func(event *EntityHookEvent) error {
+		if event.Tx == nil {
+			return errors.New("hook must run in the transaction")
+		}
+		if event.Attributes["title"] == "fail" {
+			return errors.New("title not allowed")
+		}
+		return nil
+	})
+
+	if _, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "fail"}); err == nil {
+		t.Fatalf("Failing After hook must fail the create")
+	}
+
+	count, err := store.EntityCount(EntityQueryOptions{EntityType: "post"})
+
+	if err != nil {
+		t.Fatalf("Entities could not be counted: " + err.Error())
+	}
+
+	if count != 0 {
+		t.Fatal("Create must be rolled back, found count", count)
+	}
+
+	attrs, err := store.AttributeList(AttributeQueryOptions{})
+
+	if err != nil {
+		t.Fatalf("Attributes could not be listed: " + err.Error())
+	}
+
+	if len(attrs) != 0 {
+		t.Fatal("Attributes must be rolled back, found", len(attrs))
+	}
+
+	if _, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "ok"}); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+}
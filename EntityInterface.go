@@ -0,0 +1,39 @@
+package entitystore
+
+import "time"
+
+// EntityInterface is the public API of an entity, implemented by *Entity
+type EntityInterface interface {
+	ID() string
+	Type() string
+	Handle() string
+	CreatedAt() time.Time
+	UpdatedAt() time.Time
+	Version() int64
+	ToMap() map[string]any
+
+	SetID(id string) *Entity
+	SetType(entityType string) *Entity
+	SetHandle(handle string) *Entity
+	SetCreatedAt(createdAt time.Time) *Entity
+	SetUpdatedAt(updatedAt time.Time) *Entity
+	SetVersion(version int64) *Entity
+
+	GetAttribute(attributeKey string) (*Attribute, error)
+	GetAttributes() ([]Attribute, error)
+	GetList(attributeKey string) ([]string, error)
+	GetFloat(attributeKey string, defaultValue float64) (float64, error)
+	GetInt(attributeKey string, defaultValue int64) (int64, error)
+	GetString(attributeKey string, defaultValue string) (string, error)
+	Increment(attributeKey string, delta int64) (int64, error)
+	IncrementFloat(attributeKey string, delta float64) (float64, error)
+	AddToSet(attributeKey string, values ...string) error
+	AppendToList(attributeKey string, values ...string) error
+	RemoveFromSet(attributeKey string, values ...string) error
+	SetAll(attributes map[string]string) error
+	SetFloat(attributeKey string, attributeValue float64) error
+	SetInt(attributeKey string, attributeValue int64) error
+	SetString(attributeKey string, attributeValue string) error
+}
+
+var _ EntityInterface = (*Entity)(nil)
@@ -0,0 +1,108 @@
+package entitystore
+
+import "errors"
+
+// IterateOptions define the options of an iteration over the entities
+type IterateOptions struct {
+	// ChunkSize is the number of entities read at once, 100 when zero
+	ChunkSize int
+
+	// WithAttributes reads the attributes of each chunk of entities in
+	// one query, and passes them with each entity
+	WithAttributes bool
+}
+
+// EntityWithAttributes is an entity yielded by EntityCursor, with the
+// attributes read with it when WithAttributes is set, nil otherwise
+type EntityWithAttributes struct {
+	Entity Entity
+
+	// Attributes are the attributes of the entity when it was read, the
+	// writes made since are not reflected
+	Attributes []Attribute
+}
+
+// Attribute returns the attribute with the key among the attributes read
+// with the entity, nil when the entity has no such attribute
+func (e EntityWithAttributes) Attribute(attributeKey string) *Attribute {
+	for i := range e.Attributes {
+		if e.Attributes[i].AttributeKey() == attributeKey {
+			return &e.Attributes[i]
+		}
+	}
+
+	return nil
+}
+
+// errIterationStopped stops an iteration when the consumer of a cursor
+// stops
+var errIterationStopped = errors.New("entity store: iteration stopped")
+
+// EntityIterate calls fn with each entity matching the options, ordered
+// by ID, until fn returns an error, which is returned. The attributes are
+// those read with the entity when WithAttributes is set, nil otherwise.
+// The entities are streamed in chunks read by keyset pagination, only a
+// chunk is held in memory, so any number of entities can be scanned. The
+// Limit and Offset of the options apply to the whole iteration, the
+// entities cannot be sorted by another column
+func (st *Store) EntityIterate(options EntityQueryOptions, fn func(entity Entity, attributes []Attribute) error, iterateOptions ...IterateOptions) error {
+	if (options.SortBy != "" && options.SortBy != "id") || (options.SortOrder != "" && options.SortOrder != "asc") {
+		return errors.New("entity iteration is ordered by id, sort not supported: " + options.SortBy + " " + options.SortOrder)
+	}
+
+	iterateOption := IterateOptions{}
+	if len(iterateOptions) > 0 {
+		iterateOption = iterateOptions[0]
+	}
+
+	chunkSize := uint64(entityPageSize)
+	if iterateOption.ChunkSize > 0 {
+		chunkSize = uint64(iterateOption.ChunkSize)
+	}
+
+	return st.entityPages(options, chunkSize, func(entities []Entity) error {
+		var attributes map[string][]Attribute
+
+		if iterateOption.WithAttributes {
+			var err error
+
+			if attributes, err = st.entityAttributesLoad(entities); err != nil {
+				return err
+			}
+		}
+
+		for _, entity := range entities {
+			if err := fn(entity, attributes[entity.ID()]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// entityAttributesLoad reads the attributes of the entities in one query,
+// by entity ID. Every entity has a slice, empty without attributes
+func (st *Store) entityAttributesLoad(entities []Entity) (map[string][]Attribute, error) {
+	entityIDs := make([]string, 0, len(entities))
+	attributes := map[string][]Attribute{}
+
+	for _, entity := range entities {
+		entityIDs = append(entityIDs, entity.ID())
+		attributes[entity.ID()] = []Attribute{}
+	}
+
+	list, err := st.AttributeList(AttributeQueryOptions{EntityIDs: entityIDs})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attr := range list {
+		if _, exists := attributes[attr.EntityID()]; exists {
+			attributes[attr.EntityID()] = append(attributes[attr.EntityID()], attr)
+		}
+	}
+
+	return attributes, nil
+}
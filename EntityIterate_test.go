@@ -0,0 +1,83 @@
+package entitystore
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEntityIterateWithAttributes(t *testing.T) {
+	db := InitDB("test_entity_iterate.db")
+
+	operations := map[string]int{}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		QueryHooks: []QueryHook{QueryHookFuncs{
+			After: func(ctx context.Context, event *QueryEvent) {
+				operations[event.Operation]++
+			},
+		}},
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		if _, err := store.EntityCreateWithAttributes("post", map[string]string{"title": title}); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+	}
+
+	operations = map[string]int{}
+	titles := []string{}
+
+	err = store.EntityIterate(EntityQueryOptions{EntityType: "post"}, func(entity Entity, attributes []Attribute) error {
+		if len(attributes) != 1 || attributes[0].AttributeKey() != "title" {
+			t.Fatal("Attributes incorrect", attributes)
+		}
+
+		titles = append(titles, attributes[0].AttributeValue())
+		return nil
+	}, IterateOptions{ChunkSize: 2, WithAttributes: true})
+
+	if err != nil {
+		t.Fatalf("Entities could not be iterated: " + err.Error())
+	}
+
+	if len(titles) != 3 || titles[0] != "First" || titles[2] != "Third" {
+		t.Fatal("Titles incorrect", titles)
+	}
+
+	if operations["AttributeList"] != 2 || operations["AttributeFind"] != 0 {
+		t.Fatal("Attributes must be loaded once per chunk", operations)
+	}
+
+	errStop := errors.New("stop")
+
+	err = store.EntityIterate(EntityQueryOptions{}, func(entity Entity, attributes []Attribute) error {
+		if err := entity.SetString("title", "Changed"); err != nil {
+			return err
+		}
+
+		if title, _ := entity.GetString("title", ""); title != "Changed" {
+			t.Fatal("Entity must read the attributes from the store", title)
+		}
+
+		return errStop
+	}, IterateOptions{WithAttributes: true})
+
+	if err != errStop {
+		t.Fatal("Error of the callback must be returned", err)
+	}
+
+	for _, err := range store.EntityCursor(EntityQueryOptions{SortBy: "created_at"}) {
+		if err == nil {
+			t.Fatalf("Cursor must yield the error")
+		}
+	}
+}
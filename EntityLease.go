@@ -0,0 +1,21 @@
+package entitystore
+
+import "time"
+
+// EntityLease is an exclusive lock on an entity, held by an owner until it
+// expires, unless renewed
+type EntityLease struct {
+	EntityID  string
+	Owner     string
+	ExpiresAt time.Time
+
+	// Token increases with every acquisition of the lock. Passed along
+	// with writes to other systems, it lets them reject the writes of an
+	// owner whose lease expired and was taken over since
+	Token int64
+}
+
+// Expired returns whether the lease has expired
+func (l *EntityLease) Expired() bool {
+	return !time.Now().Before(l.ExpiresAt)
+}
@@ -1,56 +1,52 @@
 package entitystore
 
 import (
-	"log"
-
 	"github.com/doug-martin/goqu/v9"
 )
 
 // EntityListByAttribute finds an entity by attribute
 func (st *Store) EntityListByAttribute(entityType string, attributeKey string, attributeValue string) (entityList []Entity, err error) {
+	entityIDs, err := st.storage.entityIDsByAttribute(entityType, attributeKey, attributeValue)
+
+	if err != nil {
+		return []Entity{}, err
+	}
+
+	if len(entityIDs) < 1 {
+		return entityList, nil
+	}
+
+	return st.EntityList(EntityQueryOptions{
+		EntityType: entityType,
+		IDs:        entityIDs,
+		SortBy:     "id",
+	})
+}
+
+// entityIDsByAttribute returns the IDs of the entities of a type having
+// the attribute value
+func (st *sqlStorage) entityIDsByAttribute(entityType string, attributeKey string, attributeValue string) ([]string, error) {
 	var entityIDs []string
 
-	q := goqu.Dialect(st.dbDriverName).From(st.attributeTableName).
+	q := st.dialect().From(st.attributeTableName).Prepared(true).
 		LeftJoin(goqu.I(st.entityTableName), goqu.On(goqu.Ex{st.attributeTableName + ".entity_id": goqu.I(st.entityTableName + ".id")})).
 		Where(goqu.C("entity_type").Eq(entityType)).
 		Where(goqu.And(goqu.C("attribute_key").Eq(attributeKey), goqu.C("attribute_value").Eq(attributeValue))).
-		Select("entity_id")
+		Select("entity_id").
+		Order(goqu.C("entity_id").Asc())
 
-	sqlStr, _, err := q.ToSQL()
+	sqlStr, params, err := q.ToSQL()
 
 	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
+		st.logError("EntityListByAttribute", err)
 		return nil, err
 	}
 
-	if st.GetDebug() {
-		log.Println(sqlStr)
-	}
-
-	rows, err := st.db.Query(sqlStr)
+	err = st.sqlSelect("EntityListByAttribute", st.db, false, &entityIDs, sqlStr, params...)
 
 	if err != nil {
-		return []Entity{}, err
-	}
-
-	for rows.Next() {
-		var entityID string
-		err := rows.Scan(&entityID)
-		if err != nil {
-			return []Entity{}, err
-		}
-		entityIDs = append(entityIDs, entityID)
-	}
-
-	if len(entityIDs) < 1 {
-		return entityList, nil
+		return nil, err
 	}
 
-	return st.EntityList(EntityQueryOptions{
-		EntityType: entityType,
-		IDs:        entityIDs,
-		SortBy:     "id",
-	})
+	return entityIDs, nil
 }
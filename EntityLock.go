@@ -0,0 +1,117 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// EntityLock claims an entity exclusively for the owner, for the ttl. It
+// fails with a *LockedError, matching ErrLocked, while another owner holds
+// an unexpired lease. An expired lease is taken over. Locking again as the
+// same owner extends the lease
+func (st *Store) EntityLock(entityID string, owner string, ttl time.Duration) (*EntityLease, error) {
+	if err := entityLockValidate(entityID, owner, ttl); err != nil {
+		return nil, err
+	}
+
+	return st.storage.entityLock(EntityLease{
+		EntityID:  entityID,
+		Owner:     owner,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	})
+}
+
+// entityLock claims an entity for the owner of the lease, unless locked by
+// another owner with an unexpired lease
+func (st *sqlStorage) entityLock(lease EntityLease) (*EntityLease, error) {
+	entityID, owner := lease.EntityID, lease.Owner
+
+	err := st.withTransaction("EntityLock", func(tx *sql.Tx) error {
+		if err := st.entityLockWriteFirst(tx, entityID); err != nil {
+			return err
+		}
+
+		current, err := st.entityLeaseFind(tx, entityID, true)
+
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			lease.Token = 1
+
+			q := st.dialect().Insert(st.lockTableName).Prepared(true)
+			q = q.Rows(goqu.Record{
+				"entity_id":  lease.EntityID,
+				"owner":      lease.Owner,
+				"token":      lease.Token,
+				"expires_at": lease.ExpiresAt,
+			})
+			sqlStr, params, errSql := q.ToSQL()
+
+			if errSql != nil {
+				return errSql
+			}
+
+			_, err := st.sqlExec("EntityLock", tx, false, sqlStr, params...)
+
+			return err
+		}
+
+		if current.Owner != owner && !current.Expired() {
+			return &LockedError{EntityID: entityID, Owner: current.Owner, ExpiresAt: current.ExpiresAt}
+		}
+
+		// The token read guards against a concurrent take over, where
+		// SELECT ... FOR UPDATE is not available
+		lease.Token = current.Token + 1
+
+		q := st.dialect().Update(st.lockTableName).Prepared(true).
+			Set(goqu.Record{
+				"owner":      lease.Owner,
+				"token":      lease.Token,
+				"expires_at": lease.ExpiresAt,
+			}).
+			Where(goqu.C("entity_id").Eq(entityID), goqu.C("token").Eq(current.Token))
+		sqlStr, params, errSql := q.ToSQL()
+
+		if errSql != nil {
+			return errSql
+		}
+
+		result, err := st.sqlExec("EntityLock", tx, false, sqlStr, params...)
+
+		if err != nil {
+			return err
+		}
+
+		updated, err := result.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		if updated == 0 {
+			return errors.New("entity store: lock of entity " + entityID + " was taken concurrently")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		// A lock taken concurrently fails the insert on the primary key, or
+		// the update on the token. It is reported as locked when held
+		if !errors.Is(err, ErrLocked) {
+			if lockedErr, _ := st.entityLockHeldByOther(st.db, entityID, owner); lockedErr != nil {
+				return nil, lockedErr
+			}
+		}
+
+		return nil, err
+	}
+
+	return &lease, nil
+}
@@ -0,0 +1,71 @@
+package entitystore
+
+import (
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// EntityLockRenew extends the lease of the owner on an entity by the ttl,
+// from now. It fails with ErrLockNotHeld when the owner does not hold the
+// lock anymore, also when its lease expired, or with a *LockedError when
+// another owner took it over. An expired lease is not renewed, as another
+// owner may have locked the entity meanwhile: the owner must lock it again,
+// getting a new token
+func (st *Store) EntityLockRenew(entityID string, owner string, ttl time.Duration) (*EntityLease, error) {
+	if err := entityLockValidate(entityID, owner, ttl); err != nil {
+		return nil, err
+	}
+
+	return st.storage.entityLockRenew(entityID, owner, time.Now().UTC().Add(ttl))
+}
+
+// entityLockRenew extends the unexpired lease of the owner
+func (st *sqlStorage) entityLockRenew(entityID string, owner string, expiresAt time.Time) (*EntityLease, error) {
+	q := st.dialect().Update(st.lockTableName).Prepared(true).
+		Set(goqu.Record{"expires_at": expiresAt}).
+		Where(
+			goqu.C("entity_id").Eq(entityID),
+			goqu.C("owner").Eq(owner),
+			goqu.C("expires_at").Gt(time.Now().UTC()),
+		)
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	result, err := st.sqlExec("EntityLockRenew", st.db, false, sqlStr, params...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := result.RowsAffected()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == 0 {
+		if lockedErr, err := st.entityLockHeldByOther(st.db, entityID, owner); err != nil {
+			return nil, err
+		} else if lockedErr != nil {
+			return nil, lockedErr
+		}
+
+		return nil, ErrLockNotHeld
+	}
+
+	lease, err := st.entityLeaseFind(st.db, entityID, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if lease == nil || lease.Owner != owner {
+		return nil, ErrLockNotHeld
+	}
+
+	return lease, nil
+}
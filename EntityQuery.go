@@ -1,6 +1,11 @@
 package entitystore
 
-import "github.com/doug-martin/goqu/v9"
+import (
+	"errors"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
 
 type EntityQueryOptions struct {
 	ID           string
@@ -13,10 +18,36 @@ type EntityQueryOptions struct {
 	SortBy       string
 	SortOrder    string // asc / dec
 	CountOnly    bool
+
+	// ListContains filters the entities whose list or set attribute,
+	// by attribute key, contains the value, i.e. {"tags": "go"}
+	ListContains map[string]string
+
+	// ListContainsAny filters the entities whose list or set attribute,
+	// by attribute key, contains any of the values
+	ListContainsAny map[string][]string
+
+	// UpdatedSince filters the entities written, with their attributes,
+	// at or after the time
+	UpdatedSince time.Time
+
+	// AfterID filters the entities with an ID greater than it, to page
+	// through the entities ordered by ID by the last ID of the previous
+	// page, rather than by offset
+	AfterID string
+}
+
+// entitySortableColumns are the columns entity queries can be sorted by
+var entitySortableColumns = map[string]bool{
+	"id":            true,
+	"entity_type":   true,
+	"entity_handle": true,
+	"created_at":    true,
+	"updated_at":    true,
 }
 
 func (st *Store) EntityQuery(options EntityQueryOptions) *goqu.SelectDataset {
-	q := goqu.Dialect(st.dbDriverName).From(st.entityTableName)
+	q := st.dialect().From(st.entityTableName).Prepared(true)
 
 	if len(options.IDs) > 0 {
 		q = q.Where(goqu.C("id").In(options.IDs))
@@ -37,6 +68,10 @@ func (st *Store) EntityQuery(options EntityQueryOptions) *goqu.SelectDataset {
 		sortByColumn = options.SortBy
 	}
 
+	if !entitySortableColumns[sortByColumn] {
+		return q.SetError(errors.New("entity sort column not supported: " + sortByColumn))
+	}
+
 	if sortOrder == "asc" {
 		q = q.Order(goqu.I(sortByColumn).Asc())
 	} else {
@@ -48,9 +83,19 @@ func (st *Store) EntityQuery(options EntityQueryOptions) *goqu.SelectDataset {
 	}
 
 	if options.EntityHandle != "" {
-		q = q.Where(goqu.C("entity_handle").Eq(options.EntityType))
+		q = q.Where(goqu.C("entity_handle").Eq(options.EntityHandle))
+	}
+
+	if options.AfterID != "" {
+		q = q.Where(goqu.C("id").Gt(options.AfterID))
 	}
 
+	if !options.UpdatedSince.IsZero() {
+		q = q.Where(goqu.C("updated_at").Gte(options.UpdatedSince))
+	}
+
+	q = st.entityQueryValueFilters(q, options)
+
 	q = q.Offset(uint(options.Offset))
 
 	if options.Limit != 0 {
@@ -0,0 +1,93 @@
+package entitystore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEntityQueryParameterized(t *testing.T) {
+	db := InitDB("test_entity_query.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	sqlStr, params, err := store.EntityQuery(EntityQueryOptions{
+		EntityType: "post'; DROP TABLE cms_entity; --",
+		Limit:      10,
+	}).ToSQL()
+
+	if err != nil {
+		t.Fatalf("Query could not be built: " + err.Error())
+	}
+
+	if strings.Contains(sqlStr, "DROP TABLE") {
+		t.Fatal("Values must not be interpolated into the SQL", sqlStr)
+	}
+
+	if len(params) != 2 {
+		t.Fatal("Params count incorrect", "must be 2", "found", len(params))
+	}
+
+	_, err = store.EntityList(EntityQueryOptions{
+		EntityType: "post'; DROP TABLE cms_entity; --",
+	})
+
+	if err != nil {
+		t.Fatalf("Entities could not be listed: " + err.Error())
+	}
+}
+
+func TestEntityQuerySortWhitelist(t *testing.T) {
+	db := InitDB("test_entity_query_sort.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	_, err = store.EntityList(EntityQueryOptions{
+		SortBy: "created_at",
+	})
+
+	if err != nil {
+		t.Fatalf("Entities could not be sorted by created_at: " + err.Error())
+	}
+
+	_, err = store.EntityList(EntityQueryOptions{
+		SortBy: "id; DROP TABLE cms_entity",
+	})
+
+	if err == nil {
+		t.Fatalf("Sorting by an unknown column must fail")
+	}
+
+	_, err = store.AttributeList(AttributeQueryOptions{
+		SortBy: "attribute_key",
+	})
+
+	if err != nil {
+		t.Fatalf("Attributes could not be sorted by attribute_key: " + err.Error())
+	}
+
+	_, err = store.AttributeList(AttributeQueryOptions{
+		SortBy: "unknown",
+	})
+
+	if err == nil {
+		t.Fatalf("Sorting by an unknown column must fail")
+	}
+}
@@ -0,0 +1,149 @@
+package entitystore
+
+import (
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// EntityRecord is an entity with all its attributes, as written by Export
+// and read by Import, one JSON object per line
+type EntityRecord struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"entity_type"`
+	Handle     string              `json:"entity_handle,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	Version    int64               `json:"version,omitempty"`
+	Attributes map[string]string   `json:"attributes"`
+	Lists      map[string][]string `json:"lists,omitempty"`
+}
+
+// entityPageSize is the number of entities read at once when scanning
+// the entities
+const entityPageSize = 100
+
+// entityPages calls fn with the pages of the entities matching the
+// options, ordered by ID. The pages are read by keyset, each starting
+// after the last ID of the previous page, so a page is found by the index
+// on the ID however deep the scan. The Limit and Offset of the options
+// apply to all the pages
+func (st *Store) entityPages(options EntityQueryOptions, pageSize uint64, fn func(entities []Entity) error) error {
+	limit := options.Limit
+	fetched := uint64(0)
+
+	options.SortBy = "id"
+	options.SortOrder = "asc"
+
+	for {
+		options.Limit = pageSize
+
+		if limit > 0 && limit-fetched < pageSize {
+			options.Limit = limit - fetched
+		}
+
+		entities, err := st.EntityList(options)
+
+		if err != nil {
+			return err
+		}
+
+		if len(entities) == 0 {
+			return nil
+		}
+
+		if err := fn(entities); err != nil {
+			return err
+		}
+
+		fetched += uint64(len(entities))
+		options.Offset = 0
+		options.AfterID = entities[len(entities)-1].ID()
+
+		if uint64(len(entities)) < options.Limit || (limit > 0 && fetched >= limit) {
+			return nil
+		}
+	}
+}
+
+// entityRecords returns the records of the entities, with their attributes
+// and the values of their list and set attributes
+func (st *Store) entityRecords(entities []Entity) ([]EntityRecord, error) {
+	entityIDs := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		entityIDs = append(entityIDs, entity.ID())
+	}
+
+	attributes, err := st.AttributeList(AttributeQueryOptions{EntityIDs: entityIDs})
+
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := st.storage.attributeValuesOfEntities(entityIDs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]EntityRecord, 0, len(entities))
+	index := map[string]int{}
+
+	for i, entity := range entities {
+		index[entity.ID()] = i
+		records = append(records, EntityRecord{
+			ID:         entity.ID(),
+			Type:       entity.Type(),
+			Handle:     entity.Handle(),
+			CreatedAt:  entity.CreatedAt(),
+			UpdatedAt:  entity.UpdatedAt(),
+			Version:    entity.Version(),
+			Attributes: map[string]string{},
+			Lists:      lists[entity.ID()],
+		})
+	}
+
+	for _, attr := range attributes {
+		if i, exists := index[attr.EntityID()]; exists {
+			records[i].Attributes[attr.AttributeKey()] = attr.AttributeValue()
+		}
+	}
+
+	return records, nil
+}
+
+// attributeValuesOfEntities returns the values of the list and set
+// attributes of the entities, by entity ID and attribute key
+func (st *sqlStorage) attributeValuesOfEntities(entityIDs []string) (map[string]map[string][]string, error) {
+	lists := map[string]map[string][]string{}
+
+	sqlStr, params, errSql := st.dialect().From(st.attributeValueTableName).Prepared(true).
+		Select("entity_id", "attribute_key", "attribute_value").
+		Where(goqu.C("entity_id").In(entityIDs)).
+		Order(goqu.C("entity_id").Asc(), goqu.C("attribute_key").Asc(), goqu.C("position").Asc()).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	rows := []struct {
+		EntityID       string `db:"entity_id"`
+		AttributeKey   string `db:"attribute_key"`
+		AttributeValue string `db:"attribute_value"`
+	}{}
+
+	if err := st.sqlSelect("AttributeValues", st.db, false, &rows, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if _, exists := lists[row.EntityID]; !exists {
+			lists[row.EntityID] = map[string][]string{}
+		}
+
+		lists[row.EntityID][row.AttributeKey] = append(lists[row.EntityID][row.AttributeKey], row.AttributeValue)
+	}
+
+	return lists, nil
+}
@@ -0,0 +1,158 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/georgysavva/scany/sqlscan"
+)
+
+// EntityRestore moves a trashed entity and its attributes, with the values
+// of the list and set attributes, back from the trash bin. Returns false
+// if the entity is not in the trash bin
+func (st *Store) EntityRestore(entityID string) (bool, error) {
+	if entityID == "" {
+		return false, errors.New("entity ID cannot be empty")
+	}
+
+	entity, attrs, err := st.storage.entityTrashFind(entityID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if entity == nil {
+		return false, nil
+	}
+
+	event := &EntityHookEvent{Entity: entity, Attributes: map[string]string{}}
+
+	for _, attr := range attrs {
+		event.Attributes[attr.AttributeKey()] = attr.AttributeValue()
+	}
+
+	change := ChangeEvent{
+		Operation:  ChangeRestore,
+		EntityID:   entity.ID(),
+		EntityType: entity.Type(),
+	}
+
+	err = st.withTransaction("EntityRestore", func(tx *sql.Tx) error {
+		event.Tx = tx
+
+		if err := st.entityHooksRun(EntityBeforeRestore, event); err != nil {
+			return err
+		}
+
+		if err := st.storage.entityRestore(tx, *entity, attrs); err != nil {
+			return err
+		}
+
+		if err := st.storage.entityVersionBump(tx, entityID, nil); err != nil {
+			return err
+		}
+
+		change.Attributes = event.Attributes
+
+		if err := st.changeRecord(tx, &change); err != nil {
+			return err
+		}
+
+		return st.entityHooksRun(EntityAfterRestore, event)
+	})
+
+	st.cacheInvalidate(entityID)
+
+	if err != nil {
+		return false, err
+	}
+
+	st.changePublish(change)
+
+	return true, nil
+}
+
+// entityTrashFind returns a trashed entity with its attributes, nil if the
+// entity is not in the trash bin
+func (st *sqlStorage) entityTrashFind(entityID string) (*Entity, []Attribute, error) {
+	sqlStr, params, errSql := st.dialect().From(st.entityTrashTableName).Prepared(true).
+		Select("id", "entity_type", "entity_handle", "created_at", "updated_at", "version").
+		Where(goqu.C("id").Eq(entityID)).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, nil, errSql
+	}
+
+	entityMap := map[string]string{}
+	err := st.sqlGet("EntityRestore", st.db, false, &entityMap, sqlStr, params...)
+
+	if sqlscan.NotFound(err) {
+		return nil, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqlStr, params, errSql = st.dialect().From(st.attributeTrashTableName).Prepared(true).
+		Select("id", "entity_id", "attribute_key", "attribute_value", "created_at", "updated_at").
+		Where(goqu.C("entity_id").Eq(entityID)).
+		Order(goqu.C("attribute_key").Asc()).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, nil, errSql
+	}
+
+	attributeMaps := []map[string]string{}
+	if err := st.sqlSelect("EntityRestore", st.db, false, &attributeMaps, sqlStr, params...); err != nil {
+		return nil, nil, err
+	}
+
+	attrs := []Attribute{}
+	for _, attributeMap := range attributeMaps {
+		attrs = append(attrs, *st.NewAttributeFromMap(attributeMap))
+	}
+
+	return st.NewEntityFromMap(entityMap), attrs, nil
+}
+
+// entityRestore inserts the trashed entity, attributes and values back
+// and removes them from the trash tables
+func (st *sqlStorage) entityRestore(tx *sql.Tx, entity Entity, attrs []Attribute) error {
+	if err := st.entityInsertWithTransactionOrDB(tx, entity); err != nil {
+		return err
+	}
+
+	for _, attr := range attrs {
+		if _, err := st.attributeInsertWithTransactionOrDB(tx, attr); err != nil {
+			return err
+		}
+	}
+
+	if err := st.attributeValuesRestore(tx, entity.ID()); err != nil {
+		return err
+	}
+
+	sqlStr1, params1, errSql := st.dialect().From(st.attributeTrashTableName).Prepared(true).Where(goqu.C("entity_id").Eq(entity.ID())).Delete().ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if _, err := st.sqlExec("EntityRestore", tx, false, sqlStr1, params1...); err != nil {
+		return err
+	}
+
+	sqlStr2, params2, errSql := st.dialect().From(st.entityTrashTableName).Prepared(true).Where(goqu.C("id").Eq(entity.ID())).Delete().ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("EntityRestore", tx, false, sqlStr2, params2...)
+
+	return err
+}
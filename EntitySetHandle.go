@@ -0,0 +1,37 @@
+package entitystore
+
+import "errors"
+
+// EntitySetHandle changes the handle of an entity. The previous handle
+// keeps resolving with EntityFindByHandle, and stays reserved to the
+// entity. It fails with a *HandleTakenError, matching ErrHandleTaken,
+// when the handle belongs to another entity of the type
+func (st *Store) EntitySetHandle(entityID string, entityHandle string) error {
+	if entityID == "" {
+		return errors.New("entity id cannot be empty")
+	}
+
+	if err := entityHandleValidate(entityHandle); err != nil {
+		return err
+	}
+
+	entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
+
+	if err != nil {
+		return err
+	}
+
+	if entity == nil {
+		return errors.New("entity store: entity " + entityID + " not found")
+	}
+
+	if entity.Handle() == entityHandle {
+		return nil
+	}
+
+	entity.SetHandle(entityHandle)
+
+	_, err = st.EntityUpdate(*entity)
+
+	return err
+}
@@ -1,75 +1,104 @@
 package entitystore
 
 import (
+	"database/sql"
 	"errors"
-	"log"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
 )
 
-// EntityTrash moves an entity and all attributes to the trash bin
+// EntityTrash moves an entity and all attributes, with the values of the
+// list and set attributes, to the trash bin. The entity keeps its handles,
+// current and past, so they are not given to another entity before it is
+// restored or deleted
 func (st *Store) EntityTrash(entityID string) (bool, error) {
 	if entityID == "" {
 		return false, errors.New("entity ID cannot be empty")
 	}
 
-	// Note the use of tx as the database handle once you are within a transaction
-	tx, err := st.db.Begin()
-
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
+	ent, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
 
 	if err != nil {
 		return false, err
 	}
 
-	ent, err := st.EntityFindByID(entityID)
+	if ent == nil {
+		return false, nil
+	}
+
+	isTrashed := false
+	event := &EntityHookEvent{Entity: ent}
+
+	change := ChangeEvent{
+		Operation:  ChangeTrash,
+		EntityID:   ent.ID(),
+		EntityType: ent.Type(),
+	}
+
+	err = st.withTransaction("EntityTrash", func(tx *sql.Tx) error {
+		event.Tx = tx
+
+		if err := st.entityHooksRun(EntityBeforeTrash, event); err != nil {
+			return err
+		}
+
+		var err error
+
+		isTrashed, err = st.storage.entityTrashMove(tx, *ent)
+
+		if err != nil || !isTrashed {
+			return err
+		}
+
+		if err := st.changeRecord(tx, &change); err != nil {
+			return err
+		}
+
+		return st.entityHooksRun(EntityAfterTrash, event)
+	})
+
+	st.cacheInvalidate(entityID)
 
 	if err != nil {
-		tx.Rollback()
 		return false, err
 	}
 
-	if ent == nil {
-		tx.Rollback()
-		return false, err
+	if isTrashed {
+		st.changePublish(change)
 	}
 
+	return isTrashed, nil
+}
+
+// entityTrashMove copies the entity, its attributes and the values of its
+// multi-valued attributes to the trash tables and deletes them
+func (st *sqlStorage) entityTrashMove(tx *sql.Tx, ent Entity) (bool, error) {
 	entTrash := EntityTrash{
 		ID:        ent.ID(),
 		Type:      ent.Type(),
+		Handle:    ent.Handle(),
 		CreatedAt: ent.CreatedAt(),
 		UpdatedAt: ent.UpdatedAt(),
+		Version:   ent.Version(),
 		DeletedAt: time.Now(),
 	}
 
-	q := goqu.Dialect(st.dbDriverName).Insert(st.entityTrashTableName)
+	q := st.dialect().Insert(st.entityTrashTableName).Prepared(true)
 	q = q.Rows(entTrash)
-	sqlStr, _, _ := q.ToSQL()
+	sqlStr, params, errSql := q.ToSQL()
 
-	if st.GetDebug() {
-		log.Println(sqlStr)
+	if errSql != nil {
+		return false, errSql
 	}
 
-	if _, err := tx.Exec(sqlStr); err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-		tx.Rollback()
+	if _, err := st.sqlExec("EntityTrash", tx, false, sqlStr, params...); err != nil {
 		return false, err
 	}
 
-	attrs, err := st.EntityAttributeList(entityID)
+	attrs, err := st.EntityAttributeList(ent.ID())
 
 	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-		tx.Rollback()
 		return false, err
 	}
 
@@ -84,54 +113,52 @@ func (st *Store) EntityTrash(entityID string) (bool, error) {
 			DeletedAt:      time.Now(),
 		}
 
-		q := goqu.Dialect(st.dbDriverName).Insert(st.attributeTrashTableName)
+		q := st.dialect().Insert(st.attributeTrashTableName).Prepared(true)
 		q = q.Rows(attrTrash)
-		sqlStrAttr, _, _ := q.ToSQL()
+		sqlStrAttr, paramsAttr, errSql := q.ToSQL()
 
-		if st.GetDebug() {
-			log.Println(sqlStrAttr)
+		if errSql != nil {
+			return false, errSql
 		}
 
-		if _, err := tx.Exec(sqlStrAttr); err != nil {
-			if st.GetDebug() {
-				log.Println(err)
-			}
-			tx.Rollback()
+		if _, err := st.sqlExec("EntityTrash", tx, false, sqlStrAttr, paramsAttr...); err != nil {
 			return false, err
 		}
 	}
 
-	q1 := goqu.Dialect(st.dbDriverName).From(st.attributeTableName).Where(goqu.C("entity_id").Eq(entityID)).Delete()
-	sqlStr1, _, _ := q1.ToSQL()
-
-	if _, err := tx.Exec(sqlStr1); err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-		tx.Rollback()
+	if err := st.attributeValuesTrash(tx, ent.ID()); err != nil {
 		return false, err
 	}
 
-	q2 := goqu.Dialect(st.dbDriverName).From(st.entityTableName).Where(goqu.C("id").Eq(entityID)).Delete()
-	sqlStr2, _, _ := q2.ToSQL()
+	q1 := st.dialect().From(st.attributeTableName).Prepared(true).Where(goqu.C("entity_id").Eq(ent.ID())).Delete()
+	sqlStr1, params1, errSql := q1.ToSQL()
 
-	if _, err := tx.Exec(sqlStr2); err != nil {
-		if st.GetDebug() {
-			log.Println(err)
-		}
-		tx.Rollback()
+	if errSql != nil {
+		return false, errSql
+	}
+
+	if _, err := st.sqlExec("EntityTrash", tx, false, sqlStr1, params1...); err != nil {
 		return false, err
 	}
 
-	err = tx.Commit()
+	q2 := st.dialect().From(st.entityTableName).Prepared(true).Where(goqu.C("id").Eq(ent.ID())).Delete()
+	sqlStr2, params2, errSql := q2.ToSQL()
 
-	if err == nil {
-		return true, nil
+	if errSql != nil {
+		return false, errSql
 	}
 
-	if st.GetDebug() {
-		log.Println(err)
+	result, err := st.sqlExec("EntityTrash", tx, false, sqlStr2, params2...)
+
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
 	}
 
-	return false, err
+	return deleted > 0, nil
 }
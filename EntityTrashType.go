@@ -11,6 +11,7 @@ type EntityTrash struct {
 	Handle    string    `db:"entity_handle"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+	Version   int64     `db:"version"`
 	DeletedAt time.Time `db:"deleted_at"`
 	DeletedBy string    `db:"deleted_by"`
 }
@@ -0,0 +1,38 @@
+package entitystore
+
+import (
+	"github.com/doug-martin/goqu/v9"
+)
+
+// EntityTypeCount is an entity type, with the number of its entities
+type EntityTypeCount struct {
+	EntityType string `db:"entity_type"`
+	Count      int64  `db:"count"`
+}
+
+// EntityTypes returns the entity types of the store, with the number of
+// their entities, ordered by type. The trashed entities are not counted
+func (st *Store) EntityTypes() ([]EntityTypeCount, error) {
+	return st.storage.entityTypes()
+}
+
+// entityTypes counts the entities by type in one query
+func (st *sqlStorage) entityTypes() ([]EntityTypeCount, error) {
+	sqlStr, params, errSql := st.dialect().From(st.entityTableName).Prepared(true).
+		Select(goqu.C("entity_type"), goqu.COUNT(goqu.Star()).As("count")).
+		GroupBy(goqu.C("entity_type")).
+		Order(goqu.C("entity_type").Asc()).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	types := []EntityTypeCount{}
+
+	if err := st.sqlSelect("EntityTypes", st.db, false, &types, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
@@ -0,0 +1,56 @@
+package entitystore
+
+import (
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// EntityUnlock releases the lock of the owner on an entity. Unlocking an
+// entity not locked by the owner anymore is a no-op, unless another owner
+// holds it, which fails with a *LockedError
+func (st *Store) EntityUnlock(entityID string, owner string) error {
+	if entityID == "" {
+		return errors.New("entity ID cannot be empty")
+	}
+
+	if owner == "" {
+		return errors.New("lock owner cannot be empty")
+	}
+
+	return st.storage.entityUnlock(entityID, owner)
+}
+
+// entityUnlock releases the lock of the owner
+func (st *sqlStorage) entityUnlock(entityID string, owner string) error {
+	sqlStr, params, errSql := st.dialect().From(st.lockTableName).Prepared(true).
+		Where(goqu.C("entity_id").Eq(entityID), goqu.C("owner").Eq(owner)).
+		Delete().
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	result, err := st.sqlExec("EntityUnlock", st.db, false, sqlStr, params...)
+
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		if lockedErr, err := st.entityLockHeldByOther(st.db, entityID, owner); err != nil {
+			return err
+		} else if lockedErr != nil {
+			return lockedErr
+		}
+	}
+
+	return nil
+}
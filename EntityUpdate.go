@@ -1,7 +1,7 @@
 package entitystore
 
 import (
-	"log"
+	"database/sql"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
@@ -9,26 +9,96 @@ import (
 
 // EntityUpdate updates an entity
 func (st *Store) EntityUpdate(ent Entity) (bool, error) {
-	ent.SetUpdatedAt(time.Now())
+	return st.entityUpdate(ent, nil)
+}
 
-	q := goqu.Dialect(st.dbDriverName).Update(st.GetEntityTableName())
-	q = q.Where(goqu.C("id").Eq(ent.ID)).Set(ent.ToMap())
+// entityUpdate updates an entity, incrementing its version. With ifVersion
+// the entity must be at that version
+func (st *Store) entityUpdate(ent Entity, ifVersion *int64) (bool, error) {
+	ent.SetUpdatedAt(time.Now())
 
-	sqlStr, _, _ := q.ToSQL()
+	event := &EntityHookEvent{Entity: &ent}
 
-	if st.GetDebug() {
-		log.Println(sqlStr)
+	change := ChangeEvent{
+		Operation:  ChangeUpdate,
+		EntityID:   ent.ID(),
+		EntityType: ent.Type(),
 	}
 
-	_, err := st.db.Exec(sqlStr)
+	err := st.withTransaction("EntityUpdate", func(tx *sql.Tx) error {
+		event.Tx = tx
+
+		if err := st.entityHooksRun(EntityBeforeUpdate, event); err != nil {
+			return err
+		}
+
+		if err := st.storage.entityUpdate(tx, ent, ifVersion); err != nil {
+			return err
+		}
+
+		if err := st.changeRecord(tx, &change); err != nil {
+			return err
+		}
+
+		return st.entityHooksRun(EntityAfterUpdate, event)
+	})
+
+	st.cacheInvalidate(ent.ID())
 
 	if err != nil {
-		if st.GetDebug() {
-			log.Println(err)
+		if ent.Handle() != "" {
+			return false, st.entityHandleTaken(err, ent.Type(), ent.Handle(), ent.ID())
 		}
 
 		return false, err
 	}
 
+	st.changePublish(change)
+
 	return true, nil
 }
+
+// entityUpdate replaces an entity, incrementing its version. With
+// ifVersion the entity must be at that version
+func (st *sqlStorage) entityUpdate(tx *sql.Tx, ent Entity, ifVersion *int64) error {
+	record := ent.ToMap()
+	record["version"] = entityVersionIncrement()
+
+	q := st.dialect().Update(st.GetEntityTableName()).Prepared(true)
+	q = q.Where(goqu.C("id").Eq(ent.ID())).Set(record)
+
+	if ifVersion != nil {
+		q = q.Where(goqu.C("version").Eq(*ifVersion))
+	}
+
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	result, err := st.sqlExec("EntityUpdate", tx, false, sqlStr, params...)
+
+	if err != nil {
+		return err
+	}
+
+	if ifVersion != nil {
+		updated, err := result.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		if updated == 0 {
+			return st.entityVersionConflict(tx, ent.ID(), *ifVersion)
+		}
+	}
+
+	// The previous handles stay recorded, they keep resolving
+	if ent.Handle() != "" {
+		return st.entityHandleReserve(tx, ent.Type(), ent.Handle(), ent.ID())
+	}
+
+	return nil
+}
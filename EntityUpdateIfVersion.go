@@ -0,0 +1,8 @@
+package entitystore
+
+// EntityUpdateIfVersion updates an entity only if it is still at the
+// version it was read at, i.e. not saved concurrently since. Otherwise a
+// *VersionConflictError, matching ErrVersionConflict, is returned
+func (st *Store) EntityUpdateIfVersion(ent Entity, version int64) (bool, error) {
+	return st.entityUpdate(ent, &version)
+}
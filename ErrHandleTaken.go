@@ -0,0 +1,24 @@
+package entitystore
+
+import "errors"
+
+// ErrHandleTaken is the error of giving an entity a handle already used by
+// another entity of the same type, currently or in the past. The returned
+// error is a *HandleTakenError, matched with errors.Is(err, ErrHandleTaken)
+var ErrHandleTaken = errors.New("entity store: handle is taken")
+
+// HandleTakenError is returned when a handle belongs to another entity
+type HandleTakenError struct {
+	EntityType string
+	Handle     string
+	EntityID   string
+}
+
+func (e *HandleTakenError) Error() string {
+	return ErrHandleTaken.Error() + ": handle " + e.Handle + " of type " + e.EntityType +
+		" belongs to entity " + e.EntityID
+}
+
+func (e *HandleTakenError) Unwrap() error {
+	return ErrHandleTaken
+}
@@ -0,0 +1,31 @@
+package entitystore
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrLocked is the error of claiming an entity locked by another owner.
+// The returned error is a *LockedError, matched with
+// errors.Is(err, ErrLocked)
+var ErrLocked = errors.New("entity store: entity is locked")
+
+// ErrLockNotHeld is returned by EntityLockRenew when the owner does not
+// hold the lock, i.e. as it was unlocked or expired and taken over
+var ErrLockNotHeld = errors.New("entity store: lock is not held")
+
+// LockedError is returned when an entity is locked by another owner
+type LockedError struct {
+	EntityID  string
+	Owner     string
+	ExpiresAt time.Time
+}
+
+func (e *LockedError) Error() string {
+	return ErrLocked.Error() + ": entity " + e.EntityID + " is locked by " + e.Owner +
+		" until " + e.ExpiresAt.Format(time.RFC3339)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
@@ -0,0 +1,30 @@
+package entitystore
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrVersionConflict is the error of a conditional write finding the
+// entity at another version than expected, i.e. as it was saved
+// concurrently. The returned error is a *VersionConflictError, matched with
+// errors.Is(err, ErrVersionConflict)
+var ErrVersionConflict = errors.New("entity store: version conflict")
+
+// VersionConflictError is returned by EntityUpdateIfVersion and
+// AttributesSetIfVersion when the entity is not at the expected version
+type VersionConflictError struct {
+	EntityID        string
+	ExpectedVersion int64
+	ActualVersion   int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return ErrVersionConflict.Error() + ": entity " + e.EntityID +
+		" is at version " + strconv.FormatInt(e.ActualVersion, 10) +
+		", expected " + strconv.FormatInt(e.ExpectedVersion, 10)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
@@ -0,0 +1,37 @@
+package entitystore
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Export writes the entities matching the filter, with all their
+// attributes, as NDJSON: one EntityRecord per line, ordered by ID. The
+// entities are read in pages, the export of a large store is streamed.
+// Returns the number of exported entities
+func (st *Store) Export(writer io.Writer, filter EntityQueryOptions) (int64, error) {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	exported := int64(0)
+
+	err := st.entityPages(filter, entityPageSize, func(entities []Entity) error {
+		records, err := st.entityRecords(entities)
+
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+
+			exported++
+		}
+
+		return nil
+	})
+
+	return exported, err
+}
@@ -0,0 +1,70 @@
+package entitystore
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// ExportCSV writes the entities of a type as CSV, with the columns "id",
+// "handle" and one column per attribute key. Without keys all the
+// attribute keys of the type are exported, ordered by key. Returns the
+// number of exported entities
+func (st *Store) ExportCSV(entityType string, attributeKeys []string, writer io.Writer) (int64, error) {
+	if entityType == "" {
+		return 0, errors.New("entity type cannot be empty")
+	}
+
+	if len(attributeKeys) == 0 {
+		keys, err := st.AttributeKeys(entityType)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for _, key := range keys {
+			attributeKeys = append(attributeKeys, key.Key)
+		}
+	}
+
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write(append([]string{"id", "handle"}, attributeKeys...)); err != nil {
+		return 0, err
+	}
+
+	exported := int64(0)
+
+	err := st.entityPages(EntityQueryOptions{EntityType: entityType}, entityPageSize, func(entities []Entity) error {
+		records, err := st.entityRecords(entities)
+
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			row := []string{record.ID, record.Handle}
+			for _, key := range attributeKeys {
+				row = append(row, record.Attributes[key])
+			}
+
+			if err := csvWriter.Write(row); err != nil {
+				return err
+			}
+
+			exported++
+		}
+
+		csvWriter.Flush()
+
+		return csvWriter.Error()
+	})
+
+	if err != nil {
+		return exported, err
+	}
+
+	csvWriter.Flush()
+
+	return exported, csvWriter.Error()
+}
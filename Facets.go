@@ -0,0 +1,73 @@
+package entitystore
+
+import (
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// FacetValue is a value of an attribute, with the number of entities
+// having it
+type FacetValue struct {
+	Value string
+	Count int64
+}
+
+// Facets counts the values of the attributes, by attribute key, of the
+// entities of a type having the attribute values of the filter. The values
+// of each attribute are ordered by count, the most frequent first, then by
+// value. With a positive limit only the most frequent values are returned
+func (st *Store) Facets(entityType string, attributeKeys []string, filter map[string]string, limit int) (map[string][]FacetValue, error) {
+	if entityType == "" {
+		return nil, errors.New("entity type cannot be empty")
+	}
+
+	facets := map[string][]FacetValue{}
+
+	for _, attributeKey := range attributeKeys {
+		if attributeKey == "" {
+			return nil, errors.New("attribute key cannot be empty")
+		}
+
+		values, err := st.storage.attributeValueCounts("Facets", entityType, attributeKey, filter, limit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		facets[attributeKey] = values
+	}
+
+	return facets, nil
+}
+
+// attributeValueCounts counts the values of an attribute of the entities
+// of a type having the attribute values of the filter, ordered by count
+func (st *sqlStorage) attributeValueCounts(op string, entityType string, attributeKey string, filter map[string]string, limit int) ([]FacetValue, error) {
+	q := st.dialect().From(goqu.T(st.attributeTableName).As("a")).Prepared(true).
+		Join(goqu.T(st.entityTableName).As("e"), goqu.On(goqu.I("e.id").Eq(goqu.I("a.entity_id")))).
+		Select(goqu.I("a.attribute_value").As("value"), goqu.COUNT(goqu.Star()).As("count")).
+		Where(goqu.I("e.entity_type").Eq(entityType), goqu.I("a.attribute_key").Eq(attributeKey)).
+		GroupBy(goqu.I("a.attribute_value")).
+		Order(goqu.I("count").Desc(), goqu.I("value").Asc())
+
+	q = st.attributeFilterWhere(q, "a.entity_id", filter)
+
+	if limit > 0 {
+		q = q.Limit(uint(limit))
+	}
+
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	values := []FacetValue{}
+
+	if err := st.sqlSelect(op, st.db, false, &values, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,207 @@
+package entitystore
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Conflict policies of an import, applied to the records of entities which
+// already exist
+const (
+	// ImportSkip keeps the existing entity unchanged. It is the default
+	ImportSkip = "skip"
+
+	// ImportOverwrite replaces the type, the handle, the timestamps and
+	// all the attributes of the existing entity with the record
+	ImportOverwrite = "overwrite"
+
+	// ImportMerge sets the attributes of the record on the existing entity,
+	// keeping its other attributes. A record of another type than the
+	// existing entity is an error of the record
+	ImportMerge = "merge"
+)
+
+// DefaultImportBatchSize is the number of records imported in a
+// transaction when none is given
+const DefaultImportBatchSize = 100
+
+// ImportOptions define the options of an import
+type ImportOptions struct {
+	// OnConflict is ImportSkip (the default), ImportOverwrite or
+	// ImportMerge
+	OnConflict string
+
+	// PreserveIDs creates the entities with the IDs of the records, and
+	// matches the existing entities by ID. Otherwise the entities get new
+	// IDs. The existing entities are also matched by type and handle
+	PreserveIDs bool
+
+	// BatchSize is the number of records imported in a transaction,
+	// DefaultImportBatchSize when zero
+	BatchSize int
+}
+
+// ImportReport reports the outcome of an import
+type ImportReport struct {
+	Created int64
+	Updated int64
+	Skipped int64
+
+	// Errors are the errors of the records which could not be imported
+	Errors []ImportError
+}
+
+// ImportError is the error of a record which could not be imported
+type ImportError struct {
+	// Line is the line of the record, starting at 1
+	Line     int
+	EntityID string
+	Err      error
+}
+
+func (e ImportError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e ImportError) Unwrap() error {
+	return e.Err
+}
+
+// importLine is a record read by Import, with its line
+type importLine struct {
+	line   int
+	record EntityRecord
+}
+
+// Import reads entities with their attributes as NDJSON, as written by
+// Export, creating or updating them in batches of a transaction each. A
+// record which cannot be imported is reported in the ImportReport, the
+// other records of its batch are still imported. The returned error is
+// the error of reading. Entity hooks are not run. Change events are
+// recorded and published for the created and updated entities, with the
+// attributes of the record
+func (st *Store) Import(reader io.Reader, options ImportOptions) (*ImportReport, error) {
+	if options.OnConflict == "" {
+		options.OnConflict = ImportSkip
+	}
+
+	if options.OnConflict != ImportSkip && options.OnConflict != ImportOverwrite && options.OnConflict != ImportMerge {
+		return nil, errors.New("import conflict policy not supported: " + options.OnConflict)
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultImportBatchSize
+	}
+
+	report := &ImportReport{}
+	batch := []importLine{}
+	buffered := bufio.NewReader(reader)
+
+	// The imported entities may be cached with their previous attributes
+	defer st.cacheClear()
+
+	for line := 1; ; line++ {
+		data, errRead := buffered.ReadBytes('\n')
+
+		if errRead != nil && errRead != io.EOF {
+			return report, errRead
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			record := EntityRecord{}
+
+			if err := json.Unmarshal(data, &record); err != nil {
+				report.Errors = append(report.Errors, ImportError{Line: line, Err: err})
+			} else if record.Type == "" {
+				report.Errors = append(report.Errors, ImportError{Line: line, EntityID: record.ID, Err: errors.New("entity type cannot be empty")})
+			} else {
+				batch = append(batch, importLine{line: line, record: record})
+			}
+		}
+
+		if len(batch) >= options.BatchSize || (errRead == io.EOF && len(batch) > 0) {
+			st.importBatch(batch, options, report)
+			batch = batch[:0]
+		}
+
+		if errRead == io.EOF {
+			return report, nil
+		}
+	}
+}
+
+// importBatch imports the records in a transaction. When a record fails
+// the transaction is rolled back, and the records are imported one by one
+// to report the failing records
+func (st *Store) importBatch(batch []importLine, options ImportOptions, report *ImportReport) {
+	if len(batch) > 1 {
+		outcomes := []string{}
+		changes := []*ChangeEvent{}
+
+		err := st.withTransaction("Import", func(tx *sql.Tx) error {
+			for _, line := range batch {
+				outcome, change, err := st.importRecord(tx, line.record, options)
+
+				if err != nil {
+					return err
+				}
+
+				outcomes = append(outcomes, outcome)
+				changes = append(changes, change)
+			}
+
+			return nil
+		})
+
+		if err == nil {
+			for i, outcome := range outcomes {
+				report.count(outcome)
+
+				if changes[i] != nil {
+					st.changePublish(*changes[i])
+				}
+			}
+
+			return
+		}
+	}
+
+	for _, line := range batch {
+		var outcome string
+		var change *ChangeEvent
+
+		err := st.withTransaction("Import", func(tx *sql.Tx) error {
+			var err error
+			outcome, change, err = st.importRecord(tx, line.record, options)
+			return err
+		})
+
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: line.line, EntityID: line.record.ID, Err: err})
+			continue
+		}
+
+		report.count(outcome)
+
+		if change != nil {
+			st.changePublish(*change)
+		}
+	}
+}
+
+// count counts the outcome of an imported record
+func (report *ImportReport) count(outcome string) {
+	switch outcome {
+	case importCreated:
+		report.Created++
+	case importUpdated:
+		report.Updated++
+	default:
+		report.Skipped++
+	}
+}
@@ -0,0 +1,173 @@
+package entitystore
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// CSVImportOptions define how the rows of a CSV file are imported
+type CSVImportOptions struct {
+	// Columns maps the columns, by header, to attribute keys. The other
+	// columns are ignored. When nil every column is the attribute of its
+	// header, except the "id" column and the HandleColumn
+	Columns map[string]string
+
+	// HandleColumn is the column holding the handle of the entities. A row
+	// updates the entity with the handle, or creates it
+	HandleColumn string
+
+	// KeyColumn is the column of an attribute identifying the entities,
+	// i.e. an email or a SKU. A row updates the entity having the
+	// attribute value, or creates it. Only one of HandleColumn and
+	// KeyColumn may be set
+	KeyColumn string
+
+	// DryRun validates the rows and reports what would be created and
+	// updated, without writing
+	DryRun bool
+}
+
+// ImportCSV creates or updates entities of a type from the rows of a CSV
+// file with a header row, setting their attributes with AttributesSet.
+// Without HandleColumn and KeyColumn every row creates an entity. A row
+// which cannot be imported is reported in the ImportReport, with its line,
+// the header being line 1. The returned error is the error of reading
+func (st *Store) ImportCSV(entityType string, reader io.Reader, options CSVImportOptions) (*ImportReport, error) {
+	if entityType == "" {
+		return nil, errors.New("entity type cannot be empty")
+	}
+
+	if options.HandleColumn != "" && options.KeyColumn != "" {
+		return nil, errors.New("only one of the handle column and the key column can be set")
+	}
+
+	csvReader := csv.NewReader(reader)
+
+	header, err := csvReader.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	for _, column := range []string{options.HandleColumn, options.KeyColumn} {
+		if _, exists := columns[column]; column != "" && !exists {
+			return nil, errors.New("column " + column + " not found in the header")
+		}
+	}
+
+	mapping := options.Columns
+
+	if mapping == nil {
+		mapping = map[string]string{}
+		for _, name := range header {
+			if name != "id" && name != options.HandleColumn {
+				mapping[name] = name
+			}
+		}
+	}
+
+	keyAttribute := ""
+
+	if options.KeyColumn != "" {
+		if keyAttribute = mapping[options.KeyColumn]; keyAttribute == "" {
+			return nil, errors.New("key column " + options.KeyColumn + " is not mapped to an attribute")
+		}
+	}
+
+	report := &ImportReport{}
+
+	for line := 2; ; line++ {
+		row, err := csvReader.Read()
+
+		if err == io.EOF {
+			return report, nil
+		}
+
+		var parseErr *csv.ParseError
+
+		if errors.As(err, &parseErr) {
+			report.Errors = append(report.Errors, ImportError{Line: line, Err: err})
+			continue
+		}
+
+		if err != nil {
+			return report, err
+		}
+
+		attributes := map[string]string{}
+		for column, key := range mapping {
+			if i, exists := columns[column]; exists {
+				attributes[key] = row[i]
+			}
+		}
+
+		handle := ""
+		if options.HandleColumn != "" {
+			handle = row[columns[options.HandleColumn]]
+		}
+
+		entityID, outcome, err := st.importCSVRow(entityType, handle, keyAttribute, attributes, options.DryRun)
+
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: line, EntityID: entityID, Err: err})
+			continue
+		}
+
+		report.count(outcome)
+	}
+}
+
+// importCSVRow creates or updates the entity of a CSV row, matched by
+// handle or by the key attribute, returning its ID and the outcome
+func (st *Store) importCSVRow(entityType string, handle string, keyAttribute string, attributes map[string]string, dryRun bool) (string, string, error) {
+	if handle != "" {
+		if err := entityHandleValidate(handle); err != nil {
+			return "", "", err
+		}
+	}
+
+	var existing *Entity
+	var err error
+
+	if handle != "" {
+		existing, err = st.EntityFindByHandle(entityType, handle)
+	} else if keyAttribute != "" && attributes[keyAttribute] != "" {
+		existing, err = st.EntityFindByAttribute(entityType, keyAttribute, attributes[keyAttribute])
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if existing != nil {
+		if !dryRun {
+			err = st.AttributesSet(existing.ID(), attributes)
+		}
+
+		return existing.ID(), importUpdated, err
+	}
+
+	if dryRun {
+		return "", importCreated, nil
+	}
+
+	var entity *Entity
+
+	if handle != "" {
+		entity, err = st.EntityCreateWithHandle(entityType, handle, attributes)
+	} else {
+		entity, err = st.EntityCreateWithAttributes(entityType, attributes)
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return entity.ID(), importCreated, nil
+}
@@ -0,0 +1,131 @@
+package entitystore
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultLRUCacheSize is the number of keys kept by an LRUCache when no
+// size is given
+const DefaultLRUCacheSize = 10000
+
+// LRUCache is an in-memory Cache keeping the most recently used keys, each
+// for up to a time to live
+type LRUCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruCacheEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+var _ Cache = (*LRUCache)(nil)
+
+// NewLRUCache creates an LRU cache of up to size keys, DefaultLRUCacheSize
+// when zero, each kept for the ttl. A zero ttl keeps the keys until
+// evicted or written
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	if size <= 0 {
+		size = DefaultLRUCacheSize
+	}
+
+	return &LRUCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+// Get returns the value of the key, unless expired
+func (c *LRUCache) Get(key string) (any, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, exists := c.entries[key]
+
+	if !exists {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruCacheEntry)
+
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// Set stores the value of the key, evicting the least recently used key
+// when full
+func (c *LRUCache) Set(key string, value any) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt := time.Time{}
+	if c.ttl > 0 {
+		expiresAt = time.Now().Add(c.ttl)
+	}
+
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*lruCacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruCacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes the key
+func (c *LRUCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.remove(element)
+	}
+}
+
+// Clear removes all the keys
+func (c *LRUCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// Len returns the number of keys kept, including the expired ones not
+// removed yet
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruCacheEntry).key)
+}
@@ -0,0 +1,56 @@
+package entitystore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2, 0)
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	// Reading a makes b the least recently used key
+	if value, found := cache.Get("a"); !found || value != 1 {
+		t.Fatalf("Expected a to be 1, found %v", value)
+	}
+
+	cache.Set("c", 3)
+
+	if _, found := cache.Get("b"); found {
+		t.Fatalf("Expected b to be evicted")
+	}
+
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 keys, found %d", cache.Len())
+	}
+
+	cache.Delete("a")
+
+	if _, found := cache.Get("a"); found {
+		t.Fatalf("Expected a to be deleted")
+	}
+
+	cache.Clear()
+
+	if cache.Len() != 0 {
+		t.Fatalf("Expected no keys after Clear, found %d", cache.Len())
+	}
+}
+
+func TestLRUCacheTTL(t *testing.T) {
+	cache := NewLRUCache(0, 20*time.Millisecond)
+
+	cache.Set("a", 1)
+
+	if _, found := cache.Get("a"); !found {
+		t.Fatalf("Expected a to be found")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, found := cache.Get("a"); found {
+		t.Fatalf("Expected a to be expired")
+	}
+}
@@ -0,0 +1,163 @@
+package entitystore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultMetricsBuckets are the default upper bounds, in seconds, of the
+// query duration histogram buckets
+var DefaultMetricsBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// MetricsHook is a QueryHook keeping an in-process registry of Prometheus
+// style metrics of the executed statements, per operation:
+//   - entitystore_queries_total, a counter labelled with the status, ok or error
+//   - entitystore_query_duration_seconds, a histogram of the durations
+//   - entitystore_query_rows_total, a counter of the rows affected or returned
+//
+// The metrics are exposed in the Prometheus text format by WritePrometheus,
+// and served by ServeHTTP for scraping
+type MetricsHook struct {
+	mutex      sync.Mutex
+	buckets    []float64
+	operations map[string]*operationMetrics
+}
+
+// operationMetrics are the metrics of a single operation
+type operationMetrics struct {
+	okTotal      uint64
+	errorTotal   uint64
+	rowsTotal    uint64
+	bucketCounts []uint64
+	durationSum  float64
+}
+
+var _ QueryHook = (*MetricsHook)(nil)
+var _ http.Handler = (*MetricsHook)(nil)
+
+// NewMetricsHook creates a metrics registry hook. The duration histogram
+// uses the given bucket upper bounds, in seconds, or DefaultMetricsBuckets
+func NewMetricsHook(buckets ...float64) *MetricsHook {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+
+	return &MetricsHook{
+		buckets:    sorted,
+		operations: map[string]*operationMetrics{},
+	}
+}
+
+// BeforeQuery does nothing, the metrics are recorded after the query
+func (h *MetricsHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery records the metrics of the completed statement
+func (h *MetricsHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	metrics, exists := h.operations[event.Operation]
+
+	if !exists {
+		metrics = &operationMetrics{bucketCounts: make([]uint64, len(h.buckets))}
+		h.operations[event.Operation] = metrics
+	}
+
+	if event.Error != nil {
+		metrics.errorTotal++
+	} else {
+		metrics.okTotal++
+	}
+
+	if event.Rows > 0 {
+		metrics.rowsTotal += uint64(event.Rows)
+	}
+
+	seconds := event.Duration.Seconds()
+	metrics.durationSum += seconds
+
+	for i, bound := range h.buckets {
+		if seconds <= bound {
+			metrics.bucketCounts[i]++
+		}
+	}
+}
+
+// WritePrometheus writes the metrics in the Prometheus text format
+func (h *MetricsHook) WritePrometheus(w io.Writer) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	operations := make([]string, 0, len(h.operations))
+	for operation := range h.operations {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+
+	lines := []string{
+		"# HELP entitystore_queries_total Statements executed by the entity store.",
+		"# TYPE entitystore_queries_total counter",
+	}
+
+	for _, operation := range operations {
+		metrics := h.operations[operation]
+		lines = append(lines,
+			fmt.Sprintf(`entitystore_queries_total{operation=%q,status="ok"} %d`, operation, metrics.okTotal),
+			fmt.Sprintf(`entitystore_queries_total{operation=%q,status="error"} %d`, operation, metrics.errorTotal),
+		)
+	}
+
+	lines = append(lines,
+		"# HELP entitystore_query_duration_seconds Duration of the statements executed by the entity store.",
+		"# TYPE entitystore_query_duration_seconds histogram",
+	)
+
+	for _, operation := range operations {
+		metrics := h.operations[operation]
+		count := metrics.okTotal + metrics.errorTotal
+
+		for i, bound := range h.buckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			lines = append(lines, fmt.Sprintf(`entitystore_query_duration_seconds_bucket{operation=%q,le=%q} %d`, operation, le, metrics.bucketCounts[i]))
+		}
+
+		lines = append(lines,
+			fmt.Sprintf(`entitystore_query_duration_seconds_bucket{operation=%q,le="+Inf"} %d`, operation, count),
+			fmt.Sprintf(`entitystore_query_duration_seconds_sum{operation=%q} %s`, operation, strconv.FormatFloat(metrics.durationSum, 'g', -1, 64)),
+			fmt.Sprintf(`entitystore_query_duration_seconds_count{operation=%q} %d`, operation, count),
+		)
+	}
+
+	lines = append(lines,
+		"# HELP entitystore_query_rows_total Rows affected or returned by the statements executed by the entity store.",
+		"# TYPE entitystore_query_rows_total counter",
+	)
+
+	for _, operation := range operations {
+		lines = append(lines, fmt.Sprintf(`entitystore_query_rows_total{operation=%q} %d`, operation, h.operations[operation].rowsTotal))
+	}
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (h *MetricsHook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	h.WritePrometheus(w)
+}
@@ -0,0 +1,268 @@
+package entitystore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/georgysavva/scany/sqlscan"
+	"github.com/golang-module/carbon/v2"
+)
+
+// Migrate applies the pending schema migrations in order, recording each
+// applied migration in the schema version table.
+//
+// Migrate is safe to call from several application instances at once.
+// A database lock is held while migrating (an advisory lock on PostgreSQL,
+// MySQL and SQL Server, the database write lock on SQLite), and a
+// migration applied concurrently by another instance is skipped
+func (st *Store) Migrate() error {
+	return st.storage.migrate()
+}
+
+// migrate applies the pending migrations holding the migration lock
+func (st *sqlStorage) migrate() error {
+	ctx := context.Background()
+
+	conn, err := st.db.Conn(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	unlock, err := st.migrationLock(ctx, conn)
+
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	sqlStr, err := st.sqlCreateSchemaVersionTable()
+
+	if err != nil {
+		return err
+	}
+
+	queryCtx, event := st.queryStart(ctx, "Migrate", sqlStr, nil)
+	_, err = conn.ExecContext(queryCtx, sqlStr)
+	st.queryEnd(event, 0, err)
+
+	if err != nil {
+		return err
+	}
+
+	applied, err := st.migrationsApplied(ctx, "Migrate", conn)
+
+	if err != nil {
+		return err
+	}
+
+	for _, m := range st.migrations() {
+		if _, exists := applied[m.version]; exists {
+			continue
+		}
+
+		errApply := st.migrationApply(ctx, conn, m)
+
+		if errApply == nil {
+			continue
+		}
+
+		// Another instance may have applied the migration in the meantime
+		applied, err = st.migrationsApplied(ctx, "Migrate", conn)
+
+		if err != nil {
+			return err
+		}
+
+		if _, exists := applied[m.version]; exists {
+			continue
+		}
+
+		return fmt.Errorf("entity store: migration %d (%s) failed: %w", m.version, m.description, errApply)
+	}
+
+	return nil
+}
+
+// migrationApply runs the statements of a migration and records it as
+// applied, in a single transaction where the database supports
+// transactional DDL
+func (st *Store) migrationApply(ctx context.Context, conn *sql.Conn, m migration) error {
+	sqls, err := m.up(st)
+
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for _, sqlStr := range sqls {
+		queryCtx, event := st.queryStart(ctx, "Migrate", sqlStr, nil)
+		_, err := tx.ExecContext(queryCtx, sqlStr)
+		st.queryEnd(event, 0, err)
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// The version is recorded last, as MySQL commits implicitly on DDL. A
+	// migration failing halfway there is applied again, its statements
+	// being idempotent
+	q := st.dialect().Insert(st.schemaVersionTableName).Prepared(true)
+	q = q.Rows(goqu.Record{
+		"version":     m.version,
+		"description": m.description,
+		"applied_at":  time.Now(),
+	})
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		tx.Rollback()
+		return errSql
+	}
+
+	queryCtx, event := st.queryStart(ctx, "Migrate", sqlStr, params)
+	_, err = tx.ExecContext(queryCtx, sqlStr, params...)
+	st.queryEnd(event, 1, err)
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrationsApplied returns the applied migration versions with the time
+// they were applied at
+func (st *Store) migrationsApplied(ctx context.Context, op string, db sqlscan.Querier) (map[int]time.Time, error) {
+	sqlStr, params, errSql := st.dialect().
+		From(st.schemaVersionTableName).
+		Prepared(true).
+		Select("version", "applied_at").
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	queryCtx, event := st.queryStart(ctx, op, sqlStr, params)
+	rows := []map[string]string{}
+	err := sqlscan.Select(queryCtx, db, &rows, sqlStr, params...)
+	st.queryEnd(event, int64(len(rows)), err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int]time.Time{}
+
+	for _, row := range rows {
+		version, err := strconv.Atoi(row["version"])
+
+		if err != nil {
+			return nil, err
+		}
+
+		applied[version] = carbon.Parse(row["applied_at"], carbon.UTC).ToStdTime()
+	}
+
+	return applied, nil
+}
+
+// migrationLock acquires the lock serializing migrations across
+// instances, and returns the function releasing it
+func (st *Store) migrationLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if st.dbDriverName == "postgres" {
+		key := st.migrationLockKey()
+
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+			return nil, err
+		}
+
+		return func() {
+			conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+		}, nil
+	}
+
+	if st.dbDriverName == "mysql" {
+		var acquired sql.NullInt64
+
+		err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", st.migrationLockName()).Scan(&acquired)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if acquired.Int64 != 1 {
+			return nil, errors.New("entity store: timed out waiting for migration lock " + st.migrationLockName())
+		}
+
+		return func() {
+			conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", st.migrationLockName())
+		}, nil
+	}
+
+	if st.dbDriverName == "mssql" {
+		var status int64
+
+		err := conn.QueryRowContext(ctx, `
+		DECLARE @status int;
+		EXEC @status = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = 60000;
+		SELECT @status;
+		`, st.migrationLockName()).Scan(&status)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if status < 0 {
+			return nil, errors.New("entity store: timed out waiting for migration lock " + st.migrationLockName())
+		}
+
+		return func() {
+			conn.ExecContext(ctx, "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", st.migrationLockName())
+		}, nil
+	}
+
+	if st.dbDriverName == "sqlite" {
+		// SQLite serializes writers itself. Wait for the write lock held
+		// by a concurrent migration instead of failing straight away
+		var busyTimeout int64
+
+		if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+			return nil, err
+		}
+
+		if _, err := conn.ExecContext(ctx, "PRAGMA busy_timeout = 60000"); err != nil {
+			return nil, err
+		}
+
+		return func() {
+			conn.ExecContext(ctx, "PRAGMA busy_timeout = "+strconv.FormatInt(busyTimeout, 10))
+		}, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
@@ -0,0 +1,250 @@
+package entitystore
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+func TestMigrate(t *testing.T) {
+	db := InitDB("test_migrate.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	status, err := store.MigrationStatus()
+
+	if err != nil {
+		t.Fatalf("Migration status failed: " + err.Error())
+	}
+
+	if len(status) == 0 {
+		t.Fatalf("Migrations must be listed")
+	}
+
+	for _, m := range status {
+		if m.Applied {
+			t.Fatal("Migration must be pending", m.Version)
+		}
+	}
+
+	if err := store.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: " + err.Error())
+	}
+
+	// Running again is a no-op
+	if err := store.Migrate(); err != nil {
+		t.Fatalf("Migrate failed on second run: " + err.Error())
+	}
+
+	status, err = store.MigrationStatus()
+
+	if err != nil {
+		t.Fatalf("Migration status failed: " + err.Error())
+	}
+
+	for _, m := range status {
+		if !m.Applied {
+			t.Fatal("Migration must be applied", m.Version)
+		}
+
+		if m.AppliedAt.IsZero() {
+			t.Fatal("Migration applied at must be set", m.Version)
+		}
+	}
+}
+
+func TestMigrateConcurrently(t *testing.T) {
+	db := InitDB("test_migrate_concurrently.db")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			store, err := NewStore(NewStoreOptions{
+				DB:                 db,
+				EntityTableName:    "cms_entity",
+				AttributeTableName: "cms_attribute",
+			})
+
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			errs <- store.Migrate()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Concurrent migrate failed: " + err.Error())
+		}
+	}
+}
+
+func TestMigrateUnsupportedDriver(t *testing.T) {
+	db := InitDB("test_migrate_unsupported.db")
+
+	_, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		DbDriverName:       "unknown",
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err == nil {
+		t.Fatalf("Automigrate must fail for an unsupported driver")
+	}
+}
+
+func TestMigrationsMysqlIdempotent(t *testing.T) {
+	db := InitDB("test_migrate_mysql.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		DbDriverName:       "mysql",
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	for _, m := range store.migrations() {
+		sqls, err := m.up(store)
+
+		if err != nil {
+			t.Fatalf("MySQL migration not supported: " + err.Error())
+		}
+
+		for _, sqlStr := range sqls {
+			statement := strings.TrimSpace(sqlStr)
+
+			isIndex := strings.HasPrefix(statement, "CREATE INDEX") || strings.HasPrefix(statement, "CREATE UNIQUE INDEX")
+			isColumn := strings.HasPrefix(statement, "ALTER TABLE") && strings.Contains(statement, "ADD COLUMN")
+
+			if isIndex || isColumn {
+				t.Fatal("MySQL DDL of migration", m.version, "must be guarded", statement)
+			}
+
+			if strings.HasPrefix(statement, "SET @entitystore_ddl") && !strings.Contains(statement, "information_schema") {
+				t.Fatal("MySQL DDL of migration", m.version, "must be checked on information_schema", statement)
+			}
+		}
+	}
+}
+
+func TestSqlCreateTableMysqlIdempotent(t *testing.T) {
+	db := InitDB("test_create_table_mysql.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		DbDriverName:       "mysql",
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	sqls, err := store.SqlCreateTable()
+
+	if err != nil {
+		t.Fatalf("MySQL create table not supported: " + err.Error())
+	}
+
+	for _, sqlStr := range sqls {
+		statement := strings.TrimSpace(sqlStr)
+
+		if strings.HasPrefix(statement, "CREATE INDEX") || strings.HasPrefix(statement, "CREATE UNIQUE INDEX") {
+			t.Fatal("MySQL index must be guarded", statement)
+		}
+	}
+}
+
+func TestMigrateHandleBackfill(t *testing.T) {
+	db := InitDB("test_migrate_handle_backfill.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	// Entities sharing a handle, created before the handle table
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	rows := []any{
+		goqu.Record{"id": "1", "entity_type": "page", "entity_handle": "about", "created_at": newer, "updated_at": newer, "version": 1},
+		goqu.Record{"id": "2", "entity_type": "page", "entity_handle": "about", "created_at": older, "updated_at": older, "version": 1},
+		goqu.Record{"id": "4", "entity_type": "page", "entity_handle": "contact", "created_at": older, "updated_at": older, "version": 1},
+		goqu.Record{"id": "3", "entity_type": "page", "entity_handle": "contact", "created_at": older, "updated_at": older, "version": 1},
+	}
+
+	sqlStr, params, err := store.dialect().Insert(store.entityTableName).Prepared(true).Rows(rows...).ToSQL()
+
+	if err != nil {
+		t.Fatalf("SQL could not be built: " + err.Error())
+	}
+
+	if _, err := db.Exec(sqlStr, params...); err != nil {
+		t.Fatalf("Entities could not be inserted: " + err.Error())
+	}
+
+	if _, err := db.Exec(`DELETE FROM "` + store.handleTableName + `"`); err != nil {
+		t.Fatalf("Handles could not be deleted: " + err.Error())
+	}
+
+	statements, err := store.sqlCreateHandleTable()
+
+	if err != nil {
+		t.Fatalf("SQL could not be built: " + err.Error())
+	}
+
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Handle table could not be created: " + err.Error())
+		}
+	}
+
+	// The oldest entity keeps the handle, by creation time then ID
+	expected := map[string]string{"about": "2", "contact": "3"}
+
+	for handle, entityID := range expected {
+		found, err := (&sqlStorage{store}).entityHandleFind(db, "page", handle)
+
+		if err != nil {
+			t.Fatalf("Handle could not be found: " + err.Error())
+		}
+
+		if found != entityID {
+			t.Fatal("Handle owner incorrect", "must be", entityID, "found", found)
+		}
+	}
+}
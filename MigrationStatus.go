@@ -0,0 +1,57 @@
+package entitystore
+
+import (
+	"context"
+	"time"
+)
+
+// Migration describes a schema migration of the store
+type Migration struct {
+	Version     int
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// MigrationStatus lists the schema migrations of the store, with whether
+// and when each of them was applied
+func (st *Store) MigrationStatus() ([]Migration, error) {
+	return st.storage.migrationStatus()
+}
+
+// migrationStatus lists the migrations with the versions recorded in the
+// schema version table
+func (st *sqlStorage) migrationStatus() ([]Migration, error) {
+	ctx := context.Background()
+
+	exists, err := st.tableExists(ctx, "MigrationStatus", st.db, st.schemaVersionTableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int]time.Time{}
+
+	if exists {
+		applied, err = st.migrationsApplied(ctx, "MigrationStatus", st.db)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := []Migration{}
+
+	for _, m := range st.migrations() {
+		appliedAt, isApplied := applied[m.version]
+
+		status = append(status, Migration{
+			Version:     m.version,
+			Description: m.description,
+			Applied:     isApplied,
+			AppliedAt:   appliedAt,
+		})
+	}
+
+	return status, nil
+}
@@ -8,6 +8,7 @@ type NewEntityOptions struct {
 	Handle    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Version   int64
 }
 
 func (st *Store) NewEntity(opts NewEntityOptions) *Entity {
@@ -17,6 +18,7 @@ func (st *Store) NewEntity(opts NewEntityOptions) *Entity {
 	entity.SetHandle(opts.Handle)
 	entity.SetCreatedAt(opts.CreatedAt)
 	entity.SetUpdatedAt(opts.UpdatedAt)
+	entity.SetVersion(opts.Version)
 	entity.st = st
 	return &entity
 }
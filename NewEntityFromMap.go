@@ -1,6 +1,10 @@
 package entitystore
 
-import "github.com/golang-module/carbon/v2"
+import (
+	"strconv"
+
+	"github.com/golang-module/carbon/v2"
+)
 
 func (st *Store) NewEntityFromMap(entityMap map[string]string) *Entity {
 	opts := NewEntityOptions{}
@@ -19,6 +23,9 @@ func (st *Store) NewEntityFromMap(entityMap map[string]string) *Entity {
 	if updatedAt, exists := entityMap["updated_at"]; exists {
 		opts.UpdatedAt = carbon.Parse(updatedAt, carbon.UTC).ToStdTime()
 	}
+	if version, exists := entityMap["version"]; exists {
+		opts.Version, _ = strconv.ParseInt(version, 10, 64)
+	}
 
 	return st.NewEntity(opts)
 }
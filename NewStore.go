@@ -3,6 +3,7 @@ package entitystore
 import (
 	"database/sql"
 	"errors"
+	"log/slog"
 )
 
 // NewStore creates a new entity store
@@ -30,28 +31,109 @@ import (
 // 	return store, nil
 // }
 
+// Storage backends of a store
+const (
+	// BackendSQL keeps the entities in the SQL database given by the DB
+	// option. It is the default backend
+	BackendSQL = "sql"
+
+	// BackendMemory keeps the entities in memory, needing no database.
+	// Meant for tests and embedded use, as nothing is persisted
+	BackendMemory = "memory"
+)
+
 // NewStoreOptions define the options for creating a new session store
 type NewStoreOptions struct {
+	// Backend is the storage backend, BackendSQL when empty
+	Backend string
+
 	EntityTableName         string
 	AttributeTableName      string
 	EntityTrashTableName    string
 	AttributeTrashTableName string
+	SchemaVersionTableName  string
 	DB                      *sql.DB
 	DbDriverName            string
 	AutomigrateEnabled      bool
 	DebugEnabled            bool
+
+	// Logger receives structured records of the executed statements, at
+	// debug level, and of the errors. When nil the records are written to
+	// the standard logger while debug is enabled
+	Logger *slog.Logger
+
+	// OutboxEnabled records a change event of every mutation in the outbox
+	// table, in the transaction of the mutation, read with ChangeFeed
+	OutboxEnabled bool
+
+	// OutboxTableName is the name of the outbox table, by default the
+	// entity table name suffixed with "_outbox"
+	OutboxTableName string
+
+	// LockTableName is the name of the table of the entity locks, by
+	// default the entity table name suffixed with "_lock"
+	LockTableName string
+
+	// AttributeValueTableName is the name of the table of the values of
+	// the list and set attributes, by default the attribute table name
+	// suffixed with "_value"
+	AttributeValueTableName string
+
+	// AttributeValueTrashTableName is the name of the table of the values
+	// of the list and set attributes of the trashed entities, by default
+	// the attribute value table name suffixed with "_trash"
+	AttributeValueTrashTableName string
+
+	// HandleTableName is the name of the table of the entity handles,
+	// current and past, by default the entity table name suffixed with
+	// "_handle"
+	HandleTableName string
+
+	// QueryHooks are called around every executed statement, for metrics
+	// and tracing. See NewMetricsHook and NewTracingHook
+	QueryHooks []QueryHook
+
+	// Cache is a read-through cache of EntityFindByID, EntityFindByHandle
+	// and AttributeFind, i.e. NewLRUCache(10000, time.Minute). It is
+	// invalidated on every write of the store. Writes by other processes
+	// to the same database are only seen once the cached values expire
+	Cache Cache
+
+	// StatementCacheEnabled keeps the statements of the most frequent
+	// queries (EntityFindByID, AttributeFind, AttributeSetString) prepared
+	// for reuse. Call Close to release them
+	StatementCacheEnabled bool
 }
 
 func NewStore(opts NewStoreOptions) (*Store, error) {
 	store := &Store{
-		entityTableName:         opts.EntityTableName,
-		attributeTableName:      opts.AttributeTableName,
-		entityTrashTableName:    opts.EntityTrashTableName,
-		attributeTrashTableName: opts.AttributeTrashTableName,
-		automigrateEnabled:      opts.AutomigrateEnabled,
-		db:                      opts.DB,
-		dbDriverName:            opts.DbDriverName,
-		debugEnabled:            opts.DebugEnabled,
+		entityTableName:              opts.EntityTableName,
+		attributeTableName:           opts.AttributeTableName,
+		entityTrashTableName:         opts.EntityTrashTableName,
+		attributeTrashTableName:      opts.AttributeTrashTableName,
+		attributeValueTableName:      opts.AttributeValueTableName,
+		attributeValueTrashTableName: opts.AttributeValueTrashTableName,
+		schemaVersionTableName:       opts.SchemaVersionTableName,
+		automigrateEnabled:           opts.AutomigrateEnabled,
+		db:                           opts.DB,
+		dbDriverName:                 opts.DbDriverName,
+		debugEnabled:                 opts.DebugEnabled,
+		logger:                       opts.Logger,
+		queryHooks:                   opts.QueryHooks,
+		outboxEnabled:                opts.OutboxEnabled,
+		outboxTableName:              opts.OutboxTableName,
+		lockTableName:                opts.LockTableName,
+		handleTableName:              opts.HandleTableName,
+		cache:                        opts.Cache,
+	}
+
+	if opts.Backend == BackendMemory {
+		store.storage = newMemoryStorage(store)
+		return store, nil
+	}
+
+	if opts.Backend != "" && opts.Backend != BackendSQL {
+		return nil, errors.New("entity store: unsupported backend " + opts.Backend)
 	}
 
 	if store.entityTableName == "" {
@@ -70,6 +152,30 @@ func NewStore(opts NewStoreOptions) (*Store, error) {
 		store.attributeTrashTableName = store.attributeTableName + "_trash"
 	}
 
+	if store.attributeValueTableName == "" {
+		store.attributeValueTableName = store.attributeTableName + "_value"
+	}
+
+	if store.attributeValueTrashTableName == "" {
+		store.attributeValueTrashTableName = store.attributeValueTableName + "_trash"
+	}
+
+	if store.outboxTableName == "" {
+		store.outboxTableName = store.entityTableName + "_outbox"
+	}
+
+	if store.lockTableName == "" {
+		store.lockTableName = store.entityTableName + "_lock"
+	}
+
+	if store.handleTableName == "" {
+		store.handleTableName = store.entityTableName + "_handle"
+	}
+
+	if store.schemaVersionTableName == "" {
+		store.schemaVersionTableName = store.entityTableName + "_schema_version"
+	}
+
 	if store.db == nil {
 		return nil, errors.New("entity store: DB is required")
 	}
@@ -78,8 +184,16 @@ func NewStore(opts NewStoreOptions) (*Store, error) {
 		store.dbDriverName = driverName(store.db)
 	}
 
+	store.storage = &sqlStorage{Store: store}
+
+	if opts.StatementCacheEnabled {
+		store.statementCache = newStatementCache(store.db)
+	}
+
 	if store.automigrateEnabled {
-		store.AutoMigrate()
+		if err := store.AutoMigrate(); err != nil {
+			return nil, err
+		}
 	}
 
 	return store, nil
@@ -0,0 +1,62 @@
+package entitystore
+
+import (
+	"context"
+	"time"
+)
+
+// QueryEvent describes a statement executed by the store
+type QueryEvent struct {
+	// Operation is the store operation executing the statement, i.e.
+	// EntityList or AttributeInsert
+	Operation  string
+	DriverName string
+	SQL        string
+	Args       []any
+	StartedAt  time.Time
+
+	// Duration, Rows and Error are set when the statement completed.
+	// Rows is the number of rows affected or returned
+	Duration time.Duration
+	Rows     int64
+	Error    error
+
+	// hookContexts are the contexts returned by the BeforeQuery of each
+	// hook, passed back to its AfterQuery
+	hookContexts []context.Context
+}
+
+// QueryHook is called around every statement executed by the SQL backend.
+// BeforeQuery may return a derived context, i.e. carrying a span, which is
+// passed to the next hooks, used for the statement and passed to the
+// AfterQuery of the same hook. The hooks wrap each other in the order
+// given, the first hook is called first before the statement and last
+// after it
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+// QueryHookFuncs adapts functions to a QueryHook. Either function may
+// be nil
+type QueryHookFuncs struct {
+	Before func(ctx context.Context, event *QueryEvent) context.Context
+	After  func(ctx context.Context, event *QueryEvent)
+}
+
+var _ QueryHook = QueryHookFuncs{}
+
+// BeforeQuery calls the Before function if set
+func (h QueryHookFuncs) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	if h.Before == nil {
+		return ctx
+	}
+	return h.Before(ctx, event)
+}
+
+// AfterQuery calls the After function if set
+func (h QueryHookFuncs) AfterQuery(ctx context.Context, event *QueryEvent) {
+	if h.After != nil {
+		h.After(ctx, event)
+	}
+}
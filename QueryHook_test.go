@@ -0,0 +1,298 @@
+package entitystore
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type hookContextKey struct{}
+
+func TestQueryHooks(t *testing.T) {
+	db := InitDB("test_query_hooks.db")
+
+	calls := []string{}
+	events := []QueryEvent{}
+
+	hook := func(name string) QueryHook {
+		return QueryHookFuncs{
+			Before: func(ctx context.Context, event *QueryEvent) context.Context {
+				calls = append(calls, "before "+name)
+				return context.WithValue(ctx, hookContextKey{}, name)
+			},
+			After: func(ctx context.Context, event *QueryEvent) {
+				calls = append(calls, "after "+name+" "+ctx.Value(hookContextKey{}).(string))
+				if name == "outer" {
+					events = append(events, *event)
+				}
+			},
+		}
+	}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		QueryHooks:         []QueryHook{hook("outer"), hook("inner")},
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	calls = []string{}
+	events = []QueryEvent{}
+
+	entity, err := store.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	expected := "before outer,before inner,after inner inner,after outer outer"
+
+	if strings.Join(calls, ",") != expected {
+		t.Fatal("Hooks called incorrectly", "must be", expected, "found", calls)
+	}
+
+	if err := store.AttributesSet(entity.ID(), map[string]string{"title": "Hello"}); err != nil {
+		t.Fatalf("Attributes could not be set: " + err.Error())
+	}
+
+	if _, err := store.EntityCount(EntityQueryOptions{EntityType: "post"}); err != nil {
+		t.Fatalf("Entities could not be counted: " + err.Error())
+	}
+
+	if _, err := store.AttributeCreate(entity.ID(), "title", "Again"); err == nil {
+		t.Fatalf("Duplicate attribute must not be created")
+	}
+
+	operations := []string{}
+	for _, event := range events {
+		operations = append(operations, event.Operation)
+	}
+
+	expected = "EntityCreate,EntityVersion,AttributeList,AttributeInsert,EntityCount,AttributeInsert"
+
+	if strings.Join(operations, ",") != expected {
+		t.Fatal("Operations incorrect", "must be", expected, "found", operations)
+	}
+
+	if events[0].SQL == "" || events[0].DriverName != "sqlite" || events[0].Rows != 1 || events[0].Duration <= 0 {
+		t.Fatal("Event incorrect", events[0])
+	}
+
+	if events[4].Rows != 1 || events[4].Error != nil {
+		t.Fatal("EntityCount event incorrect", events[4])
+	}
+
+	if events[5].Error == nil {
+		t.Fatal("Failed statement event must have the error")
+	}
+}
+
+func TestMetricsHook(t *testing.T) {
+	db := InitDB("test_query_metrics.db")
+
+	metrics := NewMetricsHook(0.5, 10)
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		QueryHooks:         []QueryHook{metrics},
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := store.EntityCreate("post"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+	}
+
+	if _, err := store.EntityList(EntityQueryOptions{EntityType: "post"}); err != nil {
+		t.Fatalf("Entities could not be listed: " + err.Error())
+	}
+
+	if _, err := store.EntityList(EntityQueryOptions{SortBy: "unknown"}); err == nil {
+		t.Fatalf("Sorting by an unknown column must fail")
+	}
+
+	output := &bytes.Buffer{}
+
+	if err := metrics.WritePrometheus(output); err != nil {
+		t.Fatalf("Metrics could not be written: " + err.Error())
+	}
+
+	for _, line := range []string{
+		"# TYPE entitystore_queries_total counter",
+		`entitystore_queries_total{operation="EntityCreate",status="ok"} 3`,
+		`entitystore_queries_total{operation="EntityCreate",status="error"} 0`,
+		`entitystore_queries_total{operation="EntityList",status="ok"} 1`,
+		"# TYPE entitystore_query_duration_seconds histogram",
+		`entitystore_query_duration_seconds_bucket{operation="EntityCreate",le="10"} 3`,
+		`entitystore_query_duration_seconds_bucket{operation="EntityCreate",le="+Inf"} 3`,
+		`entitystore_query_duration_seconds_count{operation="EntityCreate"} 3`,
+		`entitystore_query_rows_total{operation="EntityCreate"} 3`,
+		`entitystore_query_rows_total{operation="EntityList"} 3`,
+	} {
+		if !strings.Contains(output.String(), line+"\n") {
+			t.Fatal("Metrics must contain", line, "found", output.String())
+		}
+	}
+}
+
+type recordingTracer struct {
+	embedded.Tracer
+	mutex sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{name: name, attributes: map[attribute.Key]attribute.Value{}}
+
+	config := trace.NewSpanStartConfig(opts...)
+
+	for _, attr := range config.Attributes() {
+		span.attributes[attr.Key] = attr.Value
+	}
+
+	t.mutex.Lock()
+	t.spans = append(t.spans, span)
+	t.mutex.Unlock()
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	noop.Span
+	name       string
+	attributes map[attribute.Key]attribute.Value
+	status     codes.Code
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, attr := range kv {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.status = code
+}
+
+func (s *recordingSpan) End(options ...trace.SpanEndOption) {
+	s.ended = true
+}
+
+func TestTracingHook(t *testing.T) {
+	db := InitDB("test_query_tracing.db")
+
+	tracer := &recordingTracer{}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		QueryHooks:         []QueryHook{NewTracingHook(tracer)},
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	tracer.spans = nil
+
+	entity, err := store.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if _, err := store.AttributeCreate(entity.ID(), "title", "Hello"); err != nil {
+		t.Fatalf("Attribute could not be created: " + err.Error())
+	}
+
+	if _, err := store.AttributeCreate(entity.ID(), "title", "Again"); err == nil {
+		t.Fatalf("Duplicate attribute must not be created")
+	}
+
+	if len(tracer.spans) != 4 {
+		t.Fatal("Span count incorrect", "must be 4", "found", len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+
+	if span.name != "entitystore.EntityCreate" || !span.ended {
+		t.Fatal("Span incorrect", span.name, span.ended)
+	}
+
+	if span.attributes["db.system"].AsString() != "sqlite" || span.attributes["db.rows"].AsInt64() != 1 {
+		t.Fatal("Span attributes incorrect", span.attributes)
+	}
+
+	if !strings.HasPrefix(span.attributes["db.statement"].AsString(), "INSERT INTO") {
+		t.Fatal("Span must have the statement, found", span.attributes["db.statement"].AsString())
+	}
+
+	if tracer.spans[3].status != codes.Error {
+		t.Fatal("Failed statement span must have the error status")
+	}
+}
+
+func TestTracingHooksNested(t *testing.T) {
+	db := InitDB("test_query_tracing_nested.db")
+
+	outer := &recordingTracer{}
+	inner := &recordingTracer{}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		QueryHooks:         []QueryHook{NewTracingHook(outer), NewTracingHook(inner)},
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	outer.spans = nil
+	inner.spans = nil
+
+	if _, err := store.EntityCreate("post"); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	for name, tracer := range map[string]*recordingTracer{"outer": outer, "inner": inner} {
+		if len(tracer.spans) != 1 {
+			t.Fatal("Span count of the", name, "hook incorrect", "must be 1", "found", len(tracer.spans))
+		}
+
+		span := tracer.spans[0]
+
+		if !span.ended {
+			t.Fatal("Span of the", name, "hook must be ended by it")
+		}
+
+		if _, exists := span.attributes["db.rows"]; !exists {
+			t.Fatal("Span of the", name, "hook must have the rows", span.attributes)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package entitystore
+
+import (
+	"sort"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/golang-module/carbon/v2"
+)
+
+// RepairDuplicateAttributes finds attributes sharing the same entity ID
+// and attribute key, keeps the most recently updated one and deletes the
+// rest. Returns the number of deleted attributes.
+//
+// Run it before migrating stores created without the unique index on
+// (entity_id, attribute_key), as the index cannot be created while
+// duplicates exist. No change events are recorded, the repair does not
+// change the entities, only the rows keeping them.
+func (st *Store) RepairDuplicateAttributes() (int64, error) {
+	return st.storage.repairDuplicateAttributes()
+}
+
+// repairDuplicateAttributes deletes the duplicate attributes in a
+// transaction
+func (st *sqlStorage) repairDuplicateAttributes() (int64, error) {
+	tx, err := st.db.Begin()
+
+	if err != nil {
+		st.logError("RepairDuplicateAttributes", err)
+		return 0, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			txErr := tx.Rollback()
+			if txErr != nil {
+				st.logError("RepairDuplicateAttributes", txErr)
+			}
+		}
+	}()
+
+	sqlStr, params, errSql := st.dialect().
+		From(st.attributeTableName).
+		Prepared(true).
+		Select("entity_id", "attribute_key").
+		GroupBy("entity_id", "attribute_key").
+		Having(goqu.COUNT(goqu.Star()).Gt(1)).
+		ToSQL()
+
+	if errSql != nil {
+		tx.Rollback()
+		return 0, errSql
+	}
+
+	type duplicateGroup struct {
+		EntityID     string `db:"entity_id"`
+		AttributeKey string `db:"attribute_key"`
+	}
+
+	groups := []duplicateGroup{}
+	if err := st.sqlSelect("RepairDuplicateAttributes", tx, false, &groups, sqlStr, params...); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	deleted := int64(0)
+
+	for _, group := range groups {
+		sqlStr, params, errSql := st.dialect().
+			From(st.attributeTableName).
+			Prepared(true).
+			Where(goqu.C("entity_id").Eq(group.EntityID), goqu.C("attribute_key").Eq(group.AttributeKey)).
+			ToSQL()
+
+		if errSql != nil {
+			tx.Rollback()
+			return 0, errSql
+		}
+
+		attributeMaps := []map[string]string{}
+		if err := st.sqlSelect("RepairDuplicateAttributes", tx, false, &attributeMaps, sqlStr, params...); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		// Newest first, the ID breaking ties between rows updated at the same time
+		sort.Slice(attributeMaps, func(i, j int) bool {
+			iUpdatedAt := carbon.Parse(attributeMaps[i]["updated_at"], carbon.UTC).ToStdTime()
+			jUpdatedAt := carbon.Parse(attributeMaps[j]["updated_at"], carbon.UTC).ToStdTime()
+			if !iUpdatedAt.Equal(jUpdatedAt) {
+				return iUpdatedAt.After(jUpdatedAt)
+			}
+			return attributeMaps[i]["id"] > attributeMaps[j]["id"]
+		})
+
+		staleIDs := []string{}
+		for _, attributeMap := range attributeMaps[1:] {
+			staleIDs = append(staleIDs, attributeMap["id"])
+		}
+
+		sqlDelete, paramsDelete, errSql := st.dialect().
+			From(st.attributeTableName).
+			Prepared(true).
+			Where(goqu.C("id").In(staleIDs)).
+			Delete().
+			ToSQL()
+
+		if errSql != nil {
+			tx.Rollback()
+			return 0, errSql
+		}
+
+		result, err := st.sqlExec("RepairDuplicateAttributes", tx, false, sqlDelete, paramsDelete...)
+
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		affected, err := result.RowsAffected()
+
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		deleted += affected
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		st.logError("RepairDuplicateAttributes", err)
+		return 0, err
+	}
+
+	if deleted > 0 {
+		st.cacheClear()
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,120 @@
+package entitystore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAttributeCreateDuplicate(t *testing.T) {
+	db := InitDB("test_attribute_duplicate.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	_, err = store.AttributeCreate("default", "hello", "world")
+
+	if err != nil {
+		t.Fatalf("Attribute could not be created: " + err.Error())
+	}
+
+	_, err = store.AttributeCreate("default", "hello", "again")
+
+	if err == nil {
+		t.Fatalf("Duplicate attribute must not be created")
+	}
+}
+
+func TestRepairDuplicateAttributes(t *testing.T) {
+	db := InitDB("test_attribute_repair.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	// Simulate a store created before the unique index existed
+	_, err = db.Exec(`DROP INDEX "` + store.indexName(store.GetAttributeTableName(), "entity_id_attribute_key") + `"`)
+
+	if err != nil {
+		t.Fatalf("Index could not be dropped: " + err.Error())
+	}
+
+	now := time.Now()
+	values := []string{"oldest", "newest", "older"}
+	updatedAt := []time.Time{now.Add(-2 * time.Hour), now, now.Add(-1 * time.Hour)}
+
+	for i, value := range values {
+		_, err := store.AttributeInsert(*store.NewAttribute(NewAttributeOptions{
+			EntityID:       "default",
+			AttributeKey:   "hello",
+			AttributeValue: value,
+			UpdatedAt:      updatedAt[i],
+		}))
+
+		if err != nil {
+			t.Fatalf("Attribute could not be inserted: " + err.Error())
+		}
+	}
+
+	_, err = store.AttributeCreate("default", "unique", "value")
+
+	if err != nil {
+		t.Fatalf("Attribute could not be created: " + err.Error())
+	}
+
+	deleted, err := store.RepairDuplicateAttributes()
+
+	if err != nil {
+		t.Fatalf("Duplicates could not be repaired: " + err.Error())
+	}
+
+	if deleted != 2 {
+		t.Fatal("Deleted count incorrect", "must be 2", "found", deleted)
+	}
+
+	attrs, err := store.EntityAttributeList("default")
+
+	if err != nil {
+		t.Fatalf("Attributes could not be listed: " + err.Error())
+	}
+
+	if len(attrs) != 2 {
+		t.Fatal("Attribute count incorrect", "must be 2", "found", len(attrs))
+	}
+
+	attr, err := store.AttributeFind("default", "hello")
+
+	if err != nil {
+		t.Fatalf("Attribute could not be retrieved: " + err.Error())
+	}
+
+	if attr == nil || attr.GetString() != "newest" {
+		t.Fatal("The newest attribute must be kept")
+	}
+
+	sqls, err := store.SqlCreateTable()
+
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	for _, sqlStr := range sqls {
+		if _, err := db.Exec(sqlStr); err != nil {
+			t.Fatalf("Unique index could not be created after repair: " + err.Error())
+		}
+	}
+}
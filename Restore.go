@@ -0,0 +1,127 @@
+package entitystore
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// Restore loads a backup written by Backup, possibly from a store on
+// another database engine, into the store. The store must be migrated
+// and empty. The rows are inserted in one transaction, a backup failing
+// to load, i.e. truncated, leaves the store empty. Returns the manifest of
+// the backup, with the counts of the restored rows.
+//
+// No change events are recorded. The outbox is not part of the backup,
+// the change feed consumers of the restored store start from scratch
+func (st *Store) Restore(reader io.Reader) (*BackupManifest, error) {
+	gzipReader, err := gzip.NewReader(reader)
+
+	if err != nil {
+		return nil, errors.New("restore: not a backup: " + err.Error())
+	}
+
+	defer gzipReader.Close()
+
+	decoder := json.NewDecoder(gzipReader)
+
+	first := backupLine{}
+
+	if err := decoder.Decode(&first); err != nil {
+		return nil, errors.New("restore: not a backup: " + err.Error())
+	}
+
+	manifest := first.Manifest
+
+	if manifest == nil || manifest.Format != BackupFormat {
+		return nil, errors.New("restore: not a backup, the manifest is missing")
+	}
+
+	if manifest.Version > BackupFormatVersion {
+		return nil, errors.New("restore: backup format version " + strconv.Itoa(manifest.Version) + " is not supported")
+	}
+
+	tables := map[string]backupTable{}
+	for _, table := range st.backupTables() {
+		rows, err := st.storage.backupRows(st.db, table, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) > 0 {
+			return nil, errors.New("restore: the store is not empty, table " + table.name + " has rows")
+		}
+
+		tables[table.name] = table
+	}
+
+	counts := map[string]int64{}
+
+	err = st.withTransaction("Restore", func(tx *sql.Tx) error {
+		pending := map[string][]map[string]any{}
+
+		for {
+			line := backupLine{}
+
+			if err := decoder.Decode(&line); err == io.EOF {
+				return errors.New("restore: the backup is truncated")
+			} else if err != nil {
+				return errors.New("restore: " + err.Error())
+			}
+
+			if line.Counts != nil {
+				for _, table := range st.backupTables() {
+					if err := st.storage.backupRowsInsert(tx, table, pending[table.name]); err != nil {
+						return err
+					}
+				}
+
+				for name := range tables {
+					if counts[name] != line.Counts[name] {
+						return errors.New("restore: the backup is corrupted, " + strconv.FormatInt(line.Counts[name], 10) +
+							" rows of " + name + " expected, " + strconv.FormatInt(counts[name], 10) + " found")
+					}
+				}
+
+				return nil
+			}
+
+			table, exists := tables[line.Table]
+
+			if !exists {
+				return errors.New("restore: unknown table " + line.Table)
+			}
+
+			row, err := backupRowDecode(table, line.Row)
+
+			if err != nil {
+				return errors.New("restore: " + err.Error())
+			}
+
+			pending[table.name] = append(pending[table.name], row)
+			counts[table.name]++
+
+			if len(pending[table.name]) >= table.insertRows() {
+				if err := st.storage.backupRowsInsert(tx, table, pending[table.name]); err != nil {
+					return err
+				}
+
+				pending[table.name] = nil
+			}
+		}
+	})
+
+	st.cacheClear()
+
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Counts = counts
+
+	return manifest, nil
+}
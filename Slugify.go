@@ -0,0 +1,51 @@
+package entitystore
+
+import "strings"
+
+// slugFolds are the ASCII letters of the accented Latin letters
+var slugFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i",
+	'î': "i", 'ï': "i", 'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o",
+	'ö': "o", 'ø': "o", 'œ': "oe", 'ß': "ss", 'ù': "u", 'ú': "u", 'û': "u",
+	'ü': "u", 'ý': "y", 'ÿ': "y",
+}
+
+// Slugify converts a text, i.e. a title, to a handle: lower case ASCII
+// letters and digits, separated by single dashes. The accented Latin
+// letters are replaced by their ASCII letters, the other characters are
+// dropped, and the result is cut to the length of a handle
+func Slugify(text string) string {
+	var slug strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(text) {
+		letters, folded := slugFolds[r]
+
+		if !folded && ((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
+			letters = string(r)
+		}
+
+		if letters != "" {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteString(letters)
+			dash = false
+			continue
+		}
+
+		dash = true
+	}
+
+	return slugTruncate(slug.String(), handleMaxLength)
+}
+
+// slugTruncate cuts a slug to the length, without a trailing dash
+func slugTruncate(slug string, length int) string {
+	if len(slug) <= length {
+		return slug
+	}
+
+	return strings.TrimRight(slug[:length], "-")
+}
@@ -0,0 +1,27 @@
+package entitystore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":           "hello-world",
+		"  Go, SQL & EAV!  ":    "go-sql-eav",
+		"Crème brûlée":          "creme-brulee",
+		"--already-a-slug--":    "already-a-slug",
+		"!!!":                   "",
+		strings.Repeat("a", 70): strings.Repeat("a", 60),
+	}
+
+	for text, expected := range tests {
+		if slug := Slugify(text); slug != expected {
+			t.Fatalf("Slug of %q incorrect, must be %q, found %q", text, expected, slug)
+		}
+	}
+
+	if slug := Slugify(strings.Repeat("a", 59) + " b"); slug != strings.Repeat("a", 59) {
+		t.Fatalf("Slug must not end with a dash, found %q", slug)
+	}
+}
@@ -0,0 +1,126 @@
+package entitystore
+
+import (
+	"database/sql"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// StoreStats are the numbers of rows and the sizes of the tables of the
+// store
+type StoreStats struct {
+	Entities               int64
+	Attributes             int64
+	AttributeValues        int64
+	EntitiesTrashed        int64
+	AttributesTrashed      int64
+	AttributeValuesTrashed int64
+	ChangeEvents           int64
+
+	// Bytes are the sizes of the same tables
+	Bytes StoreTableSizes
+}
+
+// StoreTableSizes are the sizes of the tables of the store in bytes, with
+// their indexes, as reported by the database. They are zero on the memory
+// backend, and on SQLite when built without the dbstat virtual table
+type StoreTableSizes struct {
+	Entities               int64
+	Attributes             int64
+	AttributeValues        int64
+	EntitiesTrashed        int64
+	AttributesTrashed      int64
+	AttributeValuesTrashed int64
+	ChangeEvents           int64
+}
+
+// Stats returns the numbers of rows and the sizes of the tables of the
+// store, including the trash bin and the outbox
+func (st *Store) Stats() (StoreStats, error) {
+	return st.storage.stats()
+}
+
+// stats counts the rows of each table and reads its size
+func (st *sqlStorage) stats() (StoreStats, error) {
+	stats := StoreStats{}
+
+	tables := []struct {
+		name  string
+		count *int64
+		bytes *int64
+	}{
+		{st.entityTableName, &stats.Entities, &stats.Bytes.Entities},
+		{st.attributeTableName, &stats.Attributes, &stats.Bytes.Attributes},
+		{st.attributeValueTableName, &stats.AttributeValues, &stats.Bytes.AttributeValues},
+		{st.entityTrashTableName, &stats.EntitiesTrashed, &stats.Bytes.EntitiesTrashed},
+		{st.attributeTrashTableName, &stats.AttributesTrashed, &stats.Bytes.AttributesTrashed},
+		{st.attributeValueTrashTableName, &stats.AttributeValuesTrashed, &stats.Bytes.AttributeValuesTrashed},
+		{st.outboxTableName, &stats.ChangeEvents, &stats.Bytes.ChangeEvents},
+	}
+
+	for _, table := range tables {
+		sqlStr, params, errSql := st.dialect().From(table.name).Prepared(true).
+			Select(goqu.COUNT(goqu.Star())).
+			ToSQL()
+
+		if errSql != nil {
+			return stats, errSql
+		}
+
+		if err := st.sqlGet("Stats", st.db, false, table.count, sqlStr, params...); err != nil {
+			return stats, err
+		}
+
+		bytes, err := st.tableBytes(table.name)
+
+		if err != nil {
+			return stats, err
+		}
+
+		*table.bytes = bytes
+	}
+
+	return stats, nil
+}
+
+// tableBytes returns the size of a table with its indexes. SQLite reports
+// it in the dbstat virtual table, which is optional, the size is zero
+// without it
+func (st *sqlStorage) tableBytes(tableName string) (int64, error) {
+	sqlStr := ""
+
+	if st.dbDriverName == "postgres" {
+		sqlStr = "SELECT pg_total_relation_size(CAST($1 AS regclass))"
+	} else if st.dbDriverName == "mysql" {
+		sqlStr = "SELECT COALESCE(SUM(data_length + index_length), 0) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	} else if st.dbDriverName == "mssql" {
+		sqlStr = "SELECT COALESCE(SUM(a.total_pages), 0) * 8192 FROM sys.partitions p JOIN sys.allocation_units a ON a.container_id = p.partition_id WHERE p.object_id = OBJECT_ID(@p1)"
+	} else {
+		sqlStr = "SELECT COALESCE(SUM(pgsize), 0) FROM dbstat WHERE name = ? OR name IN (SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ?)"
+	}
+
+	params := []any{tableName}
+
+	if st.dbDriverName == "sqlite" {
+		params = append(params, tableName)
+
+		if !st.sqliteHasDbstat() {
+			return 0, nil
+		}
+	}
+
+	var bytes sql.NullInt64
+
+	if err := st.sqlGet("Stats", st.db, false, &bytes, sqlStr, params...); err != nil {
+		return 0, err
+	}
+
+	return bytes.Int64, nil
+}
+
+// sqliteHasDbstat returns whether the SQLite library has the dbstat
+// virtual table
+func (st *sqlStorage) sqliteHasDbstat() bool {
+	var pages sql.NullInt64
+	return st.db.QueryRow("SELECT COUNT(*) FROM dbstat WHERE name = 'sqlite_master'").Scan(&pages) == nil
+}
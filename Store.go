@@ -3,38 +3,59 @@ package entitystore
 import (
 	"database/sql"
 	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Store defines an entity store
 type Store struct {
-	entityTableName         string
-	attributeTableName      string
-	entityTrashTableName    string
-	attributeTrashTableName string
-	db                      *sql.DB
-	dbDriverName            string
-	automigrateEnabled      bool
-	debugEnabled            bool
+	entityTableName              string
+	attributeTableName           string
+	attributeValueTableName      string
+	entityTrashTableName         string
+	attributeTrashTableName      string
+	attributeValueTrashTableName string
+	schemaVersionTableName       string
+	db                           *sql.DB
+	dbDriverName                 string
+	automigrateEnabled           bool
+	debugEnabled                 bool
+	logger                       *slog.Logger
+	queryHooks                   []QueryHook
+	entityHooksMutex             sync.RWMutex
+	entityHooks                  map[string]map[EntityHookType][]EntityHook
+	outboxEnabled                bool
+	outboxTableName              string
+	lockTableName                string
+	handleTableName              string
+	subscriptionsMutex           sync.RWMutex
+	subscriptions                map[*Subscription]struct{}
+	statementCache               *statementCache
+	cache                        Cache
+	cacheHits                    atomic.Uint64
+	cacheMisses                  atomic.Uint64
+	cacheMutex                   sync.Mutex
+	cacheGeneration              atomic.Uint64
+	cacheEpoch                   atomic.Uint64
+	storage                      storage
 }
 
 // StoreOption options for the vault store
 type StoreOption func(*Store)
 
-// AutoMigrate auto migrate
+// AutoMigrate auto migrate, applies the pending schema migrations
 func (st *Store) AutoMigrate() error {
-	sqls, err := st.SqlCreateTable()
-
-	if err != nil {
-		return err
-	}
+	return st.Migrate()
+}
 
-	for _, sql := range sqls {
-		_, err := st.db.Exec(sql)
-		if err != nil {
-			return nil
-		}
+// Close releases the resources held by the store, i.e. the cached
+// prepared statements. The database itself is not closed
+func (st *Store) Close() error {
+	if st.statementCache != nil {
+		return st.statementCache.close()
 	}
-
 	return nil
 }
 
@@ -67,6 +88,31 @@ func (st *Store) GetEntityTrashTableName() string {
 	return st.entityTrashTableName
 }
 
+func (st *Store) GetSchemaVersionTableName() string {
+	return st.schemaVersionTableName
+}
+
+// tableNames returns the names of all the tables the store creates, with
+// the outbox and lock tables, whether enabled or not
+func (st *Store) tableNames() []string {
+	return []string{
+		st.entityTableName,
+		st.attributeTableName,
+		st.attributeValueTableName,
+		st.entityTrashTableName,
+		st.attributeTrashTableName,
+		st.attributeValueTrashTableName,
+		st.handleTableName,
+		st.schemaVersionTableName,
+		st.outboxTableName,
+		st.outboxCursorTableName(),
+		st.outboxLockTableName(),
+		st.lockTableName,
+	}
+}
+
+// SqlCreateTable returns the SQL creating the initial schema of the store.
+// Later schema changes are applied by Migrate
 func (st *Store) SqlCreateTable() ([]string, error) {
 
 	sqlMysql1 := `
@@ -206,6 +252,92 @@ func (st *Store) SqlCreateTable() ([]string, error) {
 	);
 	`
 
+	// Indexes. The unique index on (entity_id, attribute_key) guarantees
+	// one value per attribute key. MySQL does not support IF NOT EXISTS
+	// on CREATE INDEX, hence the guard, and cannot index a full text
+	// column, hence the prefix on attribute_value. PostgreSQL btree
+	// entries are limited in size, so attribute_value is indexed by prefix
+	// there as well.
+	sqlMysqlIndexes := []string{}
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.attributeTableName, "entity_id_attribute_key", `CREATE UNIQUE INDEX `+st.indexName(st.attributeTableName, "entity_id_attribute_key")+` ON `+st.attributeTableName+` (entity_id, attribute_key);`)...)
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.attributeTableName, "attribute_key_value", `CREATE INDEX `+st.indexName(st.attributeTableName, "attribute_key_value")+` ON `+st.attributeTableName+` (attribute_key, attribute_value(100));`)...)
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.entityTableName, "entity_type", `CREATE INDEX `+st.indexName(st.entityTableName, "entity_type")+` ON `+st.entityTableName+` (entity_type);`)...)
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.entityTableName, "entity_handle", `CREATE INDEX `+st.indexName(st.entityTableName, "entity_handle")+` ON `+st.entityTableName+` (entity_handle);`)...)
+
+	sqlPostgresIndexes := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS ` + st.indexName(st.attributeTableName, "entity_id_attribute_key") + ` ON ` + st.attributeTableName + ` ("entity_id", "attribute_key");`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.attributeTableName, "attribute_key_value") + ` ON ` + st.attributeTableName + ` ("attribute_key", left("attribute_value", 100));`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.entityTableName, "entity_type") + ` ON ` + st.entityTableName + ` ("entity_type");`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.entityTableName, "entity_handle") + ` ON ` + st.entityTableName + ` ("entity_handle");`,
+	}
+
+	sqlSqliteIndexes := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS "` + st.indexName(st.attributeTableName, "entity_id_attribute_key") + `" ON "` + st.attributeTableName + `" ("entity_id", "attribute_key");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.attributeTableName, "attribute_key_value") + `" ON "` + st.attributeTableName + `" ("attribute_key", "attribute_value");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.entityTableName, "entity_type") + `" ON "` + st.entityTableName + `" ("entity_type");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.entityTableName, "entity_handle") + `" ON "` + st.entityTableName + `" ("entity_handle");`,
+	}
+
+	// SQL Server has no CREATE TABLE IF NOT EXISTS, existence is checked
+	// with OBJECT_ID. A text column cannot be an index key there, so
+	// attribute_value is included in the attribute key index instead
+	sqlMssql1 := `
+	IF OBJECT_ID(N'` + st.attributeTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.attributeTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_id] nvarchar(40) NOT NULL,
+		[attribute_key] nvarchar(255) NOT NULL,
+		[attribute_value] nvarchar(max),
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL
+	);
+	`
+
+	sqlMssql2 := `
+	IF OBJECT_ID(N'` + st.entityTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.entityTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_type] nvarchar(40) NOT NULL,
+		[entity_handle] nvarchar(60) DEFAULT '',
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL
+	);
+	`
+
+	sqlMssql3 := `
+	IF OBJECT_ID(N'` + st.entityTrashTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.entityTrashTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_type] nvarchar(40) NOT NULL,
+		[entity_handle] nvarchar(60) DEFAULT '',
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL,
+		[deleted_at] datetime2 NOT NULL,
+		[deleted_by] nvarchar(40)
+	);
+	`
+
+	sqlMssql4 := `
+	IF OBJECT_ID(N'` + st.attributeTrashTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.attributeTrashTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_id] nvarchar(40) NOT NULL,
+		[attribute_key] nvarchar(255) NOT NULL,
+		[attribute_value] nvarchar(max),
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL,
+		[deleted_at] datetime2 NOT NULL,
+		[deleted_by] nvarchar(40)
+	);
+	`
+
+	sqlMssqlIndexes := []string{
+		st.sqlMssqlCreateIndex(st.attributeTableName, "entity_id_attribute_key", `CREATE UNIQUE INDEX [`+st.indexName(st.attributeTableName, "entity_id_attribute_key")+`] ON [`+st.attributeTableName+`] ([entity_id], [attribute_key]);`),
+		st.sqlMssqlCreateIndex(st.attributeTableName, "attribute_key_value", `CREATE INDEX [`+st.indexName(st.attributeTableName, "attribute_key_value")+`] ON [`+st.attributeTableName+`] ([attribute_key]) INCLUDE ([attribute_value]);`),
+		st.sqlMssqlCreateIndex(st.entityTableName, "entity_type", `CREATE INDEX [`+st.indexName(st.entityTableName, "entity_type")+`] ON [`+st.entityTableName+`] ([entity_type]);`),
+		st.sqlMssqlCreateIndex(st.entityTableName, "entity_handle", `CREATE INDEX [`+st.indexName(st.entityTableName, "entity_handle")+`] ON [`+st.entityTableName+`] ([entity_handle]);`),
+	}
+
 	sqls := []string{}
 
 	if st.dbDriverName == "mysql" {
@@ -213,19 +345,71 @@ func (st *Store) SqlCreateTable() ([]string, error) {
 		sqls = append(sqls, sqlMysql2)
 		sqls = append(sqls, sqlMysql3)
 		sqls = append(sqls, sqlMysql4)
+		sqls = append(sqls, sqlMysqlIndexes...)
 	} else if st.dbDriverName == "postgres" {
 		sqls = append(sqls, sqlPostgres1)
 		sqls = append(sqls, sqlPostgres2)
 		sqls = append(sqls, sqlPostgres3)
 		sqls = append(sqls, sqlPostgres4)
+		sqls = append(sqls, sqlPostgresIndexes...)
 	} else if st.dbDriverName == "sqlite" {
 		sqls = append(sqls, sqlSqlite1)
 		sqls = append(sqls, sqlSqlite2)
 		sqls = append(sqls, sqlSqlite3)
 		sqls = append(sqls, sqlSqlite4)
+		sqls = append(sqls, sqlSqliteIndexes...)
+	} else if st.dbDriverName == "mssql" {
+		sqls = append(sqls, sqlMssql1)
+		sqls = append(sqls, sqlMssql2)
+		sqls = append(sqls, sqlMssql3)
+		sqls = append(sqls, sqlMssql4)
+		sqls = append(sqls, sqlMssqlIndexes...)
 	} else {
 		return nil, errors.New("unsupported driver " + st.dbDriverName)
 	}
 
 	return sqls, nil
 }
+
+// indexName returns the name of an index on the given table. Index names
+// are prefixed with the table name as they must be unique per schema
+func (st *Store) indexName(tableName string, suffix string) string {
+	return "idx_" + tableName + "_" + suffix
+}
+
+// sqlMssqlCreateIndex guards the SQL Server statement creating an index,
+// which has no IF NOT EXISTS, with a check on the existing indexes
+func (st *Store) sqlMssqlCreateIndex(tableName string, suffix string, sqlCreate string) string {
+	return `IF NOT EXISTS (SELECT * FROM sys.indexes WHERE name = N'` + st.indexName(tableName, suffix) + `' AND object_id = OBJECT_ID(N'` + tableName + `')) ` + sqlCreate
+}
+
+// sqlMysqlCreateIndex guards the MySQL statement creating an index, which
+// has no IF NOT EXISTS, with a check on the existing indexes
+func (st *Store) sqlMysqlCreateIndex(tableName string, suffix string, sqlCreate string) []string {
+	sqlExists := `SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = '` + tableName + `' AND index_name = '` + st.indexName(tableName, suffix) + `'`
+
+	return st.sqlMysqlUnlessExists(sqlExists, sqlCreate)
+}
+
+// sqlMysqlAddColumn guards the MySQL statement adding a column, which has
+// no IF NOT EXISTS, with a check on the existing columns
+func (st *Store) sqlMysqlAddColumn(tableName string, columnName string, sqlAdd string) []string {
+	sqlExists := `SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = '` + tableName + `' AND column_name = '` + columnName + `'`
+
+	return st.sqlMysqlUnlessExists(sqlExists, sqlAdd)
+}
+
+// sqlMysqlUnlessExists returns the MySQL statements running a DDL
+// statement only when the count query returns zero. MySQL has no IF
+// outside of stored programs, the statement is prepared from a variable
+// holding either it or a no-op
+func (st *Store) sqlMysqlUnlessExists(sqlExists string, sqlDDL string) []string {
+	sqlDDL = strings.TrimSuffix(strings.TrimSpace(sqlDDL), ";")
+
+	return []string{
+		`SET @entitystore_ddl = IF((` + sqlExists + `) > 0, 'DO 0', '` + strings.ReplaceAll(sqlDDL, "'", "''") + `');`,
+		`PREPARE entitystore_ddl FROM @entitystore_ddl;`,
+		`EXECUTE entitystore_ddl;`,
+		`DEALLOCATE PREPARE entitystore_ddl;`,
+	}
+}
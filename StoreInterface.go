@@ -0,0 +1,105 @@
+package entitystore
+
+import (
+	"database/sql"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+//go:generate moq -out mocks/StoreInterfaceMock.go -pkg mocks . StoreInterface EntityInterface AttributeInterface
+
+// StoreInterface is the public API of the entity store. It is implemented
+// by *Store, as returned by NewStore, and allows the store to be mocked or
+// wrapped, i.e. with caching or metrics layers
+type StoreInterface interface {
+	AutoMigrate() error
+	Backup(writer io.Writer) (*BackupManifest, error)
+	CacheStats() CacheStats
+	Close() error
+	EnableDebug(debug bool)
+	GetAttributeTableName() string
+	GetAttributeTrashTableName() string
+	GetDB() *sql.DB
+	GetDebug() bool
+	GetEntityTableName() string
+	GetEntityTrashTableName() string
+	GetSchemaVersionTableName() string
+	Migrate() error
+	MigrationStatus() ([]Migration, error)
+	RepairDuplicateAttributes() (int64, error)
+	Restore(reader io.Reader) (*BackupManifest, error)
+	SqlCreateTable() ([]string, error)
+	Stats() (StoreStats, error)
+
+	Aggregate(entityType string, options AggregateOptions) ([]AggregateResult, error)
+
+	AttributeAddToSet(entityID string, attributeKey string, values ...string) error
+	AttributeAppendToList(entityID string, attributeKey string, values ...string) error
+	AttributeCreate(entityID string, attributeKey string, attributeValue string) (*Attribute, error)
+	AttributeDistinctValues(entityType string, attributeKey string, filter map[string]string) ([]string, error)
+	AttributeFind(entityID string, attributeKey string, options ...FindOptions) (*Attribute, error)
+	AttributeIncrement(entityID string, attributeKey string, delta int64) (int64, error)
+	AttributeIncrementFloat(entityID string, attributeKey string, delta float64) (float64, error)
+	AttributeInsert(attr Attribute) (*Attribute, error)
+	AttributeKeys(entityType string) ([]AttributeKeyInfo, error)
+	AttributeList(options AttributeQueryOptions) ([]Attribute, error)
+	AttributeQuery(options AttributeQueryOptions) *goqu.SelectDataset
+	AttributeRemoveFromSet(entityID string, attributeKey string, values ...string) error
+	AttributeSetFloat(entityID string, attributeKey string, attributeValue float64) error
+	AttributeSetInt(entityID string, attributeKey string, attributeValue int64) error
+	AttributeSetString(entityID string, attributeKey string, attributeValue string) error
+	AttributesSet(entityID string, attributes map[string]string) error
+	AttributesSetIfVersion(entityID string, version int64, attributes map[string]string) error
+	AttributeUpdate(attr Attribute) error
+	AttributeValues(entityID string, attributeKey string) ([]string, error)
+
+	ChangeFeed(consumer string) (*ChangeFeed, error)
+	ChangeFeedPrune() (int64, error)
+
+	CopyTo(dst StoreInterface, filter EntityQueryOptions, options CopyOptions) (*CopyReport, error)
+
+	EntityAttributeList(entityID string) ([]Attribute, error)
+	EntityCount(options EntityQueryOptions) (int64, error)
+	EntityCursor(options EntityQueryOptions, iterateOptions ...IterateOptions) iter.Seq2[EntityWithAttributes, error]
+	EntityCreate(entityType string) (*Entity, error)
+	EntityCreateWithAttributes(entityType string, attributes map[string]string) (*Entity, error)
+	EntityCreateWithHandle(entityType string, entityHandle string, attributes map[string]string) (*Entity, error)
+	EntityDelete(entityID string) (bool, error)
+	EntityFindByAttribute(entityType string, attributeKey string, attributeValue string) (*Entity, error)
+	EntityFindByHandle(entityType string, entityHandle string, options ...FindOptions) (*Entity, error)
+	EntityFindByID(entityID string, options ...FindOptions) (*Entity, error)
+	EntityHandleGenerate(entityType string, text string) (string, error)
+	EntityIterate(options EntityQueryOptions, fn func(entity Entity, attributes []Attribute) error, iterateOptions ...IterateOptions) error
+	EntityList(options EntityQueryOptions) ([]Entity, error)
+	EntityListByAttribute(entityType string, attributeKey string, attributeValue string) ([]Entity, error)
+	EntityLock(entityID string, owner string, ttl time.Duration) (*EntityLease, error)
+	EntityLockRenew(entityID string, owner string, ttl time.Duration) (*EntityLease, error)
+	EntityQuery(options EntityQueryOptions) *goqu.SelectDataset
+	EntityRestore(entityID string) (bool, error)
+	EntitySetHandle(entityID string, entityHandle string) error
+	EntityTrash(entityID string) (bool, error)
+	EntityTypes() ([]EntityTypeCount, error)
+	EntityUnlock(entityID string, owner string) error
+	EntityUpdate(ent Entity) (bool, error)
+	EntityUpdateIfVersion(ent Entity, version int64) (bool, error)
+
+	Export(writer io.Writer, filter EntityQueryOptions) (int64, error)
+	ExportCSV(entityType string, attributeKeys []string, writer io.Writer) (int64, error)
+	Facets(entityType string, attributeKeys []string, filter map[string]string, limit int) (map[string][]FacetValue, error)
+
+	NewAttribute(opts NewAttributeOptions) *Attribute
+	NewAttributeFromMap(attributeMap map[string]string) *Attribute
+	NewEntity(opts NewEntityOptions) *Entity
+	NewEntityFromMap(entityMap map[string]string) *Entity
+
+	Import(reader io.Reader, options ImportOptions) (*ImportReport, error)
+	ImportCSV(entityType string, reader io.Reader, options CSVImportOptions) (*ImportReport, error)
+	RegisterEntityHook(entityType string, hookType EntityHookType, hook EntityHook)
+	Subscribe(entityType string, filter func(event ChangeEvent) bool, options SubscribeOptions) *Subscription
+	Sync(dst StoreInterface, filter EntityQueryOptions, since time.Time, options CopyOptions) (*CopyReport, error)
+}
+
+var _ StoreInterface = (*Store)(nil)
@@ -0,0 +1,176 @@
+package entitystore
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Backpressure policies of a subscription, applied when its buffer is full
+const (
+	// SubscriptionDrop drops the events the subscriber has no room for.
+	// The mutations are never slowed down by a subscriber. It is the default
+	SubscriptionDrop = "drop"
+
+	// SubscriptionBlock makes the mutation wait, after its commit, until
+	// the subscriber has room for the event. No event is lost, but a slow
+	// subscriber slows down the mutations
+	SubscriptionBlock = "block"
+)
+
+// DefaultSubscriptionBufferSize is the buffer size of a subscription when
+// none is given
+const DefaultSubscriptionBufferSize = 64
+
+// SubscribeOptions define the options of a subscription
+type SubscribeOptions struct {
+	// BufferSize is the number of events buffered for the subscriber,
+	// DefaultSubscriptionBufferSize when zero
+	BufferSize int
+
+	// Backpressure is SubscriptionDrop (the default) or SubscriptionBlock
+	Backpressure string
+}
+
+// Subscription receives the change events of the mutations committed by
+// the store, in this process. Unlike the ChangeFeed nothing is persisted,
+// the events committed while not subscribed are not received
+type Subscription struct {
+	st           *Store
+	entityType   string
+	filter       func(event ChangeEvent) bool
+	backpressure string
+	events       chan ChangeEvent
+	done         chan struct{}
+	doneOnce     sync.Once
+	mutex        sync.Mutex
+	closed       bool
+	dropped      atomic.Uint64
+}
+
+// Subscribe subscribes to the change events of the entity type, of all the
+// entity types when empty, for which the filter returns true. A nil filter
+// accepts all the events. The events are sent after the commit of the
+// mutations. Call Unsubscribe when done
+func (st *Store) Subscribe(entityType string, filter func(event ChangeEvent) bool, options SubscribeOptions) *Subscription {
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultSubscriptionBufferSize
+	}
+
+	if options.Backpressure != SubscriptionBlock {
+		options.Backpressure = SubscriptionDrop
+	}
+
+	sub := &Subscription{
+		st:           st,
+		entityType:   entityType,
+		filter:       filter,
+		backpressure: options.Backpressure,
+		events:       make(chan ChangeEvent, options.BufferSize),
+		done:         make(chan struct{}),
+	}
+
+	st.subscriptionsMutex.Lock()
+	defer st.subscriptionsMutex.Unlock()
+
+	if st.subscriptions == nil {
+		st.subscriptions = map[*Subscription]struct{}{}
+	}
+
+	st.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+// Events returns the channel of the change events, closed by Unsubscribe
+func (sub *Subscription) Events() <-chan ChangeEvent {
+	return sub.events
+}
+
+// Dropped returns the number of events dropped as the buffer was full
+func (sub *Subscription) Dropped() uint64 {
+	return sub.dropped.Load()
+}
+
+// Unsubscribe stops the subscription and closes the events channel. A
+// mutation blocked on the subscription is released. Safe to call twice
+func (sub *Subscription) Unsubscribe() {
+	sub.doneOnce.Do(func() {
+		sub.st.subscriptionsMutex.Lock()
+		delete(sub.st.subscriptions, sub)
+		sub.st.subscriptionsMutex.Unlock()
+
+		// Closing done first releases a blocked send, which holds the mutex
+		close(sub.done)
+
+		sub.mutex.Lock()
+		defer sub.mutex.Unlock()
+
+		sub.closed = true
+		close(sub.events)
+	})
+}
+
+// send sends the event to the subscriber, if it matches the subscription
+func (sub *Subscription) send(event ChangeEvent) {
+	if sub.entityType != "" && sub.entityType != event.EntityType {
+		return
+	}
+
+	if sub.filter != nil && !sub.filter(event) {
+		return
+	}
+
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+
+	if sub.closed {
+		return
+	}
+
+	if sub.backpressure == SubscriptionBlock {
+		select {
+		case sub.events <- event:
+		case <-sub.done:
+		}
+		return
+	}
+
+	select {
+	case sub.events <- event:
+	default:
+		sub.dropped.Add(1)
+	}
+}
+
+// subscribed returns whether there are subscriptions, which need the entity
+// type of the change events
+func (st *Store) subscribed() bool {
+	st.subscriptionsMutex.RLock()
+	defer st.subscriptionsMutex.RUnlock()
+
+	return len(st.subscriptions) > 0
+}
+
+// changePublish sends the change event of a committed mutation to the
+// subscriptions
+func (st *Store) changePublish(change ChangeEvent) {
+	st.subscriptionsMutex.RLock()
+	subs := make([]*Subscription, 0, len(st.subscriptions))
+	for sub := range st.subscriptions {
+		subs = append(subs, sub)
+	}
+	st.subscriptionsMutex.RUnlock()
+
+	for _, sub := range subs {
+		// Each subscriber gets its own copy of the attributes
+		event := change
+		if change.Attributes != nil {
+			event.Attributes = make(map[string]string, len(change.Attributes))
+			for k, v := range change.Attributes {
+				event.Attributes[k] = v
+			}
+		}
+
+		sub.send(event)
+	}
+}
@@ -0,0 +1,150 @@
+package entitystore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		DB:                 InitDB("test_subscribe.db"),
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	posts := store.Subscribe("post", nil, SubscribeOptions{})
+	defer posts.Unsubscribe()
+
+	titles := store.Subscribe("", func(event ChangeEvent) bool {
+		_, ok := event.Attributes["title"]
+		return ok
+	}, SubscribeOptions{})
+	defer titles.Unsubscribe()
+
+	post, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Hello"})
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if err := post.SetString("body", "Hello world"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	if _, err := store.EntityCreateWithAttributes("comment", map[string]string{"title": "Nice"}); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if _, err := store.EntityDelete(post.ID()); err != nil {
+		t.Fatalf("Entity could not be deleted: " + err.Error())
+	}
+
+	expected := []string{ChangeCreate, ChangeAttributeSet, ChangeDelete}
+	for _, operation := range expected {
+		event := subscriptionReceive(t, posts)
+
+		if event.Operation != operation || event.EntityID != post.ID() || event.EntityType != "post" {
+			t.Fatalf("Unexpected event %v, expected %s of %s", event, operation, post.ID())
+		}
+	}
+
+	for _, entityType := range []string{"post", "comment"} {
+		event := subscriptionReceive(t, titles)
+
+		if event.Operation != ChangeCreate || event.EntityType != entityType {
+			t.Fatalf("Unexpected event %v, expected create of %s", event, entityType)
+		}
+	}
+
+	posts.Unsubscribe()
+
+	if _, ok := <-posts.Events(); ok {
+		t.Fatalf("Events must be closed after Unsubscribe")
+	}
+}
+
+func TestSubscribeBackpressure(t *testing.T) {
+	store, err := NewStore(NewStoreOptions{
+		Backend: BackendMemory,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	dropping := store.Subscribe("post", nil, SubscribeOptions{BufferSize: 1})
+	defer dropping.Unsubscribe()
+
+	for i := 0; i < 3; i++ {
+		if _, err := store.EntityCreate("post"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+	}
+
+	if dropping.Dropped() != 2 {
+		t.Fatalf("Expected 2 dropped events, found %d", dropping.Dropped())
+	}
+
+	dropping.Unsubscribe()
+
+	blocking := store.Subscribe("post", nil, SubscribeOptions{BufferSize: 1, Backpressure: SubscriptionBlock})
+
+	if _, err := store.EntityCreate("post"); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	created := make(chan struct{})
+	go func() {
+		store.EntityCreate("post")
+		close(created)
+	}()
+
+	select {
+	case <-created:
+		t.Fatalf("EntityCreate must block while the buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	subscriptionReceive(t, blocking)
+	subscriptionReceive(t, blocking)
+	<-created
+
+	// Unsubscribe releases a blocked mutation
+	if _, err := store.EntityCreate("post"); err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	created = make(chan struct{})
+	go func() {
+		store.EntityCreate("post")
+		close(created)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	blocking.Unsubscribe()
+
+	select {
+	case <-created:
+	case <-time.After(time.Second):
+		t.Fatalf("Unsubscribe must release the blocked EntityCreate")
+	}
+}
+
+// subscriptionReceive receives the next event of the subscription
+func subscriptionReceive(t *testing.T, sub *Subscription) ChangeEvent {
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatalf("Events closed unexpectedly")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("No event received")
+	}
+	return ChangeEvent{}
+}
@@ -0,0 +1,29 @@
+package entitystore
+
+import "time"
+
+// Sync copies to another store the entities matching the filter written,
+// with their attributes, since the watermark of the previous sync, the
+// zero time for the first one. The entities unchanged in the destination
+// since the watermark are overwritten, those changed there as well are
+// conflicts, resolved by the conflict policy. The returned report has the
+// watermark of the next sync. Trashed and deleted entities are not synced
+//
+// The updated_at of an entity is set before its transaction commits, a
+// transaction committing after a sync may have written entities before
+// the watermark. The entities written in the SyncWindow before the
+// watermark are read again by the next sync, and skipped when unchanged,
+// their times compared to the precision of the database engines
+func (st *Store) Sync(dst StoreInterface, filter EntityQueryOptions, since time.Time, options CopyOptions) (*CopyReport, error) {
+	if err := copyOptionsValidate(st, dst, &options); err != nil {
+		return nil, err
+	}
+
+	filter.UpdatedSince = since
+
+	if !since.IsZero() {
+		filter.UpdatedSince = since.Add(-options.SyncWindow)
+	}
+
+	return st.copyEntities(dst, filter, options, &since)
+}
@@ -0,0 +1,60 @@
+package entitystore
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingHook is a QueryHook recording an OpenTelemetry span for every
+// executed statement, named after the operation and carrying the
+// database semantic convention attributes
+type TracingHook struct {
+	tracer trace.Tracer
+}
+
+var _ QueryHook = (*TracingHook)(nil)
+
+// NewTracingHook creates a tracing hook starting the spans with the
+// tracer, i.e. otel.Tracer("entitystore")
+func NewTracingHook(tracer trace.Tracer) *TracingHook {
+	return &TracingHook{tracer: tracer}
+}
+
+// BeforeQuery starts the span of the statement
+func (h *TracingHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	ctx, _ = h.tracer.Start(ctx, "entitystore."+event.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(event.StartedAt),
+		trace.WithAttributes(
+			attribute.String("db.system", tracingDbSystem(event.DriverName)),
+			attribute.String("db.operation", event.Operation),
+			attribute.String("db.statement", event.SQL),
+		),
+	)
+	return ctx
+}
+
+// AfterQuery ends the span of the statement, recording its error
+func (h *TracingHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	span := trace.SpanFromContext(ctx)
+
+	span.SetAttributes(attribute.Int64("db.rows", event.Rows))
+
+	if event.Error != nil {
+		span.RecordError(event.Error)
+		span.SetStatus(codes.Error, event.Error.Error())
+	}
+
+	span.End(trace.WithTimestamp(event.StartedAt.Add(event.Duration)))
+}
+
+// tracingDbSystem returns the OpenTelemetry db.system value of a driver
+func tracingDbSystem(driverName string) string {
+	if driverName == "postgres" {
+		return "postgresql"
+	}
+	return driverName
+}
@@ -0,0 +1,65 @@
+package entitystore
+
+import (
+	"regexp"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// attributeNumberPattern matches the attribute values aggregated as
+// numbers, decimal numbers with an optional sign, i.e. -1.5
+const attributeNumberPattern = `^[+-]?([0-9]+([.][0-9]*)?|[.][0-9]+)$`
+
+// attributeNumberRegexp is attributeNumberPattern compiled, for the memory
+// backend
+var attributeNumberRegexp = regexp.MustCompile(attributeNumberPattern)
+
+// attributeFilterWhere restricts a query to the entities having all the
+// attribute values of the filter, by attribute key. The column holds the
+// entity ID
+func (st *Store) attributeFilterWhere(q *goqu.SelectDataset, column string, filter map[string]string) *goqu.SelectDataset {
+	for _, key := range sortedKeys(filter) {
+		q = q.Where(goqu.Ex{column: st.dialect().From(st.attributeTableName).
+			Select("entity_id").
+			Where(goqu.C("attribute_key").Eq(key), goqu.C("attribute_value").Eq(filter[key]))})
+	}
+
+	return q
+}
+
+// attributeValueAsNumber returns the expression of an attribute value
+// column cast to a floating point number, for the aggregations. The
+// values not matching attributeNumberPattern are NULL, ignored by the
+// aggregate functions, as each engine casts them differently
+func (st *Store) attributeValueAsNumber(column exp.IdentifierExpression) exp.Expression {
+	isNumber := st.attributeValueIsNumber(column)
+
+	if st.dbDriverName == "mysql" {
+		return goqu.L("CASE WHEN ? THEN (? + 0) END", isNumber, column)
+	} else if st.dbDriverName == "postgres" {
+		return goqu.L("CASE WHEN ? THEN CAST(? AS DOUBLE PRECISION) END", isNumber, column)
+	} else if st.dbDriverName == "mssql" {
+		return goqu.L("CASE WHEN ? THEN TRY_CAST(? AS FLOAT) END", isNumber, column)
+	}
+
+	return goqu.L("CASE WHEN ? THEN CAST(? AS REAL) END", isNumber, column)
+}
+
+// attributeValueIsNumber returns the condition of an attribute value
+// column matching attributeNumberPattern. SQLite and SQL Server have no
+// regular expressions, the pattern is spelled out with GLOB and LIKE: only
+// digits, dots and signs, a digit, at most one dot and the sign first
+func (st *Store) attributeValueIsNumber(column exp.IdentifierExpression) exp.Expression {
+	if st.dbDriverName == "mysql" {
+		return goqu.L("? REGEXP ?", column, attributeNumberPattern)
+	} else if st.dbDriverName == "postgres" {
+		return goqu.L("? ~ ?", column, attributeNumberPattern)
+	} else if st.dbDriverName == "mssql" {
+		return goqu.L("(? LIKE ? AND ? NOT LIKE ? AND ? NOT LIKE ? AND ? NOT LIKE ?)",
+			column, "%[0-9]%", column, "%[^0-9.+-]%", column, "%.%.%", column, "_%[+-]%")
+	}
+
+	return goqu.L("(? GLOB ? AND ? NOT GLOB ? AND ? NOT GLOB ? AND ? NOT GLOB ?)",
+		column, "*[0-9]*", column, "*[^0-9.+-]*", column, "*.*.*", column, "?*[+-]*")
+}
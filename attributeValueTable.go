@@ -0,0 +1,425 @@
+package entitystore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/gouniverse/uid"
+)
+
+// sqlCreateAttributeValueTable returns the SQL creating the table of the
+// multi-valued attributes, one row per value, ordered by position
+func (st *Store) sqlCreateAttributeValueTable() ([]string, error) {
+	sqlMysql := []string{`
+	CREATE TABLE IF NOT EXISTS ` + st.attributeValueTableName + ` (
+		id varchar(40) NOT NULL PRIMARY KEY,
+		entity_id varchar(40) NOT NULL,
+		attribute_key varchar(255) NOT NULL,
+		attribute_value text,
+		position bigint NOT NULL,
+		created_at datetime NOT NULL
+	);
+	`}
+	sqlMysql = append(sqlMysql, st.sqlMysqlCreateIndex(st.attributeValueTableName, "entity_id_attribute_key", `CREATE INDEX `+st.indexName(st.attributeValueTableName, "entity_id_attribute_key")+` ON `+st.attributeValueTableName+` (entity_id, attribute_key, position);`)...)
+	sqlMysql = append(sqlMysql, st.sqlMysqlCreateIndex(st.attributeValueTableName, "attribute_key_value", `CREATE INDEX `+st.indexName(st.attributeValueTableName, "attribute_key_value")+` ON `+st.attributeValueTableName+` (attribute_key, attribute_value(100));`)...)
+
+	sqlPostgres := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.attributeValueTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"position" bigint NOT NULL,
+		"created_at" timestamptz(6) NOT NULL
+	);
+	`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.attributeValueTableName, "entity_id_attribute_key") + ` ON ` + st.attributeValueTableName + ` ("entity_id", "attribute_key", "position");`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.attributeValueTableName, "attribute_key_value") + ` ON ` + st.attributeValueTableName + ` ("attribute_key", left("attribute_value", 100));`,
+	}
+
+	sqlSqlite := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.attributeValueTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"position" integer NOT NULL,
+		"created_at" datetime NOT NULL
+	);
+	`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.attributeValueTableName, "entity_id_attribute_key") + `" ON "` + st.attributeValueTableName + `" ("entity_id", "attribute_key", "position");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.attributeValueTableName, "attribute_key_value") + `" ON "` + st.attributeValueTableName + `" ("attribute_key", "attribute_value");`,
+	}
+
+	sqlMssql := []string{`
+	IF OBJECT_ID(N'` + st.attributeValueTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.attributeValueTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_id] nvarchar(40) NOT NULL,
+		[attribute_key] nvarchar(255) NOT NULL,
+		[attribute_value] nvarchar(max),
+		[position] bigint NOT NULL,
+		[created_at] datetime2 NOT NULL
+	);
+	`,
+		st.sqlMssqlCreateIndex(st.attributeValueTableName, "entity_id_attribute_key", `CREATE INDEX [`+st.indexName(st.attributeValueTableName, "entity_id_attribute_key")+`] ON [`+st.attributeValueTableName+`] ([entity_id], [attribute_key], [position]);`),
+		st.sqlMssqlCreateIndex(st.attributeValueTableName, "attribute_key_value", `CREATE INDEX [`+st.indexName(st.attributeValueTableName, "attribute_key_value")+`] ON [`+st.attributeValueTableName+`] ([attribute_key]) INCLUDE ([attribute_value]);`),
+	}
+
+	if st.dbDriverName == "mysql" {
+		return sqlMysql, nil
+	} else if st.dbDriverName == "postgres" {
+		return sqlPostgres, nil
+	} else if st.dbDriverName == "sqlite" {
+		return sqlSqlite, nil
+	} else if st.dbDriverName == "mssql" {
+		return sqlMssql, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// sqlCreateAttributeValueTrashTable returns the SQL creating the table of
+// the values of the multi-valued attributes of the trashed entities
+func (st *Store) sqlCreateAttributeValueTrashTable() ([]string, error) {
+	sqlMysql := []string{`
+	CREATE TABLE IF NOT EXISTS ` + st.attributeValueTrashTableName + ` (
+		id varchar(40) NOT NULL PRIMARY KEY,
+		entity_id varchar(40) NOT NULL,
+		attribute_key varchar(255) NOT NULL,
+		attribute_value text,
+		position bigint NOT NULL,
+		created_at datetime NOT NULL,
+		deleted_at datetime NOT NULL,
+		deleted_by varchar(40)
+	);
+	`}
+	sqlMysql = append(sqlMysql, st.sqlMysqlCreateIndex(st.attributeValueTrashTableName, "entity_id", `CREATE INDEX `+st.indexName(st.attributeValueTrashTableName, "entity_id")+` ON `+st.attributeValueTrashTableName+` (entity_id);`)...)
+
+	sqlPostgres := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.attributeValueTrashTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"position" bigint NOT NULL,
+		"created_at" timestamptz(6) NOT NULL,
+		"deleted_at" timestamptz(6) NOT NULL,
+		"deleted_by" varchar(40)
+	);
+	`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.attributeValueTrashTableName, "entity_id") + ` ON ` + st.attributeValueTrashTableName + ` ("entity_id");`,
+	}
+
+	sqlSqlite := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.attributeValueTrashTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"position" integer NOT NULL,
+		"created_at" datetime NOT NULL,
+		"deleted_at" datetime NOT NULL,
+		"deleted_by" varchar(40)
+	);
+	`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.attributeValueTrashTableName, "entity_id") + `" ON "` + st.attributeValueTrashTableName + `" ("entity_id");`,
+	}
+
+	sqlMssql := []string{`
+	IF OBJECT_ID(N'` + st.attributeValueTrashTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.attributeValueTrashTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_id] nvarchar(40) NOT NULL,
+		[attribute_key] nvarchar(255) NOT NULL,
+		[attribute_value] nvarchar(max),
+		[position] bigint NOT NULL,
+		[created_at] datetime2 NOT NULL,
+		[deleted_at] datetime2 NOT NULL,
+		[deleted_by] nvarchar(40)
+	);
+	`,
+		st.sqlMssqlCreateIndex(st.attributeValueTrashTableName, "entity_id", `CREATE INDEX [`+st.indexName(st.attributeValueTrashTableName, "entity_id")+`] ON [`+st.attributeValueTrashTableName+`] ([entity_id]);`),
+	}
+
+	if st.dbDriverName == "mysql" {
+		return sqlMysql, nil
+	} else if st.dbDriverName == "postgres" {
+		return sqlPostgres, nil
+	} else if st.dbDriverName == "sqlite" {
+		return sqlSqlite, nil
+	} else if st.dbDriverName == "mssql" {
+		return sqlMssql, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// attributeValuesChange applies a change to the values of a multi-valued
+// attribute, in a transaction. The change returns the new values, and
+// whether they changed at all
+func (st *Store) attributeValuesChange(op string, entityID string, attributeKey string, change func(values []string) ([]string, bool)) error {
+	if entityID == "" {
+		return errors.New("entity id cannot be empty")
+	}
+
+	if attributeKey == "" {
+		return errors.New("attribute key cannot be empty")
+	}
+
+	changeEvent := ChangeEvent{
+		Operation: ChangeAttributeValues,
+		EntityID:  entityID,
+	}
+
+	// The entity type is only looked up when needed, for the outbox and
+	// the subscriptions
+	if st.outboxEnabled || st.subscribed() {
+		entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
+
+		if err != nil {
+			return err
+		}
+
+		if entity != nil {
+			changeEvent.EntityType = entity.Type()
+		}
+	}
+
+	changed := false
+
+	err := st.withTransaction(op, func(tx *sql.Tx) error {
+		// Incrementing the version first also serializes the concurrent
+		// changes of the values of the entity
+		if err := st.storage.entityVersionBump(tx, entityID, nil); err != nil {
+			return err
+		}
+
+		values, isChanged, err := st.storage.attributeValuesChange(tx, entityID, attributeKey, change)
+
+		if err != nil || !isChanged {
+			return err
+		}
+
+		changed = true
+
+		valuesJSON, err := json.Marshal(values)
+
+		if err != nil {
+			return err
+		}
+
+		changeEvent.Attributes = map[string]string{attributeKey: string(valuesJSON)}
+
+		return st.changeRecord(tx, &changeEvent)
+	})
+
+	st.cacheInvalidate(entityID)
+
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		st.changePublish(changeEvent)
+	}
+
+	return nil
+}
+
+// attributeValuesChange applies a change to the values of a list or set
+// attribute, returning the new values and whether they changed
+func (st *sqlStorage) attributeValuesChange(tx *sql.Tx, entityID string, attributeKey string, change func(values []string) ([]string, bool)) ([]string, bool, error) {
+	values, err := st.attributeValues(tx, entityID, attributeKey)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	values, changed := change(values)
+
+	if !changed {
+		return values, false, nil
+	}
+
+	return values, true, st.attributeValuesReplace(tx, entityID, attributeKey, values)
+}
+
+// attributeValues returns the values of a multi-valued attribute, in
+// order
+func (st *sqlStorage) attributeValues(db txOrDB, entityID string, attributeKey string) ([]string, error) {
+	sqlStr, params, errSql := st.dialect().From(st.attributeValueTableName).Prepared(true).
+		Select("attribute_value").
+		Where(goqu.C("entity_id").Eq(entityID), goqu.C("attribute_key").Eq(attributeKey)).
+		Order(goqu.C("position").Asc()).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	values := []string{}
+
+	if err := st.sqlSelect("AttributeValues", db, false, &values, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// attributeValuesReplace replaces the values of a multi-valued attribute
+func (st *sqlStorage) attributeValuesReplace(tx *sql.Tx, entityID string, attributeKey string, values []string) error {
+	sqlStr, params, errSql := st.dialect().From(st.attributeValueTableName).Prepared(true).
+		Where(goqu.C("entity_id").Eq(entityID), goqu.C("attribute_key").Eq(attributeKey)).
+		Delete().
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if _, err := st.sqlExec("AttributeValues", tx, false, sqlStr, params...); err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	rows := []any{}
+	for position, value := range values {
+		rows = append(rows, goqu.Record{
+			"id":              uid.HumanUid(),
+			"entity_id":       entityID,
+			"attribute_key":   attributeKey,
+			"attribute_value": value,
+			"position":        position + 1,
+			"created_at":      time.Now(),
+		})
+	}
+
+	sqlStr, params, errSql = st.dialect().Insert(st.attributeValueTableName).Prepared(true).
+		Rows(rows...).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("AttributeValues", tx, false, sqlStr, params...)
+
+	return err
+}
+
+// attributeValuesDelete deletes all the values of the multi-valued
+// attributes of an entity
+func (st *Store) attributeValuesDelete(tx *sql.Tx, entityID string) error {
+	sqlStr, params, errSql := st.dialect().From(st.attributeValueTableName).Prepared(true).
+		Where(goqu.C("entity_id").Eq(entityID)).
+		Delete().
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("EntityDelete", tx, false, sqlStr, params...)
+
+	return err
+}
+
+// attributeValuesTrash moves the values of the multi-valued attributes of
+// an entity to the trash table
+func (st *Store) attributeValuesTrash(tx *sql.Tx, entityID string) error {
+	columns := []any{"id", "entity_id", "attribute_key", "attribute_value", "position", "created_at"}
+
+	sqlStr, params, errSql := st.dialect().Insert(st.attributeValueTrashTableName).Prepared(true).
+		Cols(append(columns, "deleted_at")...).
+		FromQuery(st.dialect().From(st.attributeValueTableName).
+			Select(append(columns, goqu.V(time.Now()))...).
+			Where(goqu.C("entity_id").Eq(entityID))).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if _, err := st.sqlExec("EntityTrash", tx, false, sqlStr, params...); err != nil {
+		return err
+	}
+
+	return st.attributeValuesDelete(tx, entityID)
+}
+
+// attributeValuesRestore moves the trashed values of the multi-valued
+// attributes of an entity back from the trash table
+func (st *Store) attributeValuesRestore(tx *sql.Tx, entityID string) error {
+	columns := []any{"id", "entity_id", "attribute_key", "attribute_value", "position", "created_at"}
+
+	sqlStr, params, errSql := st.dialect().Insert(st.attributeValueTableName).Prepared(true).
+		Cols(columns...).
+		FromQuery(st.dialect().From(st.attributeValueTrashTableName).
+			Select(columns...).
+			Where(goqu.C("entity_id").Eq(entityID))).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if _, err := st.sqlExec("EntityRestore", tx, false, sqlStr, params...); err != nil {
+		return err
+	}
+
+	sqlStr, params, errSql = st.dialect().From(st.attributeValueTrashTableName).Prepared(true).
+		Where(goqu.C("entity_id").Eq(entityID)).
+		Delete().
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("EntityRestore", tx, false, sqlStr, params...)
+
+	return err
+}
+
+// entityQueryValueFilters adds the filters on the multi-valued attributes
+// to an entity query. The keys are sorted for a stable SQL. The subselects
+// are given as goqu.Ex, as In wraps them in a second pair of parentheses
+func (st *Store) entityQueryValueFilters(q *goqu.SelectDataset, options EntityQueryOptions) *goqu.SelectDataset {
+	for _, key := range sortedKeys(options.ListContains) {
+		q = q.Where(goqu.Ex{"id": st.dialect().From(st.attributeValueTableName).
+			Select("entity_id").
+			Where(goqu.C("attribute_key").Eq(key), goqu.C("attribute_value").Eq(options.ListContains[key]))})
+	}
+
+	for _, key := range sortedKeys(options.ListContainsAny) {
+		// No value can be contained in an empty list of values
+		if len(options.ListContainsAny[key]) == 0 {
+			q = q.Where(goqu.L("1 = 0"))
+			continue
+		}
+
+		q = q.Where(goqu.Ex{"id": st.dialect().From(st.attributeValueTableName).
+			Select("entity_id").
+			Where(goqu.C("attribute_key").Eq(key), goqu.C("attribute_value").In(options.ListContainsAny[key]))})
+	}
+
+	return q
+}
+
+// sortedKeys returns the keys of the map, sorted
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
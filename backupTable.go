@@ -0,0 +1,294 @@
+package entitystore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/golang-module/carbon/v2"
+)
+
+// backupPageSize is the number of rows read at once by a backup
+const backupPageSize = 500
+
+// backupInsertParams is the most parameters of an insert of a restore,
+// SQL Server allowing 2100 parameters in a statement
+const backupInsertParams = 2000
+
+// backupTable is a table of the store included in a backup, the rows of
+// which are identified by name in the backup, not by table name
+type backupTable struct {
+	name      string
+	tableName string
+	columns   []string
+
+	// key are the columns the rows are ordered by
+	key []string
+}
+
+// insertRows returns the number of rows inserted at once by a restore, so
+// an insert has at most backupInsertParams parameters
+func (table backupTable) insertRows() int {
+	return backupInsertParams / len(table.columns)
+}
+
+// backupTables returns the tables of a backup, in the order they are
+// written and restored. The outbox and the locks are not backed up
+func (st *Store) backupTables() []backupTable {
+	entityColumns := []string{"id", "entity_type", "entity_handle", "created_at", "updated_at", "version"}
+	attributeColumns := []string{"id", "entity_id", "attribute_key", "attribute_value", "created_at", "updated_at"}
+	valueColumns := []string{"id", "entity_id", "attribute_key", "attribute_value", "position", "created_at"}
+	trashColumns := []string{"deleted_at", "deleted_by"}
+
+	return []backupTable{
+		{
+			name:      "entity",
+			tableName: st.entityTableName,
+			columns:   entityColumns,
+			key:       []string{"id"},
+		},
+		{
+			name:      "attribute",
+			tableName: st.attributeTableName,
+			columns:   attributeColumns,
+			key:       []string{"id"},
+		},
+		{
+			name:      "entity_trash",
+			tableName: st.entityTrashTableName,
+			columns:   append(append([]string{}, entityColumns...), trashColumns...),
+			key:       []string{"id"},
+		},
+		{
+			name:      "attribute_trash",
+			tableName: st.attributeTrashTableName,
+			columns:   append(append([]string{}, attributeColumns...), trashColumns...),
+			key:       []string{"id"},
+		},
+		{
+			name:      "attribute_value",
+			tableName: st.attributeValueTableName,
+			columns:   valueColumns,
+			key:       []string{"entity_id", "attribute_key", "position", "id"},
+		},
+		{
+			name:      "attribute_value_trash",
+			tableName: st.attributeValueTrashTableName,
+			columns:   append(append([]string{}, valueColumns...), trashColumns...),
+			key:       []string{"entity_id", "attribute_key", "position", "id"},
+		},
+		{
+			name:      "handle",
+			tableName: st.handleTableName,
+			columns:   []string{"entity_type", "entity_handle", "entity_id", "created_at"},
+			key:       []string{"entity_type", "entity_handle"},
+		},
+	}
+}
+
+// backupColumnIsTime returns whether a backed up column is a timestamp
+func backupColumnIsTime(column string) bool {
+	return column == "created_at" || column == "updated_at" || column == "deleted_at"
+}
+
+// backupColumnIsInt returns whether a backed up column is an integer
+func backupColumnIsInt(column string) bool {
+	return column == "version" || column == "position"
+}
+
+// backupRows reads a page of the rows of a table following the row with
+// the key after, nil for the first page, ordered by the key. The values
+// are typed: string, int64 or time.Time in UTC
+func (st *sqlStorage) backupRows(db txOrDB, table backupTable, after map[string]any) ([]map[string]any, error) {
+	selects := []any{}
+	for _, column := range table.columns {
+		if backupColumnIsTime(column) || backupColumnIsInt(column) {
+			selects = append(selects, goqu.C(column))
+		} else {
+			selects = append(selects, goqu.COALESCE(goqu.C(column), goqu.L("''")).As(column))
+		}
+	}
+
+	order := []exp.OrderedExpression{}
+	for _, column := range table.key {
+		order = append(order, goqu.C(column).Asc())
+	}
+
+	q := st.dialect().From(table.tableName).Prepared(true).
+		Select(selects...).
+		Order(order...).
+		Limit(backupPageSize)
+
+	if after != nil {
+		q = q.Where(backupKeyAfter(table, after))
+	}
+
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	rowMaps := []map[string]string{}
+
+	if err := st.sqlSelect("Backup", db, false, &rowMaps, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]any, 0, len(rowMaps))
+
+	for _, rowMap := range rowMaps {
+		row := map[string]any{}
+
+		for _, column := range table.columns {
+			value := rowMap[column]
+
+			if backupColumnIsInt(column) {
+				number, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, errors.New("backup: invalid " + column + " in table " + table.tableName + ": " + value)
+				}
+				row[column] = number
+			} else if backupColumnIsTime(column) {
+				parsed := carbon.Parse(value, carbon.UTC)
+				if parsed.Error != nil {
+					return nil, errors.New("backup: invalid " + column + " in table " + table.tableName + ": " + value)
+				}
+				row[column] = parsed.ToStdTime().UTC()
+			} else {
+				row[column] = value
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// backupKeyAfter returns the condition of the rows following the row
+// with the key after, in the order of the key. The key columns are
+// compared one by one, not as a row value, which SQL Server lacks
+func backupKeyAfter(table backupTable, after map[string]any) exp.Expression {
+	conditions := []exp.Expression{}
+
+	for i, column := range table.key {
+		condition := []exp.Expression{}
+
+		for _, previous := range table.key[:i] {
+			condition = append(condition, goqu.C(previous).Eq(after[previous]))
+		}
+
+		condition = append(condition, goqu.C(column).Gt(after[column]))
+		conditions = append(conditions, goqu.And(condition...))
+	}
+
+	return goqu.Or(conditions...)
+}
+
+// backupRowDecode decodes a row of a table read from a backup, every
+// column of the table must be present
+func backupRowDecode(table backupTable, data json.RawMessage) (map[string]any, error) {
+	values := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	row := map[string]any{}
+
+	for _, column := range table.columns {
+		value, exists := values[column]
+
+		if !exists {
+			return nil, errors.New("column " + column + " is missing in a row of " + table.name)
+		}
+
+		var err error
+
+		if backupColumnIsInt(column) {
+			number := int64(0)
+			err = json.Unmarshal(value, &number)
+			row[column] = number
+		} else if backupColumnIsTime(column) {
+			moment := time.Time{}
+			err = json.Unmarshal(value, &moment)
+			row[column] = moment
+		} else {
+			text := ""
+			err = json.Unmarshal(value, &text)
+			row[column] = text
+		}
+
+		if err != nil {
+			return nil, errors.New("invalid column " + column + " in a row of " + table.name + ": " + err.Error())
+		}
+	}
+
+	return row, nil
+}
+
+// backupRowsInsert inserts rows of a table read from a backup, at most
+// insertRows of the table
+func (st *sqlStorage) backupRowsInsert(tx *sql.Tx, table backupTable, rows []map[string]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	sqlStr, params, errSql := st.backupRowsInsertSQL(table, rows)
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("Restore", tx, false, sqlStr, params...)
+
+	return err
+}
+
+// backupRowsInsertSQL returns the SQL inserting rows of a table
+func (st *Store) backupRowsInsertSQL(table backupTable, rows []map[string]any) (string, []any, error) {
+	records := make([]any, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, goqu.Record(row))
+	}
+
+	return st.dialect().Insert(table.tableName).Prepared(true).
+		Rows(records...).
+		ToSQL()
+}
+
+// backupRowsSort sorts rows by the key of their table
+func backupRowsSort(table backupTable, rows []map[string]any) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return backupRowsCompare(table, rows[i], rows[j]) < 0
+	})
+}
+
+// backupRowsCompare compares two rows by the key of their table
+func backupRowsCompare(table backupTable, a map[string]any, b map[string]any) int {
+	for _, column := range table.key {
+		valueA, valueB := a[column], b[column]
+
+		if valueA == valueB {
+			continue
+		}
+
+		if number, isInt := valueA.(int64); isInt {
+			if number < valueB.(int64) {
+				return -1
+			}
+			return 1
+		}
+
+		return strings.Compare(valueA.(string), valueB.(string))
+	}
+
+	return 0
+}
@@ -0,0 +1,228 @@
+package entitystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// copyEntities copies the entities matching the filter to the destination
+// store. With since, an entity existing in the destination is only a
+// conflict when it was changed there after since, otherwise it is always
+// a conflict unless it has the updated_at of the source entity
+func (st *Store) copyEntities(dst StoreInterface, filter EntityQueryOptions, options CopyOptions, since *time.Time) (*CopyReport, error) {
+	if err := copyOptionsValidate(st, dst, &options); err != nil {
+		return nil, err
+	}
+
+	report := &CopyReport{}
+
+	if since != nil {
+		report.Watermark = *since
+	}
+
+	// The watermark covers the entities copied or skipped on purpose, up
+	// to the oldest entity which failed to be copied
+	watermark := report.Watermark
+	var oldestFailure *time.Time
+
+	done := func(record EntityRecord) {
+		if record.UpdatedAt.After(watermark) {
+			watermark = record.UpdatedAt
+		}
+	}
+
+	failed := func(record EntityRecord) {
+		if oldestFailure == nil || record.UpdatedAt.Before(*oldestFailure) {
+			oldestFailure = &record.UpdatedAt
+		}
+	}
+
+	position := 0
+
+	err := st.entityPages(filter, entityPageSize, func(entities []Entity) error {
+		records, err := st.entityRecords(entities)
+
+		if err != nil {
+			return err
+		}
+
+		entityIDs := make([]string, 0, len(records))
+		for _, record := range records {
+			entityIDs = append(entityIDs, record.ID)
+		}
+
+		existing, err := dst.EntityList(EntityQueryOptions{IDs: entityIDs})
+
+		if err != nil {
+			return err
+		}
+
+		existingByID := map[string]Entity{}
+		for _, entity := range existing {
+			existingByID[entity.ID()] = entity
+		}
+
+		overwrite := []importLine{}
+		conflicting := []importLine{}
+
+		for _, record := range records {
+			position++
+
+			line := importLine{line: position, record: record}
+			entity, exists := existingByID[record.ID]
+
+			if !exists {
+				var owner *Entity
+
+				if record.Handle != "" {
+					if owner, err = dst.EntityFindByHandle(record.Type, record.Handle, FindOptions{CacheBypass: true}); err != nil {
+						return err
+					}
+				}
+
+				if owner != nil && owner.ID() != record.ID {
+					report.Conflicts = append(report.Conflicts, CopyConflict{
+						EntityID:        record.ID,
+						EntityType:      record.Type,
+						Reason:          CopyConflictHandle,
+						SourceUpdatedAt: record.UpdatedAt,
+						Resolution:      ImportSkip,
+					})
+					report.Skipped++
+					done(record)
+					continue
+				}
+
+				overwrite = append(overwrite, line)
+				continue
+			}
+
+			if copyTimeEqual(entity.UpdatedAt(), record.UpdatedAt) {
+				report.Skipped++
+				done(record)
+				continue
+			}
+
+			if since != nil && !entity.UpdatedAt().After(*since) {
+				overwrite = append(overwrite, line)
+				continue
+			}
+
+			report.Conflicts = append(report.Conflicts, CopyConflict{
+				EntityID:             record.ID,
+				EntityType:           record.Type,
+				Reason:               CopyConflictModified,
+				SourceUpdatedAt:      record.UpdatedAt,
+				DestinationUpdatedAt: entity.UpdatedAt(),
+				Resolution:           options.OnConflict,
+			})
+
+			if options.OnConflict == ImportSkip {
+				report.Skipped++
+				done(record)
+				continue
+			}
+
+			conflicting = append(conflicting, line)
+		}
+
+		for _, batch := range []struct {
+			lines      []importLine
+			onConflict string
+		}{{overwrite, ImportOverwrite}, {conflicting, options.OnConflict}} {
+			failures, err := copyImport(dst, batch.lines, batch.onConflict, options.BatchSize, &report.ImportReport)
+
+			if err != nil {
+				return err
+			}
+
+			for _, line := range batch.lines {
+				if failures[line.line] {
+					failed(line.record)
+				} else {
+					done(line.record)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		// The entities not read yet may be older than those copied, the
+		// watermark is not advanced
+		return report, err
+	}
+
+	report.Watermark = watermark
+
+	if oldestFailure != nil && oldestFailure.Before(watermark) {
+		report.Watermark = *oldestFailure
+	}
+
+	return report, nil
+}
+
+// copyImport imports the copied entities into the destination, with their
+// IDs, adding the outcome to the report. Returns the positions of the
+// entities which failed to be imported
+func copyImport(dst StoreInterface, lines []importLine, onConflict string, batchSize int, report *ImportReport) (map[int]bool, error) {
+	failures := map[int]bool{}
+
+	if len(lines) == 0 {
+		return failures, nil
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	for _, line := range lines {
+		if err := encoder.Encode(line.record); err != nil {
+			return nil, err
+		}
+	}
+
+	imported, err := dst.Import(&buffer, ImportOptions{
+		OnConflict:  onConflict,
+		PreserveIDs: true,
+		BatchSize:   batchSize,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	report.Created += imported.Created
+	report.Updated += imported.Updated
+	report.Skipped += imported.Skipped
+
+	// The lines of the import are those of the buffer, the errors are
+	// reported with the position of the entity in the copy
+	for _, importErr := range imported.Errors {
+		position := lines[importErr.Line-1].line
+		failures[position] = true
+		report.Errors = append(report.Errors, ImportError{Line: position, EntityID: importErr.EntityID, Err: importErr.Err})
+	}
+
+	return failures, nil
+}
+
+// copyTimeEqual returns whether the updated_at of an entity in two stores
+// is the same, to the precision of the database engines: microseconds for
+// PostgreSQL, seconds for the MySQL datetime columns which have no
+// fractional seconds
+func copyTimeEqual(a time.Time, b time.Time) bool {
+	difference := a.Sub(b)
+
+	if difference < 0 {
+		difference = -difference
+	}
+
+	if difference < time.Microsecond {
+		return true
+	}
+
+	return (a.Nanosecond() == 0 || b.Nanosecond() == 0) && difference < time.Second
+}
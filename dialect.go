@@ -0,0 +1,26 @@
+package entitystore
+
+import (
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/mysql"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlserver"
+)
+
+// dialect returns the goqu SQL builder for the database driver of the store
+func (st *Store) dialect() goqu.DialectWrapper {
+	return goqu.Dialect(goquDialectName(st.dbDriverName))
+}
+
+// goquDialectName maps a database driver name to the name of the matching
+// goqu dialect
+func goquDialectName(driverName string) string {
+	if driverName == "sqlite" {
+		return "sqlite3"
+	}
+	if driverName == "mssql" {
+		return "sqlserver"
+	}
+	return driverName
+}
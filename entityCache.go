@@ -0,0 +1,81 @@
+package entitystore
+
+import (
+	"strconv"
+	"strings"
+)
+
+// cacheEnabled returns whether a find uses the cache
+func (st *Store) cacheEnabled(options []FindOptions) bool {
+	if st.cache == nil {
+		return false
+	}
+
+	for _, option := range options {
+		if option.CacheBypass {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cacheKey returns the cache key of the parts, prefixed with the entity
+// table name as several stores may share a cache, and with the epoch of
+// the store, which a clear moves on
+func (st *Store) cacheKey(parts ...string) string {
+	epoch := strconv.FormatUint(st.cacheEpoch.Load(), 10)
+	return st.entityTableName + ":" + epoch + ":" + strings.Join(parts, ":")
+}
+
+// cacheCount counts a cache lookup
+func (st *Store) cacheCount(hit bool) {
+	if hit {
+		st.cacheHits.Add(1)
+	} else {
+		st.cacheMisses.Add(1)
+	}
+}
+
+// cacheSet caches a value read from the database at the generation, unless
+// the store was written since, as the value may be stale then. The check
+// and the set hold the lock of the invalidations, so a value is either
+// not cached or removed by the invalidation of a write made meanwhile
+func (st *Store) cacheSet(key string, value any, generation uint64) {
+	st.cacheMutex.Lock()
+	defer st.cacheMutex.Unlock()
+
+	if st.cacheGeneration.Load() == generation {
+		st.cache.Set(key, value)
+	}
+}
+
+// cacheInvalidate removes the cached entity and attributes of an entity.
+// The cached handles are checked against the entity on read instead
+func (st *Store) cacheInvalidate(entityID string) {
+	if st.cache == nil {
+		return
+	}
+
+	st.cacheMutex.Lock()
+	defer st.cacheMutex.Unlock()
+
+	st.cacheGeneration.Add(1)
+	st.cache.Delete(st.cacheKey("entity", entityID))
+	st.cache.Delete(st.cacheKey("attributes", entityID))
+}
+
+// cacheClear drops all the cached values of the store, moving on to a new
+// epoch. The cache may be shared with other stores, their values are kept,
+// the values of the previous epoch are left to be evicted
+func (st *Store) cacheClear() {
+	if st.cache == nil {
+		return
+	}
+
+	st.cacheMutex.Lock()
+	defer st.cacheMutex.Unlock()
+
+	st.cacheGeneration.Add(1)
+	st.cacheEpoch.Add(1)
+}
@@ -0,0 +1,106 @@
+package entitystore
+
+import (
+	"errors"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/georgysavva/scany/sqlscan"
+)
+
+// sqlAddEntityVersion returns the SQL adding the version column to the
+// entity and entity trash tables
+func (st *Store) sqlAddEntityVersion() ([]string, error) {
+	tableNames := []string{st.entityTableName, st.entityTrashTableName}
+	sqls := []string{}
+
+	for _, tableName := range tableNames {
+		if st.dbDriverName == "mysql" {
+			sqls = append(sqls, st.sqlMysqlAddColumn(tableName, "version", `ALTER TABLE `+tableName+` ADD COLUMN version bigint NOT NULL DEFAULT 0;`)...)
+		} else if st.dbDriverName == "postgres" {
+			sqls = append(sqls, `ALTER TABLE "`+tableName+`" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 0;`)
+		} else if st.dbDriverName == "sqlite" {
+			sqls = append(sqls, `ALTER TABLE "`+tableName+`" ADD COLUMN "version" integer NOT NULL DEFAULT 0;`)
+		} else if st.dbDriverName == "mssql" {
+			sqls = append(sqls, `ALTER TABLE [`+tableName+`] ADD [version] bigint NOT NULL DEFAULT 0;`)
+		} else {
+			return nil, errors.New("unsupported driver " + st.dbDriverName)
+		}
+	}
+
+	return sqls, nil
+}
+
+// entityVersionIncrement returns the expression incrementing the version
+// column in an update
+func entityVersionIncrement() goqu.Expression {
+	return goqu.L("? + 1", goqu.C("version"))
+}
+
+// entityVersionBump increments the version of an entity on a write of its
+// attributes, and sets its updated_at, so the entities changed since a
+// time are found by updated_at. With ifVersion the entity must be at that
+// version, otherwise a VersionConflictError is returned
+func (st *sqlStorage) entityVersionBump(db txOrDB, entityID string, ifVersion *int64) error {
+	q := st.dialect().Update(st.entityTableName).Prepared(true).
+		Set(goqu.Record{"version": entityVersionIncrement(), "updated_at": time.Now()}).
+		Where(goqu.C("id").Eq(entityID))
+
+	if ifVersion != nil {
+		q = q.Where(goqu.C("version").Eq(*ifVersion))
+	}
+
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	result, err := st.sqlExec("EntityVersion", db, false, sqlStr, params...)
+
+	if err != nil || ifVersion == nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return st.entityVersionConflict(db, entityID, *ifVersion)
+	}
+
+	return nil
+}
+
+// entityVersionConflict returns the error of a conditional write of an
+// entity which matched no row
+func (st *Store) entityVersionConflict(db txOrDB, entityID string, expectedVersion int64) error {
+	sqlStr, params, errSql := st.dialect().From(st.entityTableName).Prepared(true).
+		Select("version").
+		Where(goqu.C("id").Eq(entityID)).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	var version int64
+	err := st.sqlGet("EntityVersion", db, false, &version, sqlStr, params...)
+
+	if sqlscan.NotFound(err) {
+		return errors.New("entity store: entity " + entityID + " not found")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return &VersionConflictError{
+		EntityID:        entityID,
+		ExpectedVersion: expectedVersion,
+		ActualVersion:   version,
+	}
+}
@@ -0,0 +1,1735 @@
+// Package entitystoretest provides the conformance suite of the entity
+// store, which any implementation of entitystore.StoreInterface can run
+package entitystoretest
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gouniverse/entitystore"
+)
+
+// RunStoreSuite runs the store behaviour tests against stores created by
+// newStore, so every implementation is held to the same behaviour. Each
+// test gets a new, empty store
+func RunStoreSuite(t *testing.T, newStore func(t *testing.T) entitystore.StoreInterface) {
+	t.Run("EntityCreate", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		found, err := store.EntityFindByID(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if found == nil {
+			t.Fatalf("Entity must be found")
+		}
+
+		if found.Type() != "post" {
+			t.Fatal("Entity type incorrect", "must be 'post'", "found", found.Type())
+		}
+
+		missing, err := store.EntityFindByID("missing")
+
+		if err != nil {
+			t.Fatalf("Entity lookup failed: " + err.Error())
+		}
+
+		if missing != nil {
+			t.Fatalf("Missing entity must not be found")
+		}
+	})
+
+	t.Run("AttributeSet", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreate("product")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if err := entity.SetString("title", "Product 1"); err != nil {
+			t.Fatalf("String could not be set: " + err.Error())
+		}
+
+		if err := entity.SetString("title", "Product 2"); err != nil {
+			t.Fatalf("String could not be updated: " + err.Error())
+		}
+
+		if err := entity.SetInt("quantity", 12); err != nil {
+			t.Fatalf("Int could not be set: " + err.Error())
+		}
+
+		if err := entity.SetFloat("price", 12.35); err != nil {
+			t.Fatalf("Float could not be set: " + err.Error())
+		}
+
+		title, _ := entity.GetString("title", "")
+		quantity, _ := entity.GetInt("quantity", 0)
+		price, _ := entity.GetFloat("price", 0)
+		missing, _ := entity.GetString("missing", "default")
+
+		if title != "Product 2" {
+			t.Fatal("Title incorrect", "must be 'Product 2'", "found", title)
+		}
+
+		if quantity != 12 {
+			t.Fatal("Quantity incorrect", "must be 12", "found", quantity)
+		}
+
+		if price != 12.35 {
+			t.Fatal("Price incorrect", "must be 12.35", "found", price)
+		}
+
+		if missing != "default" {
+			t.Fatal("Missing attribute must return the default value, found", missing)
+		}
+
+		attrs, err := entity.GetAttributes()
+
+		if err != nil {
+			t.Fatalf("Attributes could not be listed: " + err.Error())
+		}
+
+		if len(attrs) != 3 {
+			t.Fatal("Attribute count incorrect", "must be 3", "found", len(attrs))
+		}
+
+		if err := entity.SetAll(map[string]string{"title": "Product 3", "color": "red"}); err != nil {
+			t.Fatalf("Attributes could not be set: " + err.Error())
+		}
+
+		title, _ = entity.GetString("title", "")
+		color, _ := entity.GetString("color", "")
+
+		if title != "Product 3" || color != "red" {
+			t.Fatal("Attributes incorrect", title, color)
+		}
+
+		if _, err := store.AttributeCreate(entity.ID(), "color", "blue"); err == nil {
+			t.Fatalf("Duplicate attribute must not be created")
+		}
+	})
+
+	t.Run("AttributeUpdate", func(t *testing.T) {
+		store := newStore(t)
+
+		attr, err := store.AttributeCreate("default", "hello", "world")
+
+		if err != nil {
+			t.Fatalf("Attribute could not be created: " + err.Error())
+		}
+
+		attr.SetString("again")
+
+		if err := store.AttributeUpdate(*attr); err != nil {
+			t.Fatalf("Attribute could not be updated: " + err.Error())
+		}
+
+		found, err := store.AttributeFind("default", "hello")
+
+		if err != nil {
+			t.Fatalf("Attribute could not be found: " + err.Error())
+		}
+
+		if found == nil || found.GetString() != "again" {
+			t.Fatalf("Attribute must be updated")
+		}
+	})
+
+	t.Run("EntityFindByAttribute", func(t *testing.T) {
+		store := newStore(t)
+
+		home, err := store.EntityCreateWithAttributes("page", map[string]string{"path": "/"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		for _, path := range []string{"/about", "/contact"} {
+			if _, err := store.EntityCreateWithAttributes("page", map[string]string{"path": path, "menu": "main"}); err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+		}
+
+		found, err := store.EntityFindByAttribute("page", "path", "/")
+
+		if err != nil {
+			t.Fatalf("Entity find by attribute failed: " + err.Error())
+		}
+
+		if found == nil || found.ID() != home.ID() {
+			t.Fatalf("Entity found by attribute is incorrect")
+		}
+
+		notFound, err := store.EntityFindByAttribute("post", "path", "/")
+
+		if err != nil {
+			t.Fatalf("Entity find by attribute failed: " + err.Error())
+		}
+
+		if notFound != nil {
+			t.Fatalf("Entity of another type must not be found")
+		}
+
+		list, err := store.EntityListByAttribute("page", "menu", "main")
+
+		if err != nil {
+			t.Fatalf("Entity list by attribute failed: " + err.Error())
+		}
+
+		if len(list) != 2 {
+			t.Fatal("Entity count incorrect", "must be 2", "found", len(list))
+		}
+	})
+
+	t.Run("EntityList", func(t *testing.T) {
+		store := newStore(t)
+
+		ids := []string{}
+
+		for i := 0; i < 5; i++ {
+			entity, err := store.EntityCreate("post")
+
+			if err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+
+			ids = append(ids, entity.ID())
+		}
+
+		if _, err := store.EntityCreate("page"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		count, err := store.EntityCount(entitystore.EntityQueryOptions{EntityType: "post"})
+
+		if err != nil {
+			t.Fatalf("Entities could not be counted: " + err.Error())
+		}
+
+		if count != 5 {
+			t.Fatal("Entity count incorrect", "must be 5", "found", count)
+		}
+
+		page, err := store.EntityList(entitystore.EntityQueryOptions{
+			EntityType: "post",
+			SortBy:     "id",
+			SortOrder:  "desc",
+			Offset:     1,
+			Limit:      2,
+		})
+
+		if err != nil {
+			t.Fatalf("Entities could not be listed: " + err.Error())
+		}
+
+		if len(page) != 2 {
+			t.Fatal("Page size incorrect", "must be 2", "found", len(page))
+		}
+
+		if page[0].ID() != ids[3] || page[1].ID() != ids[2] {
+			t.Fatal("Page incorrect", page[0].ID(), page[1].ID())
+		}
+
+		byIDs, err := store.EntityList(entitystore.EntityQueryOptions{IDs: []string{ids[0], ids[4]}})
+
+		if err != nil {
+			t.Fatalf("Entities could not be listed: " + err.Error())
+		}
+
+		if len(byIDs) != 2 || byIDs[0].ID() != ids[0] {
+			t.Fatal("Entities listed by IDs incorrect", len(byIDs))
+		}
+
+		if _, err := store.EntityList(entitystore.EntityQueryOptions{SortBy: "unknown"}); err == nil {
+			t.Fatalf("Sorting by an unknown column must fail")
+		}
+	})
+
+	t.Run("EntityUpdate", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		entity.SetType("article")
+
+		if _, err := store.EntityUpdate(*entity); err != nil {
+			t.Fatalf("Entity could not be updated: " + err.Error())
+		}
+
+		count, err := store.EntityCount(entitystore.EntityQueryOptions{EntityType: "article"})
+
+		if err != nil {
+			t.Fatalf("Entities could not be counted: " + err.Error())
+		}
+
+		if count != 1 {
+			t.Fatal("Entity type must be updated, found count", count)
+		}
+	})
+
+	t.Run("EntityTrash", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Test"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		isTrashed, err := store.EntityTrash(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Entity could not be trashed: " + err.Error())
+		}
+
+		if !isTrashed {
+			t.Fatalf("Entity must be trashed")
+		}
+
+		found, _ := store.EntityFindByID(entity.ID())
+		attr, _ := store.AttributeFind(entity.ID(), "title")
+
+		if found != nil || attr != nil {
+			t.Fatalf("Trashed entity and attributes must no longer be present")
+		}
+
+		isTrashed, err = store.EntityTrash(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Trashing a missing entity must not fail: " + err.Error())
+		}
+
+		if isTrashed {
+			t.Fatalf("Missing entity must not be trashed")
+		}
+	})
+
+	t.Run("EntityDelete", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Test"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		isDeleted, err := store.EntityDelete(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Entity could not be deleted: " + err.Error())
+		}
+
+		if !isDeleted {
+			t.Fatalf("Entity must be deleted")
+		}
+
+		found, _ := store.EntityFindByID(entity.ID())
+		attrs, _ := store.EntityAttributeList(entity.ID())
+
+		if found != nil || len(attrs) != 0 {
+			t.Fatalf("Deleted entity and attributes must no longer be present")
+		}
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreate("counter")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key := "key_" + strconv.Itoa(i)
+				if err := entity.SetString(key, strconv.Itoa(i)); err != nil {
+					t.Error("Attribute could not be set: " + err.Error())
+				}
+			}(i)
+		}
+
+		wg.Wait()
+
+		attrs, err := entity.GetAttributes()
+
+		if err != nil {
+			t.Fatalf("Attributes could not be listed: " + err.Error())
+		}
+
+		if len(attrs) != 10 {
+			t.Fatal("Attribute count incorrect", "must be 10", "found", len(attrs))
+		}
+	})
+
+	t.Run("EntityRestore", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Test"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if _, err := store.EntityTrash(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be trashed: " + err.Error())
+		}
+
+		isRestored, err := store.EntityRestore(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Entity could not be restored: " + err.Error())
+		}
+
+		if !isRestored {
+			t.Fatalf("Entity must be restored")
+		}
+
+		found, _ := store.EntityFindByID(entity.ID())
+		attr, _ := store.AttributeFind(entity.ID(), "title")
+
+		if found == nil || found.Type() != "post" || attr == nil || attr.GetString() != "Test" {
+			t.Fatalf("Restored entity and attributes must be present")
+		}
+
+		isRestored, err = store.EntityRestore(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Restoring an entity not in the trash must not fail: " + err.Error())
+		}
+
+		if isRestored {
+			t.Fatalf("Entity not in the trash must not be restored")
+		}
+
+		// The values of a set are trashed and restored with the entity
+		if err := store.AttributeAddToSet(entity.ID(), "tags", "go", "db"); err != nil {
+			t.Fatalf("Values could not be added: " + err.Error())
+		}
+
+		if _, err := store.EntityTrash(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be trashed: " + err.Error())
+		}
+
+		if tags, _ := store.AttributeValues(entity.ID(), "tags"); len(tags) != 0 {
+			t.Fatal("Set of a trashed entity must be empty", "found", tags)
+		}
+
+		if stats, _ := store.Stats(); stats.AttributeValues != 0 || stats.AttributeValuesTrashed != 2 {
+			t.Fatal("Values incorrect", "must be 0 and 2 trashed", "found", stats.AttributeValues, stats.AttributeValuesTrashed)
+		}
+
+		if _, err := store.EntityRestore(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be restored: " + err.Error())
+		}
+
+		if tags, _ := store.AttributeValues(entity.ID(), "tags"); strings.Join(tags, ",") != "go,db" {
+			t.Fatal("Restored set incorrect", "must be go,db", "found", tags)
+		}
+
+		if stats, _ := store.Stats(); stats.AttributeValues != 2 || stats.AttributeValuesTrashed != 0 {
+			t.Fatal("Values incorrect", "must be 2 and 0 trashed", "found", stats.AttributeValues, stats.AttributeValuesTrashed)
+		}
+	})
+
+	t.Run("EntityHooks", func(t *testing.T) {
+		store := newStore(t)
+
+		calls := []string{}
+
+		record := func(event *entitystore.EntityHookEvent) error {
+			calls = append(calls, string(event.Type))
+			return nil
+		}
+
+		for _, hookType := range []entitystore.EntityHookType{
+			entitystore.EntityAfterCreate,
+			entitystore.EntityAfterUpdate,
+			entitystore.EntityAfterAttributeSet,
+			entitystore.EntityAfterTrash,
+			entitystore.EntityAfterRestore,
+			entitystore.EntityAfterDelete,
+		} {
+			store.RegisterEntityHook("post", hookType, record)
+		}
+
+		store.RegisterEntityHook("post", entitystore.EntityBeforeCreate, func(event *entitystore.EntityHookEvent) error {
+			event.Attributes["status"] = "draft"
+			return nil
+		})
+
+		store.RegisterEntityHook("", entitystore.EntityBeforeTrash, func(event *entitystore.EntityHookEvent) error {
+			if locked, _ := event.Entity.GetString("locked", ""); locked == "yes" {
+				return errors.New("entity is locked")
+			}
+			return nil
+		})
+
+		entity, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if status, _ := entity.GetString("status", ""); status != "draft" {
+			t.Fatal("Attribute stamped on create must be saved, found", status)
+		}
+
+		if _, err := store.EntityUpdate(*entity); err != nil {
+			t.Fatalf("Entity could not be updated: " + err.Error())
+		}
+
+		if err := entity.SetString("locked", "yes"); err != nil {
+			t.Fatalf("Attribute could not be set: " + err.Error())
+		}
+
+		if _, err := store.EntityTrash(entity.ID()); err == nil {
+			t.Fatalf("Trashing a locked entity must be vetoed")
+		}
+
+		if found, _ := store.EntityFindByID(entity.ID()); found == nil {
+			t.Fatalf("Vetoed entity must not be trashed")
+		}
+
+		if err := entity.SetString("locked", "no"); err != nil {
+			t.Fatalf("Attribute could not be set: " + err.Error())
+		}
+
+		if _, err := store.EntityTrash(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be trashed: " + err.Error())
+		}
+
+		if _, err := store.EntityRestore(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be restored: " + err.Error())
+		}
+
+		if _, err := store.EntityDelete(entity.ID()); err != nil {
+			t.Fatalf("Entity could not be deleted: " + err.Error())
+		}
+
+		if _, err := store.EntityCreate("page"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		expected := "after_create,after_update,after_attribute_set,after_attribute_set,after_trash,after_restore,after_delete"
+
+		if strings.Join(calls, ",") != expected {
+			t.Fatal("Hooks called incorrectly", "must be", expected, "found", calls)
+		}
+	})
+
+	t.Run("EntityHookRollback", func(t *testing.T) {
+		store := newStore(t)
+
+		invoice, err := store.EntityCreateWithAttributes("invoice", map[string]string{"total": "10"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		fail := func(event *entitystore.EntityHookEvent) error {
+			return errors.New("hook failed")
+		}
+
+		store.RegisterEntityHook("invoice", entitystore.EntityAfterCreate, fail)
+		store.RegisterEntityHook("invoice", entitystore.EntityAfterTrash, fail)
+
+		if _, err := store.EntityCreateWithAttributes("invoice", map[string]string{"total": "20"}); err == nil {
+			t.Fatal("Create with a failing hook must fail")
+		}
+
+		count, err := store.EntityCount(entitystore.EntityQueryOptions{EntityType: "invoice"})
+
+		if err != nil {
+			t.Fatalf("Entities could not be counted: " + err.Error())
+		}
+
+		if count != 1 {
+			t.Fatal("Create with a failing hook must be rolled back, found", count)
+		}
+
+		if _, err := store.EntityTrash(invoice.ID()); err == nil {
+			t.Fatal("Trash with a failing hook must fail")
+		}
+
+		found, err := store.EntityFindByID(invoice.ID())
+
+		if err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if found == nil {
+			t.Fatal("Trash with a failing hook must be rolled back")
+		}
+
+		if total, _ := found.GetString("total", ""); total != "10" {
+			t.Fatal("Attributes must be restored by the rollback, found", total)
+		}
+	})
+
+	t.Run("EntityVersion", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if entity.Version() != 1 {
+			t.Fatal("Version incorrect", "must be 1", "found", entity.Version())
+		}
+
+		if err := store.AttributesSetIfVersion(entity.ID(), 1, map[string]string{"title": "First"}); err != nil {
+			t.Fatalf("Attributes could not be set: " + err.Error())
+		}
+
+		// A second editor saving with the version read before must fail
+		err = store.AttributesSetIfVersion(entity.ID(), 1, map[string]string{"title": "Second"})
+
+		var conflict *entitystore.VersionConflictError
+		if !errors.Is(err, entitystore.ErrVersionConflict) || !errors.As(err, &conflict) || conflict.ActualVersion != 2 {
+			t.Fatal("Version conflict expected", "found", err)
+		}
+
+		if title, _ := entity.GetString("title", ""); title != "First" {
+			t.Fatal("Conflicting attributes must not be set", "found", title)
+		}
+
+		found, _ := store.EntityFindByID(entity.ID())
+		found.SetHandle("first")
+
+		if _, err := store.EntityUpdateIfVersion(*found, 2); err != nil {
+			t.Fatalf("Entity could not be updated: " + err.Error())
+		}
+
+		if _, err := store.EntityUpdateIfVersion(*found, 2); !errors.Is(err, entitystore.ErrVersionConflict) {
+			t.Fatal("Version conflict expected", "found", err)
+		}
+
+		if err := entity.SetString("title", "Third"); err != nil {
+			t.Fatalf("Attribute could not be set: " + err.Error())
+		}
+
+		found, _ = store.EntityFindByID(entity.ID())
+
+		if found.Version() != 4 || found.Handle() != "first" {
+			t.Fatal("Entity incorrect", "must be at version 4", "found", found.Version(), found.Handle())
+		}
+
+		if err := store.AttributesSetIfVersion("missing", 1, map[string]string{"title": "Missing"}); err == nil || errors.Is(err, entitystore.ErrVersionConflict) {
+			t.Fatal("Missing entity must fail without a conflict", "found", err)
+		}
+	})
+
+	t.Run("EntityLock", func(t *testing.T) {
+		store := newStore(t)
+
+		lease, err := store.EntityLock("job1", "worker1", time.Minute)
+
+		if err != nil {
+			t.Fatalf("Entity could not be locked: " + err.Error())
+		}
+
+		if lease.Owner != "worker1" || lease.Token != 1 || lease.Expired() {
+			t.Fatal("Lease incorrect", lease)
+		}
+
+		_, err = store.EntityLock("job1", "worker2", time.Minute)
+
+		var lockedErr *entitystore.LockedError
+		if !errors.Is(err, entitystore.ErrLocked) || !errors.As(err, &lockedErr) || lockedErr.Owner != "worker1" {
+			t.Fatal("Locked error expected", "found", err)
+		}
+
+		if _, err := store.EntityLockRenew("job1", "worker1", time.Minute); err != nil {
+			t.Fatalf("Lock could not be renewed: " + err.Error())
+		}
+
+		if _, err := store.EntityLockRenew("job1", "worker2", time.Minute); !errors.Is(err, entitystore.ErrLocked) {
+			t.Fatal("Locked error expected", "found", err)
+		}
+
+		if err := store.EntityUnlock("job1", "worker2"); !errors.Is(err, entitystore.ErrLocked) {
+			t.Fatal("Locked error expected", "found", err)
+		}
+
+		if err := store.EntityUnlock("job1", "worker1"); err != nil {
+			t.Fatalf("Entity could not be unlocked: " + err.Error())
+		}
+
+		if lease, err := store.EntityLock("job1", "worker2", time.Minute); err != nil || lease.Token != 1 {
+			t.Fatal("Unlocked entity must be locked again", lease, err)
+		}
+
+		// An expired lease is taken over by another owner
+		if _, err := store.EntityLock("job2", "worker1", 10*time.Millisecond); err != nil {
+			t.Fatalf("Entity could not be locked: " + err.Error())
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		lease, err = store.EntityLock("job2", "worker2", time.Minute)
+
+		if err != nil {
+			t.Fatalf("Expired lock must be taken over: " + err.Error())
+		}
+
+		if lease.Token != 2 {
+			t.Fatal("Token must increase on take over", "found", lease.Token)
+		}
+
+		if _, err := store.EntityLockRenew("job2", "worker1", time.Minute); !errors.Is(err, entitystore.ErrLocked) {
+			t.Fatal("Locked error expected", "found", err)
+		}
+
+		if err := store.EntityUnlock("job2", "worker2"); err != nil {
+			t.Fatalf("Entity could not be unlocked: " + err.Error())
+		}
+
+		if _, err := store.EntityLockRenew("job2", "worker2", time.Minute); !errors.Is(err, entitystore.ErrLockNotHeld) {
+			t.Fatal("Lock not held error expected", "found", err)
+		}
+
+		// An expired lease is lost, even while not taken over
+		if _, err := store.EntityLock("job4", "worker1", 10*time.Millisecond); err != nil {
+			t.Fatalf("Entity could not be locked: " + err.Error())
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		if _, err := store.EntityLockRenew("job4", "worker1", time.Minute); !errors.Is(err, entitystore.ErrLockNotHeld) {
+			t.Fatal("Renewing an expired lease must fail with lock not held", "found", err)
+		}
+
+		if lease, err := store.EntityLock("job4", "worker2", time.Minute); err != nil || lease.Token != 2 {
+			t.Fatal("Expired lease must be taken over after a failed renewal", lease, err)
+		}
+
+		// Exactly one of the concurrent owners gets the lock
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		locked := 0
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				_, err := store.EntityLock("job3", "worker"+strconv.Itoa(i), time.Minute)
+
+				if err != nil && !errors.Is(err, entitystore.ErrLocked) {
+					t.Errorf("Lock failed: " + err.Error())
+					return
+				}
+
+				if err == nil {
+					mutex.Lock()
+					locked++
+					mutex.Unlock()
+				}
+			}(i)
+		}
+
+		wg.Wait()
+
+		if locked != 1 {
+			t.Fatal("One owner must get the lock", "found", locked)
+		}
+	})
+
+	t.Run("AttributeIncrement", func(t *testing.T) {
+		store := newStore(t)
+
+		entity, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		// Concurrent increments are not lost, also of a missing attribute
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				if _, err := store.AttributeIncrement(entity.ID(), "views", 1); err != nil {
+					t.Errorf("Attribute could not be incremented: " + err.Error())
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		views, err := entity.Increment("views", 5)
+
+		if err != nil {
+			t.Fatalf("Attribute could not be incremented: " + err.Error())
+		}
+
+		if views != 15 {
+			t.Fatal("Views incorrect", "must be 15", "found", views)
+		}
+
+		if views, _ := entity.GetInt("views", 0); views != 15 {
+			t.Fatal("Stored views incorrect", "must be 15", "found", views)
+		}
+
+		if _, err := entity.IncrementFloat("quota", 1.5); err != nil {
+			t.Fatalf("Attribute could not be incremented: " + err.Error())
+		}
+
+		quota, err := store.AttributeIncrementFloat(entity.ID(), "quota", -0.25)
+
+		if err != nil {
+			t.Fatalf("Attribute could not be incremented: " + err.Error())
+		}
+
+		if quota != 1.25 {
+			t.Fatal("Quota incorrect", "must be 1.25", "found", quota)
+		}
+
+		// A float sum is stored as by AttributeSetFloat, and an int
+		// increment still applies to it
+		if _, err := store.AttributeIncrementFloat(entity.ID(), "quota", 0.75); err != nil {
+			t.Fatalf("Attribute could not be incremented: " + err.Error())
+		}
+
+		if stored, _ := entity.GetString("quota", ""); stored != strconv.FormatFloat(2, 'f', 30, 64) {
+			t.Fatal("Stored quota incorrect", "must be", strconv.FormatFloat(2, 'f', 30, 64), "found", stored)
+		}
+
+		quotaInt, err := store.AttributeIncrement(entity.ID(), "quota", 1)
+
+		if err != nil {
+			t.Fatalf("Attribute could not be incremented: " + err.Error())
+		}
+
+		if quotaInt != 3 {
+			t.Fatal("Quota incorrect", "must be 3", "found", quotaInt)
+		}
+
+		if quotaInt, _ := entity.GetInt("quota", 0); quotaInt != 3 {
+			t.Fatal("Stored quota incorrect", "must be 3", "found", quotaInt)
+		}
+
+		if _, err := store.AttributeIncrement("missing", "views", 1); err == nil {
+			t.Fatal("Increment of a missing entity must fail")
+		}
+
+		if attr, _ := store.AttributeFind("missing", "views"); attr != nil {
+			t.Fatal("Increment of a missing entity must not insert the attribute")
+		}
+	})
+
+	t.Run("AttributeValues", func(t *testing.T) {
+		store := newStore(t)
+
+		post, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		other, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if err := post.AppendToList("history", "draft", "review", "draft"); err != nil {
+			t.Fatalf("Values could not be appended: " + err.Error())
+		}
+
+		if history, _ := post.GetList("history"); strings.Join(history, ",") != "draft,review,draft" {
+			t.Fatal("List incorrect", "must be draft,review,draft", "found", history)
+		}
+
+		if err := post.AddToSet("tags", "go", "db", "go"); err != nil {
+			t.Fatalf("Values could not be added: " + err.Error())
+		}
+
+		if err := store.AttributeAddToSet(post.ID(), "tags", "db", "orm"); err != nil {
+			t.Fatalf("Values could not be added: " + err.Error())
+		}
+
+		if tags, _ := store.AttributeValues(post.ID(), "tags"); strings.Join(tags, ",") != "go,db,orm" {
+			t.Fatal("Set incorrect", "must be go,db,orm", "found", tags)
+		}
+
+		if err := post.RemoveFromSet("tags", "db", "missing"); err != nil {
+			t.Fatalf("Values could not be removed: " + err.Error())
+		}
+
+		if tags, _ := post.GetList("tags"); strings.Join(tags, ",") != "go,orm" {
+			t.Fatal("Set incorrect", "must be go,orm", "found", tags)
+		}
+
+		if err := other.AddToSet("tags", "db"); err != nil {
+			t.Fatalf("Values could not be added: " + err.Error())
+		}
+
+		if err := other.AddToSet("roles", "admin"); err != nil {
+			t.Fatalf("Values could not be added: " + err.Error())
+		}
+
+		tagged, err := store.EntityList(entitystore.EntityQueryOptions{
+			ListContains: map[string]string{"tags": "go"},
+		})
+
+		if err != nil {
+			t.Fatalf("Entities could not be listed: " + err.Error())
+		}
+
+		if len(tagged) != 1 || tagged[0].ID() != post.ID() {
+			t.Fatal("Entities tagged go incorrect", len(tagged))
+		}
+
+		count, err := store.EntityCount(entitystore.EntityQueryOptions{
+			ListContainsAny: map[string][]string{"tags": {"orm", "db"}},
+		})
+
+		if err != nil {
+			t.Fatalf("Entities could not be counted: " + err.Error())
+		}
+
+		if count != 2 {
+			t.Fatal("Entities tagged orm or db incorrect", "must be 2", "found", count)
+		}
+
+		both, err := store.EntityList(entitystore.EntityQueryOptions{
+			ListContains:    map[string]string{"roles": "admin"},
+			ListContainsAny: map[string][]string{"tags": {"go", "db"}},
+		})
+
+		if err != nil {
+			t.Fatalf("Entities could not be listed: " + err.Error())
+		}
+
+		if len(both) != 1 || both[0].ID() != other.ID() {
+			t.Fatal("Entities with both filters incorrect", len(both))
+		}
+
+		if _, err := store.EntityDelete(post.ID()); err != nil {
+			t.Fatalf("Entity could not be deleted: " + err.Error())
+		}
+
+		if tags, _ := store.AttributeValues(post.ID(), "tags"); len(tags) != 0 {
+			t.Fatal("Values of a deleted entity must be deleted", "found", tags)
+		}
+	})
+
+	t.Run("EntityHandle", func(t *testing.T) {
+		store := newStore(t)
+
+		about, err := store.EntityCreateWithHandle("page", "about", map[string]string{"title": "About"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if _, err := store.EntityCreateWithHandle("page", "about", nil); !errors.Is(err, entitystore.ErrHandleTaken) {
+			t.Fatal("Taken handle must fail with ErrHandleTaken", "found", err)
+		}
+
+		if _, err := store.EntityCreateWithHandle("post", "about", nil); err != nil {
+			t.Fatalf("Handle must be unique per entity type: " + err.Error())
+		}
+
+		handle, err := store.EntityHandleGenerate("page", "About!")
+
+		if err != nil {
+			t.Fatalf("Handle could not be generated: " + err.Error())
+		}
+
+		if handle != "about-2" {
+			t.Fatal("Generated handle incorrect", "must be about-2", "found", handle)
+		}
+
+		other, err := store.EntityCreateWithHandle("page", handle, nil)
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if err := store.EntitySetHandle(about.ID(), "about-us"); err != nil {
+			t.Fatalf("Handle could not be set: " + err.Error())
+		}
+
+		found, err := store.EntityFindByHandle("page", "about")
+
+		if err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if found == nil || found.ID() != about.ID() || found.Handle() != "about-us" {
+			t.Fatal("Entity must be found by its previous handle")
+		}
+
+		if err := store.EntitySetHandle(other.ID(), "about"); !errors.Is(err, entitystore.ErrHandleTaken) {
+			t.Fatal("Previous handle of another entity must fail with ErrHandleTaken", "found", err)
+		}
+
+		if handle, _ := store.EntityHandleGenerate("page", "About"); handle != "about-3" {
+			t.Fatal("Generated handle incorrect", "must be about-3", "found", handle)
+		}
+
+		listed, err := store.EntityList(entitystore.EntityQueryOptions{EntityType: "page", EntityHandle: "about-us"})
+
+		if err != nil {
+			t.Fatalf("Entities could not be listed: " + err.Error())
+		}
+
+		if len(listed) != 1 || listed[0].ID() != about.ID() {
+			t.Fatal("Entities listed by handle incorrect", len(listed))
+		}
+
+		// A trashed entity is not found by handle, but keeps its handles
+		// for a restore
+		if _, err := store.EntityTrash(about.ID()); err != nil {
+			t.Fatalf("Entity could not be trashed: " + err.Error())
+		}
+
+		if found, err := store.EntityFindByHandle("page", "about-us"); err != nil || found != nil {
+			t.Fatal("Trashed entity must not be found by handle", "found", found, err)
+		}
+
+		if _, err := store.EntityCreateWithHandle("page", "about-us", nil); !errors.Is(err, entitystore.ErrHandleTaken) {
+			t.Fatal("Handle of a trashed entity must fail with ErrHandleTaken", "found", err)
+		}
+
+		if _, err := store.EntityRestore(about.ID()); err != nil {
+			t.Fatalf("Entity could not be restored: " + err.Error())
+		}
+
+		if found, _ := store.EntityFindByHandle("page", "about"); found == nil || found.ID() != about.ID() {
+			t.Fatal("Restored entity must be found by its previous handle")
+		}
+
+		if _, err := store.EntityDelete(about.ID()); err != nil {
+			t.Fatalf("Entity could not be deleted: " + err.Error())
+		}
+
+		if found, _ := store.EntityFindByHandle("page", "about"); found != nil {
+			t.Fatalf("Deleted entity must not be found by handle")
+		}
+
+		if _, err := store.EntityCreateWithHandle("page", "about", nil); err != nil {
+			t.Fatalf("Handle of a deleted entity must be free: " + err.Error())
+		}
+
+		// Only one of concurrent creates gets the handle
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		created := 0
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := store.EntityCreateWithHandle("tag", "go", nil)
+
+				if err != nil && !errors.Is(err, entitystore.ErrHandleTaken) {
+					t.Errorf("Unexpected error: " + err.Error())
+				}
+
+				if err == nil {
+					mutex.Lock()
+					created++
+					mutex.Unlock()
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		if created != 1 {
+			t.Fatal("Concurrent creates incorrect", "must be 1", "found", created)
+		}
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		store := newStore(t)
+
+		employees := []map[string]string{
+			{"department": "eng", "salary": "100", "status": "active"},
+			{"department": "eng", "salary": "200", "status": "active"},
+			{"department": "sales", "salary": "50", "status": "inactive"},
+			{"salary": "10", "status": "active"},
+			{"department": "eng"},
+		}
+
+		for _, attributes := range employees {
+			if _, err := store.EntityCreateWithAttributes("employee", attributes); err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+		}
+
+		if _, err := store.EntityCreateWithAttributes("contractor", map[string]string{"salary": "1000"}); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		expected := map[string]float64{
+			entitystore.AggregateSum:   360,
+			entitystore.AggregateAvg:   90,
+			entitystore.AggregateMin:   10,
+			entitystore.AggregateMax:   200,
+			entitystore.AggregateCount: 4,
+		}
+
+		for function, value := range expected {
+			results, err := store.Aggregate("employee", entitystore.AggregateOptions{Func: function, Key: "salary"})
+
+			if err != nil {
+				t.Fatalf("Attribute could not be aggregated: " + err.Error())
+			}
+
+			if len(results) != 1 || results[0].Value != value {
+				t.Fatal("Aggregate incorrect", function, "must be", value, "found", results)
+			}
+		}
+
+		totals, err := store.Aggregate("employee", entitystore.AggregateOptions{
+			Func:    entitystore.AggregateSum,
+			Key:     "salary",
+			GroupBy: "department",
+		})
+
+		if err != nil {
+			t.Fatalf("Attribute could not be aggregated: " + err.Error())
+		}
+
+		if len(totals) != 3 ||
+			totals[0] != (entitystore.AggregateResult{Group: "", Value: 10}) ||
+			totals[1] != (entitystore.AggregateResult{Group: "eng", Value: 300}) ||
+			totals[2] != (entitystore.AggregateResult{Group: "sales", Value: 50}) {
+			t.Fatal("Totals per department incorrect", totals)
+		}
+
+		counts, err := store.Aggregate("employee", entitystore.AggregateOptions{
+			Func:    entitystore.AggregateCount,
+			GroupBy: "department",
+		})
+
+		if err != nil {
+			t.Fatalf("Entities could not be counted: " + err.Error())
+		}
+
+		if len(counts) != 3 || counts[1] != (entitystore.AggregateResult{Group: "eng", Value: 3}) {
+			t.Fatal("Counts per department incorrect", counts)
+		}
+
+		active, err := store.Aggregate("employee", entitystore.AggregateOptions{
+			Func:   entitystore.AggregateSum,
+			Key:    "salary",
+			Filter: map[string]string{"status": "active"},
+		})
+
+		if err != nil {
+			t.Fatalf("Attribute could not be aggregated: " + err.Error())
+		}
+
+		if len(active) != 1 || active[0].Value != 310 {
+			t.Fatal("Total of the active employees incorrect", "must be 310", "found", active)
+		}
+
+		// The values which are not decimal numbers are ignored the same
+		// way by every backend
+		for _, weight := range []string{"10", "2.5", "-0.5", "heavy", "12kg", "1.2.3", "1e3", " 7"} {
+			if _, err := store.EntityCreateWithAttributes("parcel", map[string]string{"weight": weight}); err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+		}
+
+		expected = map[string]float64{
+			entitystore.AggregateSum:   12,
+			entitystore.AggregateAvg:   4,
+			entitystore.AggregateMin:   -0.5,
+			entitystore.AggregateMax:   10,
+			entitystore.AggregateCount: 8,
+		}
+
+		for function, value := range expected {
+			results, err := store.Aggregate("parcel", entitystore.AggregateOptions{Func: function, Key: "weight"})
+
+			if err != nil {
+				t.Fatalf("Attribute could not be aggregated: " + err.Error())
+			}
+
+			if len(results) != 1 || results[0].Value != value {
+				t.Fatal("Aggregate of mixed values incorrect", function, "must be", value, "found", results)
+			}
+		}
+
+		if _, err := store.Aggregate("employee", entitystore.AggregateOptions{Func: "median", Key: "salary"}); err == nil {
+			t.Fatalf("Unsupported function must fail")
+		}
+	})
+
+	t.Run("Facets", func(t *testing.T) {
+		store := newStore(t)
+
+		products := []map[string]string{
+			{"color": "red", "size": "m", "status": "active"},
+			{"color": "red", "size": "l", "status": "active"},
+			{"color": "blue", "size": "m", "status": "active"},
+			{"color": "green", "size": "m", "status": "inactive"},
+			{"size": "s", "status": "active"},
+		}
+
+		for _, attributes := range products {
+			if _, err := store.EntityCreateWithAttributes("product", attributes); err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+		}
+
+		if _, err := store.EntityCreateWithAttributes("review", map[string]string{"color": "red"}); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		colors, err := store.AttributeDistinctValues("product", "color", nil)
+
+		if err != nil {
+			t.Fatalf("Values could not be listed: " + err.Error())
+		}
+
+		if strings.Join(colors, ",") != "blue,green,red" {
+			t.Fatal("Distinct values incorrect", "must be blue,green,red", "found", colors)
+		}
+
+		colors, err = store.AttributeDistinctValues("product", "color", map[string]string{"size": "m"})
+
+		if err != nil {
+			t.Fatalf("Values could not be listed: " + err.Error())
+		}
+
+		if strings.Join(colors, ",") != "blue,green,red" {
+			t.Fatal("Filtered distinct values incorrect", "must be blue,green,red", "found", colors)
+		}
+
+		facets, err := store.Facets("product", []string{"color", "size"}, map[string]string{"status": "active"}, 0)
+
+		if err != nil {
+			t.Fatalf("Facets could not be counted: " + err.Error())
+		}
+
+		expectedColors := []entitystore.FacetValue{{Value: "red", Count: 2}, {Value: "blue", Count: 1}}
+		expectedSizes := []entitystore.FacetValue{{Value: "m", Count: 2}, {Value: "l", Count: 1}, {Value: "s", Count: 1}}
+
+		if len(facets) != 2 || !facetsEqual(facets["color"], expectedColors) || !facetsEqual(facets["size"], expectedSizes) {
+			t.Fatal("Facets incorrect", facets)
+		}
+
+		limited, err := store.Facets("product", []string{"size"}, nil, 1)
+
+		if err != nil {
+			t.Fatalf("Facets could not be counted: " + err.Error())
+		}
+
+		if !facetsEqual(limited["size"], []entitystore.FacetValue{{Value: "m", Count: 3}}) {
+			t.Fatal("Limited facets incorrect", limited)
+		}
+	})
+
+	t.Run("Introspection", func(t *testing.T) {
+		store := newStore(t)
+
+		users := []map[string]string{
+			{"age": "30", "score": "1.5", "admin": "true", "name": "Ann", "prefs": `{"theme":"dark"}`},
+			{"age": "41", "score": "2", "admin": "false", "name": "Bob", "prefs": "[]"},
+			{"age": "", "name": "42"},
+		}
+
+		for _, attributes := range users {
+			if _, err := store.EntityCreateWithAttributes("user", attributes); err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+		}
+
+		post, err := store.EntityCreate("post")
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if err := post.AddToSet("tags", "go", "db"); err != nil {
+			t.Fatalf("Values could not be added: " + err.Error())
+		}
+
+		trashed, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Old"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if _, err := store.EntityTrash(trashed.ID()); err != nil {
+			t.Fatalf("Entity could not be trashed: " + err.Error())
+		}
+
+		types, err := store.EntityTypes()
+
+		if err != nil {
+			t.Fatalf("Entity types could not be listed: " + err.Error())
+		}
+
+		expectedTypes := []entitystore.EntityTypeCount{{EntityType: "post", Count: 1}, {EntityType: "user", Count: 3}}
+
+		if len(types) != 2 || types[0] != expectedTypes[0] || types[1] != expectedTypes[1] {
+			t.Fatal("Entity types incorrect", types)
+		}
+
+		keys, err := store.AttributeKeys("user")
+
+		if err != nil {
+			t.Fatalf("Attribute keys could not be listed: " + err.Error())
+		}
+
+		expectedKeys := []entitystore.AttributeKeyInfo{
+			{Key: "admin", Count: 2, ValueType: entitystore.ValueTypeBool},
+			{Key: "age", Count: 3, ValueType: entitystore.ValueTypeInt},
+			{Key: "name", Count: 3, ValueType: entitystore.ValueTypeString},
+			{Key: "prefs", Count: 2, ValueType: entitystore.ValueTypeJSON},
+			{Key: "score", Count: 2, ValueType: entitystore.ValueTypeFloat},
+		}
+
+		if len(keys) != len(expectedKeys) {
+			t.Fatal("Attribute keys incorrect", keys)
+		}
+
+		for i := range keys {
+			if keys[i] != expectedKeys[i] {
+				t.Fatal("Attribute key incorrect", "must be", expectedKeys[i], "found", keys[i])
+			}
+		}
+
+		postKeys, err := store.AttributeKeys("post")
+
+		if err != nil {
+			t.Fatalf("Attribute keys could not be listed: " + err.Error())
+		}
+
+		expectedPostKeys := []entitystore.AttributeKeyInfo{
+			{Key: "tags", Count: 1, ValueType: entitystore.ValueTypeString, List: true},
+		}
+
+		if len(postKeys) != len(expectedPostKeys) || postKeys[0] != expectedPostKeys[0] {
+			t.Fatal("Attribute keys incorrect", postKeys)
+		}
+
+		stats, err := store.Stats()
+
+		if err != nil {
+			t.Fatalf("Stats could not be computed: " + err.Error())
+		}
+
+		expectedStats := entitystore.StoreStats{
+			Entities:          4,
+			Attributes:        12,
+			AttributeValues:   2,
+			EntitiesTrashed:   1,
+			AttributesTrashed: 1,
+		}
+
+		// The change events are only recorded with the outbox enabled
+		stats.ChangeEvents = 0
+
+		if stats.Bytes.Entities < 0 || stats.Bytes.Attributes < 0 {
+			t.Fatal("Table sizes incorrect", stats.Bytes)
+		}
+
+		// The sizes depend on the database
+		stats.Bytes = entitystore.StoreTableSizes{}
+
+		if stats != expectedStats {
+			t.Fatal("Stats incorrect", "must be", expectedStats, "found", stats)
+		}
+	})
+
+	t.Run("ExportImport", func(t *testing.T) {
+		store := newStore(t)
+
+		first, err := store.EntityCreateWithHandle("post", "first", map[string]string{"title": "First", "views": "3"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if err := first.AppendToList("tags", "go", "db"); err != nil {
+			t.Fatalf("Values could not be appended: " + err.Error())
+		}
+
+		second, err := store.EntityCreateWithAttributes("post", map[string]string{"title": "Second"})
+
+		if err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		if _, err := store.EntityCreate("page"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		var exported bytes.Buffer
+		count, err := store.Export(&exported, entitystore.EntityQueryOptions{EntityType: "post"})
+
+		if err != nil {
+			t.Fatalf("Entities could not be exported: " + err.Error())
+		}
+
+		if count != 2 || strings.Count(exported.String(), "\n") != 2 {
+			t.Fatal("Exported entities incorrect", "must be 2", "found", count, exported.String())
+		}
+
+		for _, entity := range []*entitystore.Entity{first, second} {
+			if _, err := store.EntityDelete(entity.ID()); err != nil {
+				t.Fatalf("Entity could not be deleted: " + err.Error())
+			}
+		}
+
+		report, err := store.Import(bytes.NewReader(exported.Bytes()), entitystore.ImportOptions{PreserveIDs: true})
+
+		if err != nil {
+			t.Fatalf("Entities could not be imported: " + err.Error())
+		}
+
+		if report.Created != 2 || len(report.Errors) != 0 {
+			t.Fatal("Import report incorrect", report)
+		}
+
+		imported, err := store.EntityFindByHandle("post", "first")
+
+		if err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if imported == nil || imported.ID() != first.ID() || imported.CreatedAt().Unix() != first.CreatedAt().Unix() {
+			t.Fatal("Imported entity incorrect", imported)
+		}
+
+		if title, _ := imported.GetString("title", ""); title != "First" {
+			t.Fatal("Imported attribute incorrect", "must be First", "found", title)
+		}
+
+		if tags, _ := imported.GetList("tags"); strings.Join(tags, ",") != "go,db" {
+			t.Fatal("Imported list incorrect", "must be go,db", "found", tags)
+		}
+
+		report, err = store.Import(bytes.NewReader(exported.Bytes()), entitystore.ImportOptions{PreserveIDs: true})
+
+		if err != nil || report.Skipped != 2 {
+			t.Fatal("Existing entities must be skipped", report, err)
+		}
+
+		merge := `{"id":"` + first.ID() + `","entity_type":"post","attributes":{"views":"10"}}`
+		report, err = store.Import(strings.NewReader(merge), entitystore.ImportOptions{PreserveIDs: true, OnConflict: entitystore.ImportMerge})
+
+		if err != nil || report.Updated != 1 {
+			t.Fatal("Entity must be merged", report, err)
+		}
+
+		if title, _ := imported.GetString("title", ""); title != "First" {
+			t.Fatal("Merge must keep the other attributes", "found", title)
+		}
+
+		if views, _ := imported.GetInt("views", 0); views != 10 {
+			t.Fatal("Merged attribute incorrect", "must be 10", "found", views)
+		}
+
+		overwrite := `{"id":"` + first.ID() + `","entity_type":"post","entity_handle":"first","attributes":{"title":"New"}}`
+		report, err = store.Import(strings.NewReader(overwrite), entitystore.ImportOptions{PreserveIDs: true, OnConflict: entitystore.ImportOverwrite})
+
+		if err != nil || report.Updated != 1 {
+			t.Fatal("Entity must be overwritten", report, err)
+		}
+
+		if attrs, _ := imported.GetAttributes(); len(attrs) != 1 || attrs[0].AttributeValue() != "New" {
+			t.Fatal("Overwrite must replace the attributes", len(attrs))
+		}
+
+		if tags, _ := imported.GetList("tags"); len(tags) != 0 {
+			t.Fatal("Overwrite must replace the lists", "found", tags)
+		}
+
+		lines := strings.Join([]string{
+			`not json`,
+			`{"id":"x"}`,
+			`{"entity_type":"page","entity_handle":"` + strings.Repeat("a", 61) + `"}`,
+			``,
+			`{"entity_type":"page","entity_handle":"about","attributes":{"title":"About"}}`,
+			`{"id":"other","entity_type":"post","entity_handle":"first"}`,
+		}, "\n")
+
+		report, err = store.Import(strings.NewReader(lines), entitystore.ImportOptions{})
+
+		if err != nil {
+			t.Fatalf("Entities could not be imported: " + err.Error())
+		}
+
+		if report.Created != 1 || report.Skipped != 1 || len(report.Errors) != 3 {
+			t.Fatal("Import report incorrect", report)
+		}
+
+		for i, line := range []int{1, 2, 3} {
+			if report.Errors[i].Line != line {
+				t.Fatal("Error line incorrect", "must be", line, "found", report.Errors[i])
+			}
+		}
+
+		if about, _ := store.EntityFindByHandle("page", "about"); about == nil {
+			t.Fatalf("Entity must be imported with a new ID")
+		}
+
+		mismatch := `{"id":"` + first.ID() + `","entity_type":"article","attributes":{"views":"11"}}`
+		report, err = store.Import(strings.NewReader(mismatch), entitystore.ImportOptions{PreserveIDs: true, OnConflict: entitystore.ImportMerge})
+
+		if err != nil || report.Updated != 0 || len(report.Errors) != 1 {
+			t.Fatal("A record of another type must not be merged", report, err)
+		}
+
+		retype := `{"id":"` + first.ID() + `","entity_type":"article","entity_handle":"first"}`
+		report, err = store.Import(strings.NewReader(retype), entitystore.ImportOptions{PreserveIDs: true, OnConflict: entitystore.ImportOverwrite})
+
+		if err != nil || report.Updated != 1 {
+			t.Fatal("Entity must be overwritten", report, err)
+		}
+
+		if retyped, _ := store.EntityFindByHandle("article", "first"); retyped == nil || retyped.ID() != first.ID() || retyped.Type() != "article" {
+			t.Fatal("Overwrite must replace the type", retyped)
+		}
+
+		if articles, _ := store.EntityCount(entitystore.EntityQueryOptions{EntityType: "article"}); articles != 1 {
+			t.Fatal("Overwritten entity must be of the new type", "found", articles)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		store := newStore(t)
+
+		products := "sku,name,price\nA1,Apple,1.5\nB2,\"Banana, ripe\",0.5\n"
+		report, err := store.ImportCSV("product", strings.NewReader(products), entitystore.CSVImportOptions{KeyColumn: "sku"})
+
+		if err != nil {
+			t.Fatalf("CSV could not be imported: " + err.Error())
+		}
+
+		if report.Created != 2 || len(report.Errors) != 0 {
+			t.Fatal("CSV import report incorrect", report)
+		}
+
+		var exported bytes.Buffer
+		count, err := store.ExportCSV("product", []string{"name", "sku"}, &exported)
+
+		if err != nil {
+			t.Fatalf("CSV could not be exported: " + err.Error())
+		}
+
+		lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+
+		if count != 2 || len(lines) != 3 || lines[0] != "id,handle,name,sku" {
+			t.Fatal("CSV export incorrect", exported.String())
+		}
+
+		if !strings.Contains(exported.String(), `,"Banana, ripe",B2`) {
+			t.Fatal("CSV export must quote the values", exported.String())
+		}
+
+		changes := "sku,name,price\nA1,Apple,2\nC3,Cherry,3\nD4,Date\n"
+		report, err = store.ImportCSV("product", strings.NewReader(changes), entitystore.CSVImportOptions{KeyColumn: "sku", DryRun: true})
+
+		if err != nil {
+			t.Fatalf("CSV could not be imported: " + err.Error())
+		}
+
+		if report.Updated != 1 || report.Created != 1 || len(report.Errors) != 1 || report.Errors[0].Line != 4 {
+			t.Fatal("Dry run report incorrect", report)
+		}
+
+		if count, _ := store.EntityCount(entitystore.EntityQueryOptions{EntityType: "product"}); count != 2 {
+			t.Fatal("Dry run must not create entities", "found", count)
+		}
+
+		report, err = store.ImportCSV("product", strings.NewReader(changes), entitystore.CSVImportOptions{KeyColumn: "sku"})
+
+		if err != nil || report.Updated != 1 || report.Created != 1 {
+			t.Fatal("CSV import report incorrect", report, err)
+		}
+
+		apple, err := store.EntityFindByAttribute("product", "sku", "A1")
+
+		if err != nil || apple == nil {
+			t.Fatal("Entity must be found by key", err)
+		}
+
+		if price, _ := apple.GetFloat("price", 0); price != 2 {
+			t.Fatal("Updated attribute incorrect", "must be 2", "found", price)
+		}
+
+		pages := "slug,title,notes\nabout,About,x\n"
+		options := entitystore.CSVImportOptions{HandleColumn: "slug", Columns: map[string]string{"title": "name"}}
+
+		for _, outcome := range []string{"created", "updated"} {
+			report, err = store.ImportCSV("page", strings.NewReader(pages), options)
+
+			if err != nil || len(report.Errors) != 0 || (outcome == "created" && report.Created != 1) || (outcome == "updated" && report.Updated != 1) {
+				t.Fatal("CSV import by handle incorrect", outcome, report, err)
+			}
+		}
+
+		about, _ := store.EntityFindByHandle("page", "about")
+
+		if about == nil {
+			t.Fatalf("Entity must be created with the handle")
+		}
+
+		if attrs, _ := about.GetAttributes(); len(attrs) != 1 || attrs[0].AttributeKey() != "name" {
+			t.Fatal("Only the mapped columns must be imported", len(attrs))
+		}
+
+		if _, err := store.ImportCSV("page", strings.NewReader(pages), entitystore.CSVImportOptions{HandleColumn: "slug", KeyColumn: "title"}); err == nil {
+			t.Fatalf("Handle and key columns together must fail")
+		}
+	})
+
+	t.Run("Backup", func(t *testing.T) {
+		store := newStore(t)
+
+		if _, err := store.EntityCreateWithHandle("post", "hello", map[string]string{"title": "Hello"}); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		var backup bytes.Buffer
+		manifest, err := store.Backup(&backup)
+
+		if err != nil {
+			t.Fatalf("Store could not be backed up: " + err.Error())
+		}
+
+		if manifest.Format != entitystore.BackupFormat || manifest.Counts["entity"] != 1 || manifest.Counts["attribute"] != 1 || manifest.Counts["handle"] != 1 {
+			t.Fatal("Backup manifest incorrect", manifest)
+		}
+
+		if _, err := store.Restore(bytes.NewReader(backup.Bytes())); err == nil {
+			t.Fatalf("Restore into a store which is not empty must fail")
+		}
+
+		if _, err := store.Restore(strings.NewReader("{}")); err == nil {
+			t.Fatalf("Restore of a file which is not a backup must fail")
+		}
+	})
+
+	t.Run("EntityIterate", func(t *testing.T) {
+		store := newStore(t)
+
+		for i := 0; i < 25; i++ {
+			if _, err := store.EntityCreateWithAttributes("post", map[string]string{"index": strconv.Itoa(i)}); err != nil {
+				t.Fatalf("Entity could not be created: " + err.Error())
+			}
+		}
+
+		if _, err := store.EntityCreate("page"); err != nil {
+			t.Fatalf("Entity could not be created: " + err.Error())
+		}
+
+		ids := []string{}
+		err := store.EntityIterate(entitystore.EntityQueryOptions{EntityType: "post"}, func(entity entitystore.Entity, attributes []entitystore.Attribute) error {
+			if attributes != nil {
+				t.Fatal("Attributes must only be read when asked", entity.ID())
+			}
+
+			ids = append(ids, entity.ID())
+			return nil
+		}, entitystore.IterateOptions{ChunkSize: 10})
+
+		if err != nil {
+			t.Fatalf("Entities could not be iterated: " + err.Error())
+		}
+
+		if len(ids) != 25 || !sort.StringsAreSorted(ids) {
+			t.Fatal("Iterated entities incorrect", "must be 25 sorted by ID", "found", len(ids))
+		}
+
+		limited := []string{}
+		options := entitystore.EntityQueryOptions{EntityType: "post", Offset: 5, Limit: 12}
+
+		for item, err := range store.EntityCursor(options, entitystore.IterateOptions{ChunkSize: 5, WithAttributes: true}) {
+			if err != nil {
+				t.Fatalf("Entities could not be iterated: " + err.Error())
+			}
+
+			if index := item.Attribute("index"); index == nil || index.AttributeValue() == "" {
+				t.Fatal("Attributes must be read with the entity", item.Entity.ID())
+			}
+
+			limited = append(limited, item.Entity.ID())
+		}
+
+		if len(limited) != 12 || limited[0] != ids[5] || limited[11] != ids[16] {
+			t.Fatal("Limit and offset must apply to the iteration", len(limited))
+		}
+
+		visited := 0
+		for range store.EntityCursor(entitystore.EntityQueryOptions{}) {
+			visited++
+			if visited == 3 {
+				break
+			}
+		}
+
+		if visited != 3 {
+			t.Fatal("Iteration must stop", visited)
+		}
+
+		page, err := store.EntityList(entitystore.EntityQueryOptions{EntityType: "post", AfterID: ids[20]})
+
+		if err != nil || len(page) != 4 || page[0].ID() != ids[21] {
+			t.Fatal("Entities after the ID incorrect", len(page), err)
+		}
+
+		err = store.EntityIterate(entitystore.EntityQueryOptions{SortBy: "created_at"}, func(entity entitystore.Entity, attributes []entitystore.Attribute) error {
+			return nil
+		})
+
+		if err == nil {
+			t.Fatalf("Iteration sorted by another column must fail")
+		}
+	})
+}
+
+// facetsEqual returns whether the facet values are equal, in order
+func facetsEqual(a []entitystore.FacetValue, b []entitystore.FacetValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,6 +1,6 @@
 module github.com/gouniverse/entitystore
 
-go 1.19
+go 1.23
 
 require (
 	github.com/doug-martin/goqu/v9 v9.18.0
@@ -10,4 +10,15 @@ require (
 
 require github.com/georgysavva/scany v1.2.1
 
-require github.com/golang-module/carbon/v2 v2.2.3 // indirect
+require (
+	github.com/golang-module/carbon/v2 v2.2.3
+	github.com/microsoft/go-mssqldb v1.0.0
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/golang-sql/civil v0.0.0-20190719163853-cb61b32ac6fe // indirect
+	github.com/golang-sql/sqlexp v0.1.0 // indirect
+	golang.org/x/crypto v0.0.0-20220622213112-05595931fe9d // indirect
+)
@@ -0,0 +1,221 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// handleMaxLength is the length of the entity_handle column
+const handleMaxLength = 60
+
+// sqlCreateHandleTable returns the SQL creating the table of the entity
+// handles, current and past, unique per entity type. The handles of the
+// existing entities are copied, the oldest entity keeping a duplicate, by
+// creation time then ID
+func (st *Store) sqlCreateHandleTable() ([]string, error) {
+	sqlBackfill := ` (entity_type, entity_handle, entity_id, created_at)
+	SELECT e.entity_type, e.entity_handle, e.id, e.created_at FROM ` + st.entityTableName + ` e
+	WHERE e.entity_handle <> '' AND NOT EXISTS (
+		SELECT 1 FROM ` + st.entityTableName + ` o
+		WHERE o.entity_type = e.entity_type AND o.entity_handle = e.entity_handle
+		AND (o.created_at < e.created_at OR (o.created_at = e.created_at AND o.id < e.id))
+	);
+	`
+
+	sqlMysql := []string{`
+	CREATE TABLE IF NOT EXISTS ` + st.handleTableName + ` (
+		entity_type varchar(40) NOT NULL,
+		entity_handle varchar(60) NOT NULL,
+		entity_id varchar(40) NOT NULL,
+		created_at datetime NOT NULL,
+		PRIMARY KEY (entity_type, entity_handle)
+	);
+	`}
+	sqlMysql = append(sqlMysql, st.sqlMysqlCreateIndex(st.handleTableName, "entity_id", `CREATE INDEX `+st.indexName(st.handleTableName, "entity_id")+` ON `+st.handleTableName+` (entity_id);`)...)
+	sqlMysql = append(sqlMysql, `INSERT IGNORE INTO `+st.handleTableName+sqlBackfill)
+
+	sqlPostgres := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.handleTableName + `" (
+		"entity_type" varchar(40) NOT NULL,
+		"entity_handle" varchar(60) NOT NULL,
+		"entity_id" varchar(40) NOT NULL,
+		"created_at" timestamptz(6) NOT NULL,
+		PRIMARY KEY ("entity_type", "entity_handle")
+	);
+	`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.handleTableName, "entity_id") + ` ON ` + st.handleTableName + ` ("entity_id");`,
+		`INSERT INTO "` + st.handleTableName + `"` + sqlBackfill,
+	}
+
+	sqlSqlite := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.handleTableName + `" (
+		"entity_type" varchar(40) NOT NULL,
+		"entity_handle" varchar(60) NOT NULL,
+		"entity_id" varchar(40) NOT NULL,
+		"created_at" datetime NOT NULL,
+		PRIMARY KEY ("entity_type", "entity_handle")
+	);
+	`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.handleTableName, "entity_id") + `" ON "` + st.handleTableName + `" ("entity_id");`,
+		`INSERT INTO "` + st.handleTableName + `"` + sqlBackfill,
+	}
+
+	sqlMssql := []string{`
+	IF OBJECT_ID(N'` + st.handleTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.handleTableName + `] (
+		[entity_type] nvarchar(40) NOT NULL,
+		[entity_handle] nvarchar(60) NOT NULL,
+		[entity_id] nvarchar(40) NOT NULL,
+		[created_at] datetime2 NOT NULL,
+		PRIMARY KEY ([entity_type], [entity_handle])
+	);
+	`,
+		st.sqlMssqlCreateIndex(st.handleTableName, "entity_id", `CREATE INDEX [`+st.indexName(st.handleTableName, "entity_id")+`] ON [`+st.handleTableName+`] ([entity_id]);`),
+		`INSERT INTO [` + st.handleTableName + `]` + sqlBackfill,
+	}
+
+	if st.dbDriverName == "mysql" {
+		return sqlMysql, nil
+	} else if st.dbDriverName == "postgres" {
+		return sqlPostgres, nil
+	} else if st.dbDriverName == "sqlite" {
+		return sqlSqlite, nil
+	} else if st.dbDriverName == "mssql" {
+		return sqlMssql, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// entityHandleValidate validates a handle given to an entity
+func entityHandleValidate(entityHandle string) error {
+	if entityHandle == "" {
+		return errors.New("entity handle cannot be empty")
+	}
+
+	if len(entityHandle) > handleMaxLength {
+		return errors.New("entity handle cannot be longer than " + strconv.Itoa(handleMaxLength) + " characters")
+	}
+
+	return nil
+}
+
+// entityHandleFind returns the ID of the entity the handle belongs to,
+// currently or in the past, empty if none
+func (st *sqlStorage) entityHandleFind(db txOrDB, entityType string, entityHandle string) (string, error) {
+	sqlStr, params, errSql := st.dialect().From(st.handleTableName).Prepared(true).
+		Select("entity_id").
+		Where(goqu.C("entity_type").Eq(entityType), goqu.C("entity_handle").Eq(entityHandle)).
+		ToSQL()
+
+	if errSql != nil {
+		return "", errSql
+	}
+
+	entityIDs := []string{}
+
+	if err := st.sqlSelect("EntityFindByHandle", db, false, &entityIDs, sqlStr, params...); err != nil {
+		return "", err
+	}
+
+	if len(entityIDs) == 0 {
+		return "", nil
+	}
+
+	return entityIDs[0], nil
+}
+
+// entityHandleReserve records the handle of an entity, failing with a
+// *HandleTakenError when it belongs to another entity of the type. It is
+// called after the write of the entity, which on SQLite takes the write
+// lock before the handle is read
+func (st *sqlStorage) entityHandleReserve(tx *sql.Tx, entityType string, entityHandle string, entityID string) error {
+	owner, err := st.entityHandleFind(tx, entityType, entityHandle)
+
+	if err != nil {
+		return err
+	}
+
+	if owner == entityID {
+		return nil
+	}
+
+	if owner != "" {
+		return &HandleTakenError{EntityType: entityType, Handle: entityHandle, EntityID: owner}
+	}
+
+	sqlStr, params, errSql := st.dialect().Insert(st.handleTableName).Prepared(true).
+		Rows(goqu.Record{
+			"entity_type":   entityType,
+			"entity_handle": entityHandle,
+			"entity_id":     entityID,
+			"created_at":    time.Now(),
+		}).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err = st.sqlExec("EntityHandle", tx, false, sqlStr, params...)
+
+	return err
+}
+
+// entityHandleTaken converts the error of a write giving a handle to an
+// entity, when the handle was taken concurrently and the insert failed on
+// the primary key, into a *HandleTakenError
+func (st *Store) entityHandleTaken(err error, entityType string, entityHandle string, entityID string) error {
+	if err == nil || errors.Is(err, ErrHandleTaken) {
+		return err
+	}
+
+	owner, errFind := st.storage.entityHandleFind(st.db, entityType, entityHandle)
+
+	if errFind == nil && owner != "" && owner != entityID {
+		return &HandleTakenError{EntityType: entityType, Handle: entityHandle, EntityID: owner}
+	}
+
+	return err
+}
+
+// entityHandlesWithPrefix returns the handles of the entity type starting
+// with the prefix, which must not contain LIKE wildcards
+func (st *sqlStorage) entityHandlesWithPrefix(entityType string, prefix string) ([]string, error) {
+	sqlStr, params, errSql := st.dialect().From(st.handleTableName).Prepared(true).
+		Select("entity_handle").
+		Where(goqu.C("entity_type").Eq(entityType), goqu.C("entity_handle").Like(prefix+"%")).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	handles := []string{}
+
+	if err := st.sqlSelect("EntityHandleGenerate", st.db, false, &handles, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	return handles, nil
+}
+
+// entityHandlesDelete deletes the handles, current and past, of an entity
+func (st *Store) entityHandlesDelete(tx *sql.Tx, entityID string) error {
+	sqlStr, params, errSql := st.dialect().From(st.handleTableName).Prepared(true).
+		Where(goqu.C("entity_id").Eq(entityID)).
+		Delete().
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("EntityDelete", tx, false, sqlStr, params...)
+
+	return err
+}
@@ -0,0 +1,239 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/gouniverse/uid"
+)
+
+// Outcomes of an imported record
+const (
+	importCreated = "created"
+	importUpdated = "updated"
+	importSkipped = "skipped"
+)
+
+// importRecord creates the entity of a record, or applies the conflict
+// policy when it exists, returning the outcome and the change event
+// recorded, nil when the record is skipped
+func (st *Store) importRecord(tx *sql.Tx, record EntityRecord, options ImportOptions) (string, *ChangeEvent, error) {
+	if record.Handle != "" {
+		if err := entityHandleValidate(record.Handle); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if !options.PreserveIDs || record.ID == "" {
+		record.ID = uid.HumanUid()
+	}
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = time.Now()
+	}
+
+	outcome, entityID, err := st.storage.entityImport(tx, record, options)
+
+	if err != nil || outcome == importSkipped {
+		return outcome, nil, err
+	}
+
+	change := &ChangeEvent{
+		Operation:  ChangeCreate,
+		EntityID:   entityID,
+		EntityType: record.Type,
+		Attributes: map[string]string{},
+	}
+
+	if outcome == importUpdated {
+		change.Operation = ChangeUpdate
+	}
+
+	for k, v := range record.Attributes {
+		change.Attributes[k] = v
+	}
+
+	return outcome, change, st.changeRecord(tx, change)
+}
+
+// entityImport creates the entity of a prepared record, or applies the
+// conflict policy when it exists
+func (st *sqlStorage) entityImport(tx *sql.Tx, record EntityRecord, options ImportOptions) (string, string, error) {
+	existing, err := st.importExisting(tx, record, options)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if existing == nil {
+		entity := st.NewEntity(NewEntityOptions{
+			ID:        record.ID,
+			Type:      record.Type,
+			Handle:    record.Handle,
+			CreatedAt: record.CreatedAt,
+			UpdatedAt: record.UpdatedAt,
+			Version:   1,
+		})
+
+		if err := st.entityInsertWithTransactionOrDB(tx, *entity); err != nil {
+			return "", "", err
+		}
+
+		if record.Handle != "" {
+			if err := st.entityHandleReserve(tx, record.Type, record.Handle, record.ID); err != nil {
+				return "", "", err
+			}
+		}
+
+		return importCreated, record.ID, st.importAttributes(tx, record.ID, record, true)
+	}
+
+	if options.OnConflict == ImportSkip {
+		return importSkipped, existing.ID(), nil
+	}
+
+	overwrite := options.OnConflict == ImportOverwrite
+
+	if !overwrite && existing.Type() != record.Type {
+		return "", "", importTypeMismatch(*existing, record)
+	}
+
+	update := goqu.Record{
+		"updated_at": record.UpdatedAt,
+		"version":    entityVersionIncrement(),
+	}
+
+	if overwrite {
+		update["entity_type"] = record.Type
+		update["created_at"] = record.CreatedAt
+		update["entity_handle"] = record.Handle
+	} else if record.Handle != "" {
+		update["entity_handle"] = record.Handle
+	}
+
+	sqlStr, params, errSql := st.dialect().Update(st.entityTableName).Prepared(true).
+		Set(update).
+		Where(goqu.C("id").Eq(existing.ID())).
+		ToSQL()
+
+	if errSql != nil {
+		return "", "", errSql
+	}
+
+	if _, err := st.sqlExec("Import", tx, false, sqlStr, params...); err != nil {
+		return "", "", err
+	}
+
+	if record.Handle != "" {
+		if err := st.entityHandleReserve(tx, record.Type, record.Handle, existing.ID()); err != nil {
+			return "", "", err
+		}
+	}
+
+	return importUpdated, existing.ID(), st.importAttributes(tx, existing.ID(), record, overwrite)
+}
+
+// importTypeMismatch returns the error of a record merged into an
+// existing entity of another type
+func importTypeMismatch(existing Entity, record EntityRecord) error {
+	return errors.New("entity store: entity " + existing.ID() + " is of type " + existing.Type() + ", the record of type " + record.Type + " cannot be merged into it")
+}
+
+// importExisting returns the existing entity of a record, matched by ID
+// when the IDs are preserved, then by type and handle
+func (st *sqlStorage) importExisting(tx *sql.Tx, record EntityRecord, options ImportOptions) (*Entity, error) {
+	if options.PreserveIDs {
+		entity, err := st.entityFindWithTransaction(tx, record.ID)
+
+		if err != nil || entity != nil {
+			return entity, err
+		}
+	}
+
+	if record.Handle == "" {
+		return nil, nil
+	}
+
+	entityID, err := st.entityHandleFind(tx, record.Type, record.Handle)
+
+	if err != nil || entityID == "" {
+		return nil, err
+	}
+
+	return st.entityFindWithTransaction(tx, entityID)
+}
+
+// importAttributes writes the attributes and the lists of a record. With
+// replaceAll the other attributes and lists of the entity are deleted,
+// otherwise only those of the record are replaced
+func (st *sqlStorage) importAttributes(tx *sql.Tx, entityID string, record EntityRecord, replaceAll bool) error {
+	q := st.dialect().From(st.attributeTableName).Prepared(true).
+		Where(goqu.C("entity_id").Eq(entityID))
+
+	if !replaceAll {
+		q = q.Where(goqu.C("attribute_key").In(sortedKeys(record.Attributes)))
+	}
+
+	if replaceAll || len(record.Attributes) > 0 {
+		sqlStr, params, errSql := q.Delete().ToSQL()
+
+		if errSql != nil {
+			return errSql
+		}
+
+		if _, err := st.sqlExec("Import", tx, false, sqlStr, params...); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(record.Attributes) {
+		if _, err := st.attributeCreateWithTransactionOrDB(tx, entityID, key, record.Attributes[key]); err != nil {
+			return err
+		}
+	}
+
+	if replaceAll {
+		if err := st.attributeValuesDelete(tx, entityID); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(record.Lists) {
+		if err := st.attributeValuesReplace(tx, entityID, key, record.Lists[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// entityFindWithTransaction finds an entity by ID, reading in the
+// transaction
+func (st *Store) entityFindWithTransaction(db txOrDB, entityID string) (*Entity, error) {
+	sqlStr, params, errSql := st.dialect().From(st.entityTableName).Prepared(true).
+		Select("id", "entity_type", "entity_handle", "created_at", "updated_at", "version").
+		Where(goqu.C("id").Eq(entityID)).
+		ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	entityMaps := []map[string]string{}
+
+	if err := st.sqlSelect("EntityFindByID", db, false, &entityMaps, sqlStr, params...); err != nil {
+		return nil, err
+	}
+
+	if len(entityMaps) == 0 {
+		return nil, nil
+	}
+
+	return st.NewEntityFromMap(entityMaps[0]), nil
+}
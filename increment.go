@@ -0,0 +1,231 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// attributeIncrement adds the delta, an int64 or a float64, to the value
+// of an attribute in the database, so concurrent increments are not lost.
+// A missing attribute is created with the delta. Returns the new value
+func (st *Store) attributeIncrement(entityID string, attributeKey string, delta any) (string, error) {
+	if entityID == "" {
+		return "", errors.New("entity id cannot be empty")
+	}
+
+	if attributeKey == "" {
+		return "", errors.New("attribute key cannot be empty")
+	}
+
+	change := ChangeEvent{
+		Operation: ChangeAttributeSet,
+		EntityID:  entityID,
+	}
+
+	// The entity type is only looked up when needed, for the outbox and
+	// the subscriptions
+	if st.outboxEnabled || st.subscribed() {
+		entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
+
+		if err != nil {
+			return "", err
+		}
+
+		if entity != nil {
+			change.EntityType = entity.Type()
+		}
+	}
+
+	var value string
+	var err error
+
+	// Concurrent increments of a missing attribute all insert it. The ones
+	// failing on the unique index are retried, updating it instead
+	for attempt := 1; attempt <= 2; attempt++ {
+		inserted := false
+
+		err = st.withTransaction("AttributeIncrement", func(tx *sql.Tx) error {
+			var err error
+
+			value, inserted, err = st.storage.attributeIncrement(tx, entityID, attributeKey, delta)
+
+			if err != nil {
+				return err
+			}
+
+			// An inserted attribute must belong to an entity. Checked after
+			// the write, so a SQLite transaction does not upgrade its lock
+			if inserted {
+				exists, err := st.storage.entityExists(tx, entityID)
+
+				if err != nil {
+					return err
+				}
+
+				if !exists {
+					inserted = false
+					return errors.New("entity store: entity " + entityID + " not found")
+				}
+			}
+
+			if err := st.storage.entityVersionBump(tx, entityID, nil); err != nil {
+				return err
+			}
+
+			change.Attributes = map[string]string{attributeKey: value}
+
+			return st.changeRecord(tx, &change)
+		})
+
+		if err == nil || !inserted {
+			break
+		}
+	}
+
+	st.cacheInvalidate(entityID)
+
+	if err != nil {
+		return "", err
+	}
+
+	st.changePublish(change)
+
+	return value, nil
+}
+
+// attributeIncrement increments the value of the attribute in one
+// statement, or inserts the attribute when missing
+func (st *sqlStorage) attributeIncrement(tx *sql.Tx, entityID string, attributeKey string, delta any) (value string, inserted bool, err error) {
+	expression, err := st.attributeIncrementExpression(delta)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	sqlStr, params, errSql := st.dialect().Update(st.attributeTableName).Prepared(true).
+		Set(goqu.Record{
+			"attribute_value": goqu.L(expression, goqu.C("attribute_value"), delta),
+			"updated_at":      time.Now(),
+		}).
+		Where(goqu.C("entity_id").Eq(entityID), goqu.C("attribute_key").Eq(attributeKey)).
+		ToSQL()
+
+	if errSql != nil {
+		return "", false, errSql
+	}
+
+	result, err := st.sqlExec("AttributeIncrement", tx, false, sqlStr, params...)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	updated, err := result.RowsAffected()
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if updated == 0 {
+		value = attributeIncrementFormat(delta)
+
+		if _, err := st.attributeCreateWithTransactionOrDB(tx, entityID, attributeKey, value); err != nil {
+			return "", true, err
+		}
+
+		return value, true, nil
+	}
+
+	sqlStr, params, errSql = st.dialect().From(st.attributeTableName).Prepared(true).
+		Select("attribute_value").
+		Where(goqu.C("entity_id").Eq(entityID), goqu.C("attribute_key").Eq(attributeKey)).
+		ToSQL()
+
+	if errSql != nil {
+		return "", false, errSql
+	}
+
+	if err := st.sqlGet("AttributeIncrement", tx, false, &value, sqlStr, params...); err != nil {
+		return "", false, err
+	}
+
+	if _, isFloat := delta.(float64); !isFloat {
+		return value, false, nil
+	}
+
+	// Each driver formats a float its own way, i.e. SQLite as "12.0". The
+	// row is locked by the update, so the sum is stored again formatted
+	// as by AttributeSetFloat
+	sum, err := strconv.ParseFloat(value, 64)
+
+	if err != nil {
+		return "", false, err
+	}
+
+	if formatted := attributeIncrementFormat(sum); formatted != value {
+		sqlStr, params, errSql = st.dialect().Update(st.attributeTableName).Prepared(true).
+			Set(goqu.Record{"attribute_value": formatted}).
+			Where(goqu.C("entity_id").Eq(entityID), goqu.C("attribute_key").Eq(attributeKey)).
+			ToSQL()
+
+		if errSql != nil {
+			return "", false, errSql
+		}
+
+		if _, err := st.sqlExec("AttributeIncrement", tx, false, sqlStr, params...); err != nil {
+			return "", false, err
+		}
+
+		value = formatted
+	}
+
+	return value, false, nil
+}
+
+// attributeIncrementExpression returns the SQL adding a delta to a text
+// attribute value, with the value and the delta as placeholders. A value
+// which is not a number counts as zero on MySQL and SQLite, and fails the
+// increment on PostgreSQL and SQL Server. An int delta truncates a float
+// value, i.e. one set by AttributeSetFloat
+func (st *Store) attributeIncrementExpression(delta any) (string, error) {
+	_, isFloat := delta.(float64)
+
+	if st.dbDriverName == "mysql" {
+		if isFloat {
+			return "CAST((? + 0) + ? AS CHAR)", nil
+		}
+		return "CAST(CAST(? AS SIGNED) + ? AS CHAR)", nil
+	} else if st.dbDriverName == "postgres" {
+		if isFloat {
+			return "CAST(CAST(? AS DOUBLE PRECISION) + ? AS TEXT)", nil
+		}
+		return "CAST(TRUNC(CAST(? AS NUMERIC)) + ? AS TEXT)", nil
+	} else if st.dbDriverName == "sqlite" {
+		if isFloat {
+			return "CAST(CAST(? AS REAL) + ? AS TEXT)", nil
+		}
+		return "CAST(CAST(? AS INTEGER) + ? AS TEXT)", nil
+	} else if st.dbDriverName == "mssql" {
+		// Style 3 keeps all the digits of a float
+		if isFloat {
+			return "CONVERT(NVARCHAR(MAX), CAST(? AS FLOAT) + ?, 3)", nil
+		}
+		return "CAST(CAST(CAST(? AS DECIMAL(38, 10)) AS BIGINT) + ? AS NVARCHAR(MAX))", nil
+	}
+
+	return "", errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// attributeIncrementFormat formats an int64 or float64 as attribute
+// value, the same way as AttributeSetInt and AttributeSetFloat
+func attributeIncrementFormat(value any) string {
+	if f, isFloat := value.(float64); isFloat {
+		return strconv.FormatFloat(f, 'f', 30, 64)
+	}
+
+	return strconv.FormatInt(value.(int64), 10)
+}
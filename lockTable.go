@@ -0,0 +1,163 @@
+package entitystore
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/georgysavva/scany/sqlscan"
+	"github.com/golang-module/carbon/v2"
+)
+
+// sqlCreateLockTable returns the SQL creating the table of the entity locks
+func (st *Store) sqlCreateLockTable() ([]string, error) {
+	sqlMysql := `
+	CREATE TABLE IF NOT EXISTS ` + st.lockTableName + ` (
+		entity_id varchar(40) NOT NULL PRIMARY KEY,
+		owner varchar(100) NOT NULL,
+		token bigint NOT NULL,
+		expires_at datetime(6) NOT NULL
+	);
+	`
+
+	sqlPostgres := `
+	CREATE TABLE IF NOT EXISTS "` + st.lockTableName + `" (
+		"entity_id" varchar(40) NOT NULL PRIMARY KEY,
+		"owner" varchar(100) NOT NULL,
+		"token" bigint NOT NULL,
+		"expires_at" timestamptz(6) NOT NULL
+	);
+	`
+
+	sqlSqlite := `
+	CREATE TABLE IF NOT EXISTS "` + st.lockTableName + `" (
+		"entity_id" varchar(40) NOT NULL PRIMARY KEY,
+		"owner" varchar(100) NOT NULL,
+		"token" integer NOT NULL,
+		"expires_at" datetime NOT NULL
+	);
+	`
+
+	sqlMssql := `
+	IF OBJECT_ID(N'` + st.lockTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.lockTableName + `] (
+		[entity_id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[owner] nvarchar(100) NOT NULL,
+		[token] bigint NOT NULL,
+		[expires_at] datetime2 NOT NULL
+	);
+	`
+
+	if st.dbDriverName == "mysql" {
+		return []string{sqlMysql}, nil
+	} else if st.dbDriverName == "postgres" {
+		return []string{sqlPostgres}, nil
+	} else if st.dbDriverName == "sqlite" {
+		return []string{sqlSqlite}, nil
+	} else if st.dbDriverName == "mssql" {
+		return []string{sqlMssql}, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// entityLockValidate validates the arguments of the lock methods
+func entityLockValidate(entityID string, owner string, ttl time.Duration) error {
+	if entityID == "" {
+		return errors.New("entity ID cannot be empty")
+	}
+
+	if owner == "" {
+		return errors.New("lock owner cannot be empty")
+	}
+
+	if ttl <= 0 {
+		return errors.New("lock ttl must be positive")
+	}
+
+	return nil
+}
+
+// entityLeaseFind returns the lease of an entity, nil if it is not locked.
+// With forUpdate the row is locked until the end of the transaction, on
+// the databases supporting SELECT ... FOR UPDATE
+func (st *Store) entityLeaseFind(db txOrDB, entityID string, forUpdate bool) (*EntityLease, error) {
+	q := st.dialect().From(st.lockTableName).Prepared(true).
+		Select("entity_id", "owner", "token", "expires_at").
+		Where(goqu.C("entity_id").Eq(entityID))
+
+	if forUpdate && (st.dbDriverName == "mysql" || st.dbDriverName == "postgres") {
+		q = q.ForUpdate(exp.Wait)
+	}
+
+	sqlStr, params, errSql := q.ToSQL()
+
+	if errSql != nil {
+		return nil, errSql
+	}
+
+	leaseMap := map[string]string{}
+	err := st.sqlGet("EntityLock", db, false, &leaseMap, sqlStr, params...)
+
+	if sqlscan.NotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := strconv.ParseInt(leaseMap["token"], 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &EntityLease{
+		EntityID:  leaseMap["entity_id"],
+		Owner:     leaseMap["owner"],
+		Token:     token,
+		ExpiresAt: carbon.Parse(leaseMap["expires_at"], carbon.UTC).ToStdTime(),
+	}, nil
+}
+
+// entityLockWriteFirst takes the write lock of SQLite, which has no
+// SELECT ... FOR UPDATE, by a no-op write at the start of the transaction.
+// Reading first would fail when upgrading to the write lock, instead of
+// waiting for the other writers
+func (st *Store) entityLockWriteFirst(db txOrDB, entityID string) error {
+	if st.dbDriverName != "sqlite" {
+		return nil
+	}
+
+	sqlStr, params, errSql := st.dialect().Update(st.lockTableName).Prepared(true).
+		Set(goqu.Record{"token": goqu.C("token")}).
+		Where(goqu.C("entity_id").Eq(entityID)).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err := st.sqlExec("EntityLock", db, false, sqlStr, params...)
+
+	return err
+}
+
+// entityLockHeldByOther returns a LockedError if the entity is locked by
+// another owner, with an unexpired lease
+func (st *Store) entityLockHeldByOther(db txOrDB, entityID string, owner string) (*LockedError, error) {
+	lease, err := st.entityLeaseFind(db, entityID, false)
+
+	if err != nil || lease == nil {
+		return nil, err
+	}
+
+	if lease.Owner == owner || lease.Expired() {
+		return nil, nil
+	}
+
+	return &LockedError{EntityID: entityID, Owner: lease.Owner, ExpiresAt: lease.ExpiresAt}, nil
+}
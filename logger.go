@@ -0,0 +1,72 @@
+package entitystore
+
+import (
+	"context"
+	"log"
+	"log/slog"
+)
+
+// activeLogger returns the logger the store records are written to. Without
+// a Logger option the records are written to the standard logger while
+// debug is enabled, and discarded otherwise
+func (st *Store) activeLogger() *slog.Logger {
+	if st.logger != nil {
+		return st.logger
+	}
+
+	if st.debugEnabled {
+		return slog.New(slog.NewTextHandler(log.Writer(), &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	return nil
+}
+
+// logQuery records an executed statement, at debug level, or at error
+// level if it failed
+func (st *Store) logQuery(event *QueryEvent) {
+	logger := st.activeLogger()
+
+	if logger == nil {
+		return
+	}
+
+	ctx := context.Background()
+	level := slog.LevelDebug
+
+	if event.Error != nil {
+		level = slog.LevelError
+	}
+
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", event.Operation),
+		slog.String("sql", event.SQL),
+		slog.Any("args", event.Args),
+		slog.Duration("duration", event.Duration),
+		slog.Int64("rows", event.Rows),
+	}
+
+	if event.Error != nil {
+		attrs = append(attrs, slog.Any("error", event.Error))
+	}
+
+	logger.LogAttrs(ctx, level, "entitystore query", attrs...)
+}
+
+// logError records an error of the operation op which is not the result
+// of a statement, i.e. failing to build the SQL or to commit
+func (st *Store) logError(op string, err error) {
+	logger := st.activeLogger()
+
+	if logger == nil {
+		return
+	}
+
+	logger.LogAttrs(context.Background(), slog.LevelError, "entitystore error",
+		slog.String("operation", op),
+		slog.Any("error", err),
+	)
+}
@@ -0,0 +1,124 @@
+package entitystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStoreLogger(t *testing.T) {
+	db := InitDB("test_store_logger.db")
+
+	buffer := &bytes.Buffer{}
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		Logger:             slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	entity, err := store.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	if err := entity.SetString("title", "Hello"); err != nil {
+		t.Fatalf("Attribute could not be set: " + err.Error())
+	}
+
+	if _, err := store.AttributeCreate(entity.ID(), "title", "Again"); err == nil {
+		t.Fatalf("Duplicate attribute must not be created")
+	}
+
+	records := map[string][]map[string]any{}
+
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		record := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Record is not valid JSON: " + err.Error())
+		}
+		op, _ := record["operation"].(string)
+		records[op] = append(records[op], record)
+	}
+
+	if len(records["Migrate"]) == 0 {
+		t.Fatal("Migration statements must be logged")
+	}
+
+	created := records["EntityCreate"]
+
+	if len(created) != 1 {
+		t.Fatal("EntityCreate must be logged once, found", len(created))
+	}
+
+	if created[0]["level"] != "DEBUG" || created[0]["rows"] != float64(1) {
+		t.Fatal("EntityCreate record incorrect", created[0])
+	}
+
+	if !strings.HasPrefix(created[0]["sql"].(string), "INSERT INTO") {
+		t.Fatal("EntityCreate record must have the SQL, found", created[0]["sql"])
+	}
+
+	if _, exists := created[0]["duration"]; !exists {
+		t.Fatal("EntityCreate record must have the duration")
+	}
+
+	if args, _ := created[0]["args"].([]any); len(args) == 0 {
+		t.Fatal("EntityCreate record must have the args")
+	}
+
+	inserts := records["AttributeInsert"]
+	failed := inserts[len(inserts)-1]
+
+	if failed["level"] != "ERROR" || failed["error"] == nil {
+		t.Fatal("Failed AttributeInsert must be logged as error", failed)
+	}
+}
+
+func TestStoreLoggerDebugDisabled(t *testing.T) {
+	db := InitDB("test_store_logger_debug.db")
+
+	buffer := &bytes.Buffer{}
+	output := log.Writer()
+	log.SetOutput(buffer)
+	defer log.SetOutput(output)
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	if _, err := store.EntityDelete(""); err == nil {
+		t.Fatalf("Deleting an empty entity ID must fail")
+	}
+
+	if buffer.Len() != 0 {
+		t.Fatal("Nothing must be logged with debug disabled, found", buffer.String())
+	}
+
+	store.EnableDebug(true)
+
+	if _, err := store.EntityDelete(""); err == nil {
+		t.Fatalf("Deleting an empty entity ID must fail")
+	}
+
+	if !strings.Contains(buffer.String(), "operation=EntityDelete") {
+		t.Fatal("Errors must be logged with debug enabled, found", buffer.String())
+	}
+}
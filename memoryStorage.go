@@ -0,0 +1,1720 @@
+package entitystore
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gouniverse/uid"
+)
+
+// memoryStorage keeps the entities and attributes of a store in memory,
+// indexed by entity type and by entity ID and attribute key. It is safe
+// for concurrent use.
+//
+// The transactions run one at a time. The writes of a transaction are
+// recorded in a journal, replayed backwards to undo them when it fails.
+// The reads are not isolated, they see the writes of a running
+// transaction
+type memoryStorage struct {
+	st    *Store
+	mutex sync.RWMutex
+
+	transactionMutex sync.Mutex
+
+	// journal undoes the writes of the running transaction, nil when none
+	// is running
+	journal []func()
+
+	entities        map[string]Entity
+	entityTypeIndex map[string]map[string]struct{}
+	entityTrash     map[string]EntityTrash
+
+	attributes        map[string]Attribute
+	attributeKeyIndex map[string]map[string]string
+	attributeTrash    map[string]AttributeTrash
+
+	// attributeValueLists are the values of the list and set attributes, by
+	// entity ID and attribute key
+	attributeValueLists map[string]map[string][]string
+
+	// attributeValueListsTrash are the values of the list and set
+	// attributes of the trashed entities, by entity ID and attribute key
+	attributeValueListsTrash map[string]map[string][]string
+
+	// handles are the entity IDs by entity type and handle, current and
+	// past
+	handles map[string]map[string]string
+
+	outbox         []ChangeEvent
+	outboxSequence int64
+	outboxCursors  map[string]int64
+
+	// outboxCommitted is the ID of the last event committed, the events
+	// of the running transaction are not read
+	outboxCommitted int64
+
+	// locks and outboxCursors are not written in transactions, like the
+	// lock and cursor tables of the SQL storage
+	locks map[string]EntityLease
+}
+
+func newMemoryStorage(st *Store) *memoryStorage {
+	return &memoryStorage{
+		st:                       st,
+		entities:                 map[string]Entity{},
+		entityTypeIndex:          map[string]map[string]struct{}{},
+		entityTrash:              map[string]EntityTrash{},
+		attributes:               map[string]Attribute{},
+		attributeKeyIndex:        map[string]map[string]string{},
+		attributeTrash:           map[string]AttributeTrash{},
+		attributeValueLists:      map[string]map[string][]string{},
+		attributeValueListsTrash: map[string]map[string][]string{},
+		handles:                  map[string]map[string]string{},
+		outboxCursors:            map[string]int64{},
+		locks:                    map[string]EntityLease{},
+	}
+}
+
+// transaction runs fn with the other transactions waiting, undoing the
+// writes of fn when it fails or panics. fn is given a nil transaction
+func (m *memoryStorage) transaction(op string, fn func(tx *sql.Tx) error) error {
+	m.transactionMutex.Lock()
+	defer m.transactionMutex.Unlock()
+
+	m.mutex.Lock()
+	m.journal = []func(){}
+	m.mutex.Unlock()
+
+	committed := false
+
+	defer func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		if !committed {
+			for i := len(m.journal) - 1; i >= 0; i-- {
+				m.journal[i]()
+			}
+		} else {
+			m.outboxCommitted = m.outboxSequence
+		}
+
+		m.journal = nil
+	}()
+
+	if err := fn(nil); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// snapshot runs fn while no transaction runs. The writes made outside
+// of a transaction are not held off
+func (m *memoryStorage) snapshot(op string, fn func(db txOrDB) error) error {
+	m.transactionMutex.Lock()
+	defer m.transactionMutex.Unlock()
+
+	return fn(nil)
+}
+
+// migrate does nothing, the memory storage has no schema
+func (m *memoryStorage) migrate() error {
+	return nil
+}
+
+// migrationStatus returns no migrations, the memory storage has no schema
+func (m *memoryStorage) migrationStatus() ([]Migration, error) {
+	return []Migration{}, nil
+}
+
+// repairDuplicateAttributes deletes nothing, the memory storage never
+// stores duplicates
+func (m *memoryStorage) repairDuplicateAttributes() (int64, error) {
+	return 0, nil
+}
+
+// entityInsert stores a new entity with its attributes
+func (m *memoryStorage) entityInsert(tx *sql.Tx, entity Entity, attributes map[string]string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.entities[entity.ID()]; exists {
+		return errors.New("entity with ID " + entity.ID() + " already exists")
+	}
+
+	if err := m.entityHandleReserve(entity); err != nil {
+		return err
+	}
+
+	m.entityPut(entity)
+
+	for k, v := range attributes {
+		attr := m.st.NewAttribute(NewAttributeOptions{
+			ID:             uid.HumanUid(),
+			EntityID:       entity.ID(),
+			AttributeKey:   k,
+			AttributeValue: v,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		})
+
+		if err := m.attributePut(*attr, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryStorage) entityList(options EntityQueryOptions, useCache bool) ([]Entity, error) {
+	sortByColumn := "id"
+
+	if options.SortBy != "" {
+		sortByColumn = options.SortBy
+	}
+
+	if !entitySortableColumns[sortByColumn] {
+		return nil, errors.New("entity sort column not supported: " + sortByColumn)
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	list := []Entity{}
+
+	for _, entity := range m.entityCandidates(options) {
+		if options.ID != "" && entity.ID() != options.ID {
+			continue
+		}
+		if len(options.IDs) > 0 && !memoryContains(options.IDs, entity.ID()) {
+			continue
+		}
+		if options.EntityType != "" && entity.Type() != options.EntityType {
+			continue
+		}
+		if options.EntityHandle != "" && entity.Handle() != options.EntityHandle {
+			continue
+		}
+		if !options.UpdatedSince.IsZero() && entity.UpdatedAt().Before(options.UpdatedSince) {
+			continue
+		}
+		if options.AfterID != "" && entity.ID() <= options.AfterID {
+			continue
+		}
+		if !m.attributeValuesMatch(entity.ID(), options) {
+			continue
+		}
+		list = append(list, entity)
+	}
+
+	if options.CountOnly {
+		return list, nil
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return memoryLess(list[i].ToMap()[sortByColumn], list[j].ToMap()[sortByColumn], options.SortOrder)
+	})
+
+	return memoryPage(list, options.Offset, options.Limit), nil
+}
+
+// entityCandidates narrows the entities to scan using the type index
+func (m *memoryStorage) entityCandidates(options EntityQueryOptions) []Entity {
+	candidates := []Entity{}
+
+	if options.ID != "" {
+		if entity, exists := m.entities[options.ID]; exists {
+			candidates = append(candidates, entity)
+		}
+		return candidates
+	}
+
+	if options.EntityType != "" {
+		for id := range m.entityTypeIndex[options.EntityType] {
+			candidates = append(candidates, m.entities[id])
+		}
+		return candidates
+	}
+
+	for _, entity := range m.entities {
+		candidates = append(candidates, entity)
+	}
+
+	return candidates
+}
+
+func (m *memoryStorage) entityCount(options EntityQueryOptions) (int64, error) {
+	options.CountOnly = true
+
+	list, err := m.entityList(options, false)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(list)), nil
+}
+
+// entityExists returns whether the entity exists
+func (m *memoryStorage) entityExists(db txOrDB, entityID string) (bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	_, exists := m.entities[entityID]
+
+	return exists, nil
+}
+
+// entityUpdate replaces an entity, incrementing its version. With
+// ifVersion the entity must be at that version
+func (m *memoryStorage) entityUpdate(tx *sql.Tx, ent Entity, ifVersion *int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	existing, exists := m.entities[ent.ID()]
+
+	if !exists {
+		if ifVersion != nil {
+			return errors.New("entity store: entity " + ent.ID() + " not found")
+		}
+		return nil
+	}
+
+	if ifVersion != nil && existing.Version() != *ifVersion {
+		return &VersionConflictError{
+			EntityID:        ent.ID(),
+			ExpectedVersion: *ifVersion,
+			ActualVersion:   existing.Version(),
+		}
+	}
+
+	if err := m.entityHandleReserve(ent); err != nil {
+		return err
+	}
+
+	ent.SetVersion(existing.Version() + 1)
+
+	m.entityRemove(ent.ID())
+	m.entityPut(ent)
+
+	return nil
+}
+
+// entityVersionBump increments the version of an entity and sets its
+// updated_at. With ifVersion the entity must be at that version
+func (m *memoryStorage) entityVersionBump(db txOrDB, entityID string, ifVersion *int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entity, exists := m.entities[entityID]
+
+	if !exists {
+		if ifVersion != nil {
+			return errors.New("entity store: entity " + entityID + " not found")
+		}
+		return nil
+	}
+
+	if ifVersion != nil && entity.Version() != *ifVersion {
+		return &VersionConflictError{
+			EntityID:        entityID,
+			ExpectedVersion: *ifVersion,
+			ActualVersion:   entity.Version(),
+		}
+	}
+
+	entity.SetVersion(entity.Version() + 1)
+	entity.SetUpdatedAt(time.Now())
+	memorySet(m, m.entities, entityID, entity)
+
+	return nil
+}
+
+func (m *memoryStorage) entityDelete(tx *sql.Tx, entityID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, attributeID := range m.attributeKeyIndex[entityID] {
+		memoryDelete(m, m.attributes, attributeID)
+	}
+
+	memoryDelete(m, m.attributeKeyIndex, entityID)
+	memoryDelete(m, m.attributeValueLists, entityID)
+
+	for _, handles := range m.handles {
+		for handle, id := range handles {
+			if id == entityID {
+				memoryDelete(m, handles, handle)
+			}
+		}
+	}
+
+	m.entityRemove(entityID)
+
+	return nil
+}
+
+func (m *memoryStorage) entityTrashMove(tx *sql.Tx, ent Entity) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entityID := ent.ID()
+	entity, exists := m.entities[entityID]
+
+	if !exists {
+		return false, nil
+	}
+
+	memorySet(m, m.entityTrash, entityID, EntityTrash{
+		ID:        entity.ID(),
+		Type:      entity.Type(),
+		Handle:    entity.Handle(),
+		CreatedAt: entity.CreatedAt(),
+		UpdatedAt: entity.UpdatedAt(),
+		Version:   entity.Version(),
+		DeletedAt: time.Now(),
+	})
+
+	for _, attributeID := range m.attributeKeyIndex[entityID] {
+		attr := m.attributes[attributeID]
+
+		memorySet(m, m.attributeTrash, attributeID, AttributeTrash{
+			ID:             attr.ID(),
+			EntityID:       attr.EntityID(),
+			AttributeKey:   attr.AttributeKey(),
+			AttributeValue: attr.AttributeValue(),
+			CreatedAt:      attr.CreatedAt(),
+			UpdatedAt:      attr.UpdatedAt(),
+			DeletedAt:      time.Now(),
+		})
+
+		memoryDelete(m, m.attributes, attributeID)
+	}
+
+	memoryDelete(m, m.attributeKeyIndex, entityID)
+
+	if lists, exists := m.attributeValueLists[entityID]; exists {
+		memorySet(m, m.attributeValueListsTrash, entityID, lists)
+		memoryDelete(m, m.attributeValueLists, entityID)
+	}
+
+	m.entityRemove(entityID)
+
+	return true, nil
+}
+
+// entityTrashFind returns a trashed entity with its attributes, sorted by
+// key, nil if the entity is not in the trash bin
+func (m *memoryStorage) entityTrashFind(entityID string) (*Entity, []Attribute, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	entTrash, exists := m.entityTrash[entityID]
+
+	if !exists {
+		return nil, nil, nil
+	}
+
+	entity := m.st.NewEntity(NewEntityOptions{
+		ID:        entTrash.ID,
+		Type:      entTrash.Type,
+		Handle:    entTrash.Handle,
+		CreatedAt: entTrash.CreatedAt,
+		UpdatedAt: entTrash.UpdatedAt,
+		Version:   entTrash.Version,
+	})
+
+	attrs := []Attribute{}
+
+	for _, attrTrash := range m.attributeTrash {
+		if attrTrash.EntityID != entityID {
+			continue
+		}
+
+		attrs = append(attrs, *m.st.NewAttribute(NewAttributeOptions{
+			ID:             attrTrash.ID,
+			EntityID:       attrTrash.EntityID,
+			AttributeKey:   attrTrash.AttributeKey,
+			AttributeValue: attrTrash.AttributeValue,
+			CreatedAt:      attrTrash.CreatedAt,
+			UpdatedAt:      attrTrash.UpdatedAt,
+		}))
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].AttributeKey() < attrs[j].AttributeKey()
+	})
+
+	return entity, attrs, nil
+}
+
+// entityRestore moves a trashed entity and its attributes back
+func (m *memoryStorage) entityRestore(tx *sql.Tx, entity Entity, attrs []Attribute) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.entities[entity.ID()]; exists {
+		return errors.New("entity with ID " + entity.ID() + " already exists")
+	}
+
+	m.entityPut(entity)
+
+	for _, attr := range attrs {
+		if err := m.attributePut(attr, true); err != nil {
+			return err
+		}
+	}
+
+	for attributeID, attrTrash := range m.attributeTrash {
+		if attrTrash.EntityID == entity.ID() {
+			memoryDelete(m, m.attributeTrash, attributeID)
+		}
+	}
+
+	if lists, exists := m.attributeValueListsTrash[entity.ID()]; exists {
+		memorySet(m, m.attributeValueLists, entity.ID(), lists)
+		memoryDelete(m, m.attributeValueListsTrash, entity.ID())
+	}
+
+	memoryDelete(m, m.entityTrash, entity.ID())
+
+	return nil
+}
+
+// entityIDsByAttribute returns the IDs of the entities of a type having
+// the attribute value, sorted by ID
+func (m *memoryStorage) entityIDsByAttribute(entityType string, attributeKey string, attributeValue string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	entityIDs := []string{}
+
+	for id := range m.entityTypeIndex[entityType] {
+		attributeID, exists := m.attributeKeyIndex[id][attributeKey]
+
+		if !exists {
+			continue
+		}
+
+		if attr := m.attributes[attributeID]; attr.AttributeValue() == attributeValue {
+			entityIDs = append(entityIDs, id)
+		}
+	}
+
+	sort.Strings(entityIDs)
+
+	return entityIDs, nil
+}
+
+func (m *memoryStorage) attributeInsert(db txOrDB, attr Attribute) (*Attribute, error) {
+	if attr.AttributeKey() == "" {
+		return nil, errors.New("attribute key is required field")
+	}
+	if attr.ID() == "" {
+		attr.SetID(uid.HumanUid())
+	}
+	if attr.CreatedAt().IsZero() {
+		attr.SetCreatedAt(time.Now())
+	}
+	if attr.UpdatedAt().IsZero() {
+		attr.SetUpdatedAt(time.Now())
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.attributePut(attr, true); err != nil {
+		return nil, err
+	}
+
+	return &attr, nil
+}
+
+func (m *memoryStorage) attributeList(options AttributeQueryOptions, useCache bool) ([]Attribute, error) {
+	sortByColumn := "id"
+
+	if options.SortBy != "" {
+		sortByColumn = options.SortBy
+	}
+
+	if !attributeSortableColumns[sortByColumn] {
+		return nil, errors.New("attribute sort column not supported: " + sortByColumn)
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	candidates := []Attribute{}
+
+	if options.EntityID != "" {
+		for _, attributeID := range m.attributeKeyIndex[options.EntityID] {
+			candidates = append(candidates, m.attributes[attributeID])
+		}
+	} else if len(options.EntityIDs) > 0 {
+		for _, entityID := range options.EntityIDs {
+			for _, attributeID := range m.attributeKeyIndex[entityID] {
+				candidates = append(candidates, m.attributes[attributeID])
+			}
+		}
+	} else {
+		for _, attr := range m.attributes {
+			candidates = append(candidates, attr)
+		}
+	}
+
+	list := []Attribute{}
+
+	for _, attr := range candidates {
+		if options.ID != "" && attr.ID() != options.ID {
+			continue
+		}
+		if len(options.IDs) > 0 && !memoryContains(options.IDs, attr.ID()) {
+			continue
+		}
+		if options.AttributeKey != "" && attr.AttributeKey() != options.AttributeKey {
+			continue
+		}
+		list = append(list, attr)
+	}
+
+	if options.CountOnly {
+		return list, nil
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return memoryLess(list[i].ToMap()[sortByColumn], list[j].ToMap()[sortByColumn], options.SortOrder)
+	})
+
+	return memoryPage(list, options.Offset, options.Limit), nil
+}
+
+func (m *memoryStorage) attributeUpdate(db txOrDB, attr Attribute) error {
+	attr.SetUpdatedAt(time.Now())
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	existing, exists := m.attributes[attr.ID()]
+
+	if !exists {
+		return nil
+	}
+
+	if existing.EntityID() != attr.EntityID() || existing.AttributeKey() != attr.AttributeKey() {
+		if _, taken := m.attributeKeyIndex[attr.EntityID()][attr.AttributeKey()]; taken {
+			return errors.New("attribute " + attr.AttributeKey() + " already exists for entity " + attr.EntityID())
+		}
+		memoryDelete(m, m.attributeKeyIndex[existing.EntityID()], existing.AttributeKey())
+	}
+
+	return m.attributePut(attr, false)
+}
+
+// attributeIncrement adds the delta, an int64 or a float64, to the value
+// of an attribute, creating it when missing. Returns the new value and
+// whether the attribute was created
+func (m *memoryStorage) attributeIncrement(tx *sql.Tx, entityID string, attributeKey string, delta any) (string, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	attributeID, exists := m.attributeKeyIndex[entityID][attributeKey]
+
+	if !exists {
+		value := attributeIncrementFormat(delta)
+
+		attr := m.st.NewAttribute(NewAttributeOptions{
+			ID:             uid.HumanUid(),
+			EntityID:       entityID,
+			AttributeKey:   attributeKey,
+			AttributeValue: value,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		})
+
+		return value, true, m.attributePut(*attr, true)
+	}
+
+	attr := m.attributes[attributeID]
+
+	var value string
+
+	if f, isFloat := delta.(float64); isFloat {
+		current, err := attr.GetFloat()
+		if err != nil {
+			return "", false, err
+		}
+		value = attributeIncrementFormat(current + f)
+	} else {
+		current, err := attr.GetInt()
+		if err != nil {
+			// A float value is truncated, as by the SQL drivers
+			f, errFloat := attr.GetFloat()
+			if errFloat != nil {
+				return "", false, err
+			}
+			current = int64(f)
+		}
+		value = attributeIncrementFormat(current + delta.(int64))
+	}
+
+	attr.SetString(value)
+	attr.SetUpdatedAt(time.Now())
+	memorySet(m, m.attributes, attributeID, attr)
+
+	return value, false, nil
+}
+
+// attributeValues returns a copy of the values of a list or set attribute
+func (m *memoryStorage) attributeValues(db txOrDB, entityID string, attributeKey string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return append([]string{}, m.attributeValueLists[entityID][attributeKey]...), nil
+}
+
+// attributeValuesChange applies a change to the values of a list or set
+// attribute, returning the new values and whether they changed
+func (m *memoryStorage) attributeValuesChange(tx *sql.Tx, entityID string, attributeKey string, change func(values []string) ([]string, bool)) ([]string, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	values, changed := change(append([]string{}, m.attributeValueLists[entityID][attributeKey]...))
+
+	if !changed {
+		return values, false, nil
+	}
+
+	m.attributeValuesPut(entityID, attributeKey, values)
+
+	return values, true, nil
+}
+
+// attributeValuesPut replaces the values of a list or set attribute,
+// deleting it when there are none. The caller holds the lock
+func (m *memoryStorage) attributeValuesPut(entityID string, attributeKey string, values []string) {
+	if len(values) == 0 {
+		memoryDelete(m, m.attributeValueLists[entityID], attributeKey)
+		return
+	}
+
+	if _, exists := m.attributeValueLists[entityID]; !exists {
+		memorySet(m, m.attributeValueLists, entityID, map[string][]string{})
+	}
+
+	memorySet(m, m.attributeValueLists[entityID], attributeKey, append([]string{}, values...))
+}
+
+// entityHandleReserve records the handle of an entity, unless empty,
+// failing when it belongs to another entity of the type. The caller holds
+// the lock
+func (m *memoryStorage) entityHandleReserve(entity Entity) error {
+	if entity.Handle() == "" {
+		return nil
+	}
+
+	owner, exists := m.handles[entity.Type()][entity.Handle()]
+
+	if exists && owner != entity.ID() {
+		return &HandleTakenError{EntityType: entity.Type(), Handle: entity.Handle(), EntityID: owner}
+	}
+
+	if _, exists := m.handles[entity.Type()]; !exists {
+		memorySet(m, m.handles, entity.Type(), map[string]string{})
+	}
+
+	memorySet(m, m.handles[entity.Type()], entity.Handle(), entity.ID())
+
+	return nil
+}
+
+// entityHandleFind returns the ID of the entity the handle belongs to,
+// currently or in the past, empty if none
+func (m *memoryStorage) entityHandleFind(db txOrDB, entityType string, entityHandle string) (string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.handles[entityType][entityHandle], nil
+}
+
+// entityHandlesWithPrefix returns the handles of the entity type starting
+// with the prefix
+func (m *memoryStorage) entityHandlesWithPrefix(entityType string, prefix string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	handles := []string{}
+	for handle := range m.handles[entityType] {
+		if strings.HasPrefix(handle, prefix) {
+			handles = append(handles, handle)
+		}
+	}
+
+	return handles, nil
+}
+
+// attributeFilterMatch returns whether an entity has all the attribute
+// values of the filter. The caller holds the lock
+func (m *memoryStorage) attributeFilterMatch(entityID string, filter map[string]string) bool {
+	for key, value := range filter {
+		if attributeValue, exists := m.attributeValueFind(entityID, key); !exists || attributeValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// attributeValueFind returns the value of an attribute of an entity. The
+// caller holds the lock
+func (m *memoryStorage) attributeValueFind(entityID string, attributeKey string) (string, bool) {
+	attributeID, exists := m.attributeKeyIndex[entityID][attributeKey]
+
+	if !exists {
+		return "", false
+	}
+
+	attr := m.attributes[attributeID]
+
+	return attr.AttributeValue(), true
+}
+
+// aggregate computes an aggregate function of an attribute across the
+// entities of a type, like the SQL of Aggregate. The values which are
+// not numbers are skipped
+func (m *memoryStorage) aggregate(entityType string, options AggregateOptions) ([]AggregateResult, error) {
+	type accumulator struct {
+		count    int64
+		numbers  int64
+		sum      float64
+		min, max float64
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	groups := map[string]*accumulator{}
+
+	if options.GroupBy == "" {
+		groups[""] = &accumulator{}
+	}
+
+	for entityID := range m.entityTypeIndex[entityType] {
+		if !m.attributeFilterMatch(entityID, options.Filter) {
+			continue
+		}
+
+		value, exists := "", true
+
+		if options.Key != "" {
+			value, exists = m.attributeValueFind(entityID, options.Key)
+		}
+
+		if !exists {
+			continue
+		}
+
+		group, _ := m.attributeValueFind(entityID, options.GroupBy)
+
+		acc, exists := groups[group]
+
+		if !exists {
+			acc = &accumulator{}
+			groups[group] = acc
+		}
+
+		acc.count++
+
+		if options.Key == "" || !attributeNumberRegexp.MatchString(value) {
+			continue
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			continue
+		}
+
+		if acc.numbers == 0 || number < acc.min {
+			acc.min = number
+		}
+
+		if acc.numbers == 0 || number > acc.max {
+			acc.max = number
+		}
+
+		acc.numbers++
+		acc.sum += number
+	}
+
+	results := []AggregateResult{}
+
+	for _, group := range sortedKeys(groups) {
+		acc := groups[group]
+		result := AggregateResult{Group: group}
+
+		switch options.Func {
+		case AggregateSum:
+			result.Value = acc.sum
+		case AggregateAvg:
+			if acc.numbers > 0 {
+				result.Value = acc.sum / float64(acc.numbers)
+			}
+		case AggregateMin:
+			result.Value = acc.min
+		case AggregateMax:
+			result.Value = acc.max
+		case AggregateCount:
+			result.Value = float64(acc.count)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// attributeValueCounts counts the values of an attribute of the entities
+// of a type having the attribute values of the filter, ordered by count
+func (m *memoryStorage) attributeValueCounts(op string, entityType string, attributeKey string, filter map[string]string, limit int) ([]FacetValue, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	counts := map[string]int64{}
+
+	for entityID := range m.entityTypeIndex[entityType] {
+		if !m.attributeFilterMatch(entityID, filter) {
+			continue
+		}
+
+		if value, exists := m.attributeValueFind(entityID, attributeKey); exists {
+			counts[value]++
+		}
+	}
+
+	values := make([]FacetValue, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, FacetValue{Value: value, Count: count})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+
+	return values, nil
+}
+
+// entityTypes returns the entity types with the number of their entities,
+// ordered by type
+func (m *memoryStorage) entityTypes() ([]EntityTypeCount, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	types := []EntityTypeCount{}
+	for _, entityType := range sortedKeys(m.entityTypeIndex) {
+		if count := len(m.entityTypeIndex[entityType]); count > 0 {
+			types = append(types, EntityTypeCount{EntityType: entityType, Count: int64(count)})
+		}
+	}
+
+	return types, nil
+}
+
+// attributeKeys returns the attribute keys of the entities of a type,
+// inferring the value types from up to sampleSize values
+func (m *memoryStorage) attributeKeys(entityType string, sampleSize int) ([]AttributeKeyInfo, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	samples := map[string][]string{}
+	counts := map[string]int64{}
+
+	for entityID := range m.entityTypeIndex[entityType] {
+		for key, attributeID := range m.attributeKeyIndex[entityID] {
+			counts[key]++
+
+			if len(samples[key]) < sampleSize {
+				attr := m.attributes[attributeID]
+				samples[key] = append(samples[key], attr.AttributeValue())
+			}
+		}
+	}
+
+	listSamples := map[string][]string{}
+	listCounts := map[string]int64{}
+
+	for entityID := range m.entityTypeIndex[entityType] {
+		for key, values := range m.attributeValueLists[entityID] {
+			if len(values) == 0 {
+				continue
+			}
+
+			listCounts[key]++
+
+			for _, value := range values {
+				if len(listSamples[key]) < sampleSize {
+					listSamples[key] = append(listSamples[key], value)
+				}
+			}
+		}
+	}
+
+	keys := []AttributeKeyInfo{}
+	for _, key := range sortedKeys(counts) {
+		keys = append(keys, AttributeKeyInfo{
+			Key:       key,
+			Count:     counts[key],
+			ValueType: attributeValuesType(samples[key]),
+		})
+	}
+
+	for _, key := range sortedKeys(listCounts) {
+		keys = append(keys, AttributeKeyInfo{
+			Key:       key,
+			Count:     listCounts[key],
+			ValueType: attributeValuesType(listSamples[key]),
+			List:      true,
+		})
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+
+	return keys, nil
+}
+
+// stats returns the numbers of entities, attributes, values, trashed
+// entities, attributes and values, and change events. The sizes are left zero
+func (m *memoryStorage) stats() (StoreStats, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	stats := StoreStats{
+		Entities:          int64(len(m.entities)),
+		Attributes:        int64(len(m.attributes)),
+		EntitiesTrashed:   int64(len(m.entityTrash)),
+		AttributesTrashed: int64(len(m.attributeTrash)),
+		ChangeEvents:      int64(len(m.outbox)),
+	}
+
+	for _, lists := range m.attributeValueLists {
+		for _, values := range lists {
+			stats.AttributeValues += int64(len(values))
+		}
+	}
+
+	for _, lists := range m.attributeValueListsTrash {
+		for _, values := range lists {
+			stats.AttributeValuesTrashed += int64(len(values))
+		}
+	}
+
+	return stats, nil
+}
+
+// attributeValuesOfEntities returns a copy of the values of the list and
+// set attributes of the entities, by entity ID and attribute key
+func (m *memoryStorage) attributeValuesOfEntities(entityIDs []string) (map[string]map[string][]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	lists := map[string]map[string][]string{}
+
+	for _, entityID := range entityIDs {
+		for key, values := range m.attributeValueLists[entityID] {
+			if _, exists := lists[entityID]; !exists {
+				lists[entityID] = map[string][]string{}
+			}
+
+			lists[entityID][key] = append([]string{}, values...)
+		}
+	}
+
+	return lists, nil
+}
+
+// entityImport creates the entity of an imported record, or applies the
+// conflict policy when it exists, like importRecord
+func (m *memoryStorage) entityImport(tx *sql.Tx, record EntityRecord, options ImportOptions) (string, string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	existing, exists := Entity{}, false
+
+	if options.PreserveIDs {
+		existing, exists = m.entities[record.ID]
+	}
+
+	if !exists && record.Handle != "" {
+		if entityID, found := m.handles[record.Type][record.Handle]; found {
+			existing, exists = m.entities[entityID]
+		}
+	}
+
+	if !exists {
+		entity := m.st.NewEntity(NewEntityOptions{
+			ID:        record.ID,
+			Type:      record.Type,
+			Handle:    record.Handle,
+			CreatedAt: record.CreatedAt,
+			UpdatedAt: record.UpdatedAt,
+			Version:   1,
+		})
+
+		if err := m.entityHandleReserve(*entity); err != nil {
+			return "", "", err
+		}
+
+		m.entityPut(*entity)
+
+		return importCreated, record.ID, m.importAttributes(record.ID, record, true)
+	}
+
+	if options.OnConflict == ImportSkip {
+		return importSkipped, existing.ID(), nil
+	}
+
+	overwrite := options.OnConflict == ImportOverwrite
+
+	if !overwrite && existing.Type() != record.Type {
+		return "", "", importTypeMismatch(existing, record)
+	}
+
+	if overwrite {
+		existing.SetType(record.Type)
+		existing.SetCreatedAt(record.CreatedAt)
+		existing.SetHandle(record.Handle)
+	} else if record.Handle != "" {
+		existing.SetHandle(record.Handle)
+	}
+
+	existing.SetUpdatedAt(record.UpdatedAt)
+	existing.SetVersion(existing.Version() + 1)
+
+	if err := m.entityHandleReserve(existing); err != nil {
+		return "", "", err
+	}
+
+	m.entityRemove(existing.ID())
+	m.entityPut(existing)
+
+	return importUpdated, existing.ID(), m.importAttributes(existing.ID(), record, overwrite)
+}
+
+// importAttributes writes the attributes and the lists of an imported
+// record, like the importAttributes of the store. The caller holds the
+// lock
+func (m *memoryStorage) importAttributes(entityID string, record EntityRecord, replaceAll bool) error {
+	for key, attributeID := range m.attributeKeyIndex[entityID] {
+		if _, imported := record.Attributes[key]; replaceAll || imported {
+			memoryDelete(m, m.attributes, attributeID)
+			memoryDelete(m, m.attributeKeyIndex[entityID], key)
+		}
+	}
+
+	for key, value := range record.Attributes {
+		attr := m.st.NewAttribute(NewAttributeOptions{
+			ID:             uid.HumanUid(),
+			EntityID:       entityID,
+			AttributeKey:   key,
+			AttributeValue: value,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		})
+
+		if err := m.attributePut(*attr, true); err != nil {
+			return err
+		}
+	}
+
+	if replaceAll {
+		memoryDelete(m, m.attributeValueLists, entityID)
+	}
+
+	for key, values := range record.Lists {
+		m.attributeValuesPut(entityID, key, values)
+	}
+
+	return nil
+}
+
+// backupRows returns a page of the rows of a table of a backup following
+// the row with the key after, sorted by the key of the table. The values
+// of the lists, and the handles, are given the time of the backup as
+// creation time
+func (m *memoryStorage) backupRows(db txOrDB, table backupTable, after map[string]any) ([]map[string]any, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	rows := []map[string]any{}
+
+	switch table.name {
+	case "entity":
+		for _, entity := range m.entities {
+			rows = append(rows, map[string]any{
+				"id":            entity.ID(),
+				"entity_type":   entity.Type(),
+				"entity_handle": entity.Handle(),
+				"created_at":    entity.CreatedAt().UTC(),
+				"updated_at":    entity.UpdatedAt().UTC(),
+				"version":       entity.Version(),
+			})
+		}
+	case "attribute":
+		for _, attr := range m.attributes {
+			rows = append(rows, map[string]any{
+				"id":              attr.ID(),
+				"entity_id":       attr.EntityID(),
+				"attribute_key":   attr.AttributeKey(),
+				"attribute_value": attr.AttributeValue(),
+				"created_at":      attr.CreatedAt().UTC(),
+				"updated_at":      attr.UpdatedAt().UTC(),
+			})
+		}
+	case "entity_trash":
+		for _, entTrash := range m.entityTrash {
+			rows = append(rows, map[string]any{
+				"id":            entTrash.ID,
+				"entity_type":   entTrash.Type,
+				"entity_handle": entTrash.Handle,
+				"created_at":    entTrash.CreatedAt.UTC(),
+				"updated_at":    entTrash.UpdatedAt.UTC(),
+				"version":       entTrash.Version,
+				"deleted_at":    entTrash.DeletedAt.UTC(),
+				"deleted_by":    entTrash.DeletedBy,
+			})
+		}
+	case "attribute_trash":
+		for _, attrTrash := range m.attributeTrash {
+			rows = append(rows, map[string]any{
+				"id":              attrTrash.ID,
+				"entity_id":       attrTrash.EntityID,
+				"attribute_key":   attrTrash.AttributeKey,
+				"attribute_value": attrTrash.AttributeValue,
+				"created_at":      attrTrash.CreatedAt.UTC(),
+				"updated_at":      attrTrash.UpdatedAt.UTC(),
+				"deleted_at":      attrTrash.DeletedAt.UTC(),
+				"deleted_by":      attrTrash.DeletedBy,
+			})
+		}
+	case "attribute_value":
+		for entityID, lists := range m.attributeValueLists {
+			for key, values := range lists {
+				for position, value := range values {
+					rows = append(rows, map[string]any{
+						"entity_id":       entityID,
+						"attribute_key":   key,
+						"attribute_value": value,
+						"position":        int64(position),
+						"created_at":      time.Now().UTC(),
+					})
+				}
+			}
+		}
+	case "attribute_value_trash":
+		for entityID, lists := range m.attributeValueListsTrash {
+			for key, values := range lists {
+				for position, value := range values {
+					rows = append(rows, map[string]any{
+						"entity_id":       entityID,
+						"attribute_key":   key,
+						"attribute_value": value,
+						"position":        int64(position),
+						"created_at":      time.Now().UTC(),
+						"deleted_at":      m.entityTrash[entityID].DeletedAt.UTC(),
+						"deleted_by":      m.entityTrash[entityID].DeletedBy,
+					})
+				}
+			}
+		}
+	case "handle":
+		for entityType, handles := range m.handles {
+			for handle, entityID := range handles {
+				rows = append(rows, map[string]any{
+					"entity_type":   entityType,
+					"entity_handle": handle,
+					"entity_id":     entityID,
+					"created_at":    time.Now().UTC(),
+				})
+			}
+		}
+	}
+
+	// The values of the lists have no ID, they are sorted by position and
+	// given an ID once paged
+	isValues := table.name == "attribute_value" || table.name == "attribute_value_trash"
+
+	if isValues && after != nil {
+		after = map[string]any{"entity_id": after["entity_id"], "attribute_key": after["attribute_key"], "position": after["position"]}
+	}
+
+	backupRowsSort(table, rows)
+
+	if after != nil {
+		first := sort.Search(len(rows), func(i int) bool {
+			return backupRowsCompare(table, rows[i], after) > 0
+		})
+		rows = rows[first:]
+	}
+
+	rows = memoryPage(rows, 0, backupPageSize)
+
+	if isValues {
+		for _, row := range rows {
+			row["id"] = uid.HumanUid()
+		}
+	}
+
+	return rows, nil
+}
+
+// backupRowsInsert stores the rows of a table read from a backup, like
+// backupRowsInsert of the store. The rows of the values of a list are
+// given in the order of their positions
+func (m *memoryStorage) backupRowsInsert(tx *sql.Tx, table backupTable, rows []map[string]any) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, row := range rows {
+		text := func(column string) string { return row[column].(string) }
+		moment := func(column string) time.Time { return row[column].(time.Time) }
+
+		switch table.name {
+		case "entity":
+			if _, exists := m.entities[text("id")]; exists {
+				return errors.New("entity with ID " + text("id") + " already exists")
+			}
+
+			m.entityPut(*m.st.NewEntity(NewEntityOptions{
+				ID:        text("id"),
+				Type:      text("entity_type"),
+				Handle:    text("entity_handle"),
+				CreatedAt: moment("created_at"),
+				UpdatedAt: moment("updated_at"),
+				Version:   row["version"].(int64),
+			}))
+		case "attribute":
+			attr := m.st.NewAttribute(NewAttributeOptions{
+				ID:             text("id"),
+				EntityID:       text("entity_id"),
+				AttributeKey:   text("attribute_key"),
+				AttributeValue: text("attribute_value"),
+				CreatedAt:      moment("created_at"),
+				UpdatedAt:      moment("updated_at"),
+			})
+
+			if err := m.attributePut(*attr, true); err != nil {
+				return err
+			}
+		case "entity_trash":
+			memorySet(m, m.entityTrash, text("id"), EntityTrash{
+				ID:        text("id"),
+				Type:      text("entity_type"),
+				Handle:    text("entity_handle"),
+				CreatedAt: moment("created_at"),
+				UpdatedAt: moment("updated_at"),
+				Version:   row["version"].(int64),
+				DeletedAt: moment("deleted_at"),
+				DeletedBy: text("deleted_by"),
+			})
+		case "attribute_trash":
+			memorySet(m, m.attributeTrash, text("id"), AttributeTrash{
+				ID:             text("id"),
+				EntityID:       text("entity_id"),
+				AttributeKey:   text("attribute_key"),
+				AttributeValue: text("attribute_value"),
+				CreatedAt:      moment("created_at"),
+				UpdatedAt:      moment("updated_at"),
+				DeletedAt:      moment("deleted_at"),
+				DeletedBy:      text("deleted_by"),
+			})
+		case "attribute_value":
+			values := m.attributeValueLists[text("entity_id")][text("attribute_key")]
+			m.attributeValuesPut(text("entity_id"), text("attribute_key"), append(values, text("attribute_value")))
+		case "attribute_value_trash":
+			if _, exists := m.attributeValueListsTrash[text("entity_id")]; !exists {
+				memorySet(m, m.attributeValueListsTrash, text("entity_id"), map[string][]string{})
+			}
+
+			lists := m.attributeValueListsTrash[text("entity_id")]
+			memorySet(m, lists, text("attribute_key"), append(append([]string{}, lists[text("attribute_key")]...), text("attribute_value")))
+		case "handle":
+			if _, exists := m.handles[text("entity_type")]; !exists {
+				memorySet(m, m.handles, text("entity_type"), map[string]string{})
+			}
+
+			memorySet(m, m.handles[text("entity_type")], text("entity_handle"), text("entity_id"))
+		}
+	}
+
+	return nil
+}
+
+// attributeValuesMatch returns whether the list and set attributes of an
+// entity match the ListContains and ListContainsAny filters. The caller
+// holds the lock
+func (m *memoryStorage) attributeValuesMatch(entityID string, options EntityQueryOptions) bool {
+	for key, value := range options.ListContains {
+		if !memoryContains(m.attributeValueLists[entityID][key], value) {
+			return false
+		}
+	}
+
+	for key, values := range options.ListContainsAny {
+		found := false
+		for _, value := range values {
+			if memoryContains(m.attributeValueLists[entityID][key], value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// attributesSet upserts the attributes of an entity in one step
+func (m *memoryStorage) attributesSet(tx *sql.Tx, entityID string, attributes map[string]string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for k, v := range attributes {
+		if attributeID, exists := m.attributeKeyIndex[entityID][k]; exists {
+			attr := m.attributes[attributeID]
+			attr.SetString(v)
+			attr.SetUpdatedAt(time.Now())
+			memorySet(m, m.attributes, attributeID, attr)
+			continue
+		}
+
+		attr := m.st.NewAttribute(NewAttributeOptions{
+			ID:             uid.HumanUid(),
+			EntityID:       entityID,
+			AttributeKey:   k,
+			AttributeValue: v,
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		})
+
+		if err := m.attributePut(*attr, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// entityPut stores an entity and indexes it. The caller holds the lock
+func (m *memoryStorage) entityPut(entity Entity) {
+	memorySet(m, m.entities, entity.ID(), entity)
+
+	if _, exists := m.entityTypeIndex[entity.Type()]; !exists {
+		memorySet(m, m.entityTypeIndex, entity.Type(), map[string]struct{}{})
+	}
+
+	memorySet(m, m.entityTypeIndex[entity.Type()], entity.ID(), struct{}{})
+}
+
+// entityRemove removes an entity and its index entries. The caller holds
+// the lock
+func (m *memoryStorage) entityRemove(entityID string) {
+	entity, exists := m.entities[entityID]
+
+	if !exists {
+		return
+	}
+
+	memoryDelete(m, m.entityTypeIndex[entity.Type()], entityID)
+	memoryDelete(m, m.entities, entityID)
+}
+
+// attributePut stores an attribute and indexes it, failing like the
+// unique index of the SQL backend when isNew is set and the entity
+// already has the attribute key. The caller holds the lock
+func (m *memoryStorage) attributePut(attr Attribute, isNew bool) error {
+	if _, exists := m.attributeKeyIndex[attr.EntityID()]; !exists {
+		memorySet(m, m.attributeKeyIndex, attr.EntityID(), map[string]string{})
+	}
+
+	if isNew {
+		if _, exists := m.attributeKeyIndex[attr.EntityID()][attr.AttributeKey()]; exists {
+			return errors.New("attribute " + attr.AttributeKey() + " already exists for entity " + attr.EntityID())
+		}
+		if _, exists := m.attributes[attr.ID()]; exists {
+			return errors.New("attribute with ID " + attr.ID() + " already exists")
+		}
+	}
+
+	memorySet(m, m.attributes, attr.ID(), attr)
+	memorySet(m, m.attributeKeyIndex[attr.EntityID()], attr.AttributeKey(), attr.ID())
+
+	return nil
+}
+
+// outboxAppend appends a change event to the outbox, assigning its ID. A
+// failing transaction removes it, its ID is not reused
+func (m *memoryStorage) outboxAppend(tx *sql.Tx, change ChangeEvent) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.outboxSequence++
+	change.ID = m.outboxSequence
+	m.outbox = append(m.outbox, change)
+
+	if m.journal != nil {
+		m.journal = append(m.journal, func() {
+			kept := []ChangeEvent{}
+			for _, event := range m.outbox {
+				if event.ID != change.ID {
+					kept = append(kept, event)
+				}
+			}
+			m.outbox = kept
+		})
+	}
+
+	return nil
+}
+
+// outboxList returns up to limit events after the position
+func (m *memoryStorage) outboxList(position int64, limit uint64) ([]ChangeEvent, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	events := []ChangeEvent{}
+
+	for _, change := range m.outbox {
+		if change.ID <= position {
+			continue
+		}
+		if change.ID > m.outboxCommitted {
+			break
+		}
+		events = append(events, change)
+		if uint64(len(events)) == limit {
+			break
+		}
+	}
+
+	return events, nil
+}
+
+func (m *memoryStorage) outboxCursorCreate(consumer string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.outboxCursors[consumer]; !exists {
+		m.outboxCursors[consumer] = 0
+	}
+
+	return nil
+}
+
+func (m *memoryStorage) outboxCursor(consumer string) (int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.outboxCursors[consumer], nil
+}
+
+func (m *memoryStorage) outboxAck(consumer string, eventID int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.outboxCursors[consumer] < eventID {
+		m.outboxCursors[consumer] = eventID
+	}
+
+	return nil
+}
+
+// outboxPrune deletes the events acknowledged by all consumers
+func (m *memoryStorage) outboxPrune() (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.outboxCursors) == 0 {
+		return 0, nil
+	}
+
+	position := int64(-1)
+	for _, cursor := range m.outboxCursors {
+		if position < 0 || cursor < position {
+			position = cursor
+		}
+	}
+
+	kept := []ChangeEvent{}
+	for _, change := range m.outbox {
+		if change.ID > position {
+			kept = append(kept, change)
+		}
+	}
+
+	deleted := int64(len(m.outbox) - len(kept))
+	m.outbox = kept
+
+	return deleted, nil
+}
+
+// memoryLess compares two column values of the same type in the given
+// sort order, descending unless "asc" like the SQL backend
+func memoryLess(a any, b any, sortOrder string) bool {
+	if sortOrder != "" && sortOrder != "asc" {
+		a, b = b, a
+	}
+
+	switch aValue := a.(type) {
+	case time.Time:
+		return aValue.Before(b.(time.Time))
+	case string:
+		return aValue < b.(string)
+	}
+
+	return false
+}
+
+// memoryPage returns the page of the list starting at offset, with at most
+// limit items unless limit is 0
+func memoryPage[T any](list []T, offset uint64, limit uint64) []T {
+	if offset >= uint64(len(list)) {
+		return []T{}
+	}
+
+	list = list[offset:]
+
+	if limit > 0 && limit < uint64(len(list)) {
+		list = list[:limit]
+	}
+
+	return list
+}
+
+// entityLock claims an entity for the owner of the lease, unless locked by
+// another owner with an unexpired lease
+func (m *memoryStorage) entityLock(lease EntityLease) (*EntityLease, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current, exists := m.locks[lease.EntityID]
+
+	if exists && current.Owner != lease.Owner && !current.Expired() {
+		return nil, &LockedError{EntityID: current.EntityID, Owner: current.Owner, ExpiresAt: current.ExpiresAt}
+	}
+
+	lease.Token = current.Token + 1
+	m.locks[lease.EntityID] = lease
+
+	return &lease, nil
+}
+
+// entityLockRenew extends the lease of the owner
+func (m *memoryStorage) entityLockRenew(entityID string, owner string, expiresAt time.Time) (*EntityLease, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current, exists := m.locks[entityID]
+
+	if !exists {
+		return nil, ErrLockNotHeld
+	}
+
+	if current.Expired() {
+		return nil, ErrLockNotHeld
+	}
+
+	if current.Owner != owner {
+		return nil, &LockedError{EntityID: current.EntityID, Owner: current.Owner, ExpiresAt: current.ExpiresAt}
+	}
+
+	current.ExpiresAt = expiresAt
+	m.locks[entityID] = current
+
+	return &current, nil
+}
+
+// entityUnlock releases the lock of the owner
+func (m *memoryStorage) entityUnlock(entityID string, owner string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current, exists := m.locks[entityID]
+
+	if !exists {
+		return nil
+	}
+
+	if current.Owner != owner {
+		if current.Expired() {
+			return nil
+		}
+		return &LockedError{EntityID: current.EntityID, Owner: current.Owner, ExpiresAt: current.ExpiresAt}
+	}
+
+	delete(m.locks, entityID)
+
+	return nil
+}
+
+// memorySet sets the value of a key of a map of the storage, recording in
+// the journal how to undo it. The caller holds the lock
+func memorySet[K comparable, V any](m *memoryStorage, values map[K]V, key K, value V) {
+	memoryJournal(m, values, key)
+	values[key] = value
+}
+
+// memoryDelete deletes a key of a map of the storage, recording in the
+// journal how to undo it. The caller holds the lock
+func memoryDelete[K comparable, V any](m *memoryStorage, values map[K]V, key K) {
+	if _, exists := values[key]; !exists {
+		return
+	}
+
+	memoryJournal(m, values, key)
+	delete(values, key)
+}
+
+// memoryJournal records how to restore the current value of a key of a
+// map, while a transaction is running
+func memoryJournal[K comparable, V any](m *memoryStorage, values map[K]V, key K) {
+	if m.journal == nil {
+		return
+	}
+
+	previous, existed := values[key]
+
+	m.journal = append(m.journal, func() {
+		if existed {
+			values[key] = previous
+		} else {
+			delete(values, key)
+		}
+	})
+}
+
+func memoryContains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
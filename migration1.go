@@ -0,0 +1,265 @@
+package entitystore
+
+import "errors"
+
+// sqlMigration1 returns the SQL of migration 1, creating the entity,
+// attribute and trash tables. It is a copy of the schema created by
+// SqlCreateTable when the migration was released, and must not change
+// with it
+func (st *Store) sqlMigration1() ([]string, error) {
+
+	sqlMysql1 := `
+	CREATE TABLE IF NOT EXISTS ` + st.entityTableName + ` (
+		id varchar(40) NOT NULL PRIMARY KEY,
+		entity_type varchar(40) NOT NULL,
+		entity_handle varchar(60) DEFAULT '',
+		created_at datetime NOT NULL,
+		updated_at datetime NOT NULL
+	 );
+	`
+
+	sqlMysql2 := `
+	CREATE TABLE IF NOT EXISTS ` + st.attributeTableName + ` (
+		id varchar(40) NOT NULL PRIMARY KEY,
+		entity_id varchar(40) NOT NULL,
+		attribute_key varchar(255) NOT NULL,
+		attribute_value text,
+		created_at datetime NOT NULL,
+		updated_at datetime NOT NULL
+	);
+	`
+
+	sqlMysql3 := `
+	CREATE TABLE IF NOT EXISTS ` + st.entityTrashTableName + ` (
+		id varchar(40) NOT NULL PRIMARY KEY,
+		entity_type varchar(40) NOT NULL,
+		entity_handle varchar(60) DEFAULT '',
+		created_at datetime NOT NULL,
+		updated_at datetime NOT NULL,
+		deleted_at datetime NOT NULL,
+		deleted_by varchar(40)
+	);
+	`
+
+	sqlMysql4 := `
+	CREATE TABLE IF NOT EXISTS ` + st.attributeTrashTableName + ` (
+		id varchar(40) NOT NULL PRIMARY KEY,
+		entity_id varchar(40) NOT NULL,
+		attribute_key varchar(255) NOT NULL,
+		attribute_value text,
+		created_at datetime NOT NULL,
+		updated_at datetime NOT NULL,
+		deleted_at datetime NOT NULL,
+		deleted_by varchar(40)
+	);
+	`
+
+	sqlPostgres1 := `
+	CREATE TABLE IF NOT EXISTS ` + st.attributeTableName + ` (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"created_at" timestamptz(6) NOT NULL,
+		"updated_at" timestamptz(6) NOT NULL
+	);
+	`
+
+	sqlPostgres2 := `
+	CREATE TABLE IF NOT EXISTS ` + st.entityTableName + ` (
+	   "id" varchar(40) NOT NULL PRIMARY KEY,
+	   "entity_type" varchar(40) NOT NULL,
+	   "entity_handle" varchar(60) DEFAULT '',
+	   "created_at" timestamptz(6),
+	   "updated_at" timestamptz(6)
+	);
+	`
+
+	sqlPostgres3 := `
+	CREATE TABLE IF NOT EXISTS ` + st.entityTrashTableName + ` (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_type" varchar(40) NOT NULL,
+		"entity_handle" varchar(60) DEFAULT '',
+		"created_at" timestamptz(6) NOT NULL,
+		"updated_at" timestamptz(6) NOT NULL,
+		"deleted_at" timestamptz(6) NOT NULL,
+		"deleted_by" varchar(40)
+	);
+	`
+
+	sqlPostgres4 := `
+	CREATE TABLE IF NOT EXISTS ` + st.attributeTrashTableName + ` (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"created_at" timestamptz(6) NOT NULL,
+		"updated_at" timestamptz(6) NOT NULL,
+		"deleted_at" timestamptz(6) NOT NULL,
+		"deleted_by" varchar(40)
+	);
+	`
+
+	sqlSqlite1 := `
+	CREATE TABLE IF NOT EXISTS "` + st.attributeTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"created_at" datetime NOT NULL,
+		"updated_at" datetime NOT NULL
+	);
+	`
+	sqlSqlite2 := `
+	CREATE TABLE IF NOT EXISTS "` + st.entityTableName + `" (
+	   "id" varchar(40) NOT NULL PRIMARY KEY,
+	   "entity_type" varchar(40) NOT NULL,
+	   "entity_handle" varchar(60) DEFAULT '',
+	   "created_at" datetime NOT NULL,
+	   "updated_at" datetime NOT NULL
+	);
+	`
+
+	sqlSqlite3 := `
+	CREATE TABLE IF NOT EXISTS "` + st.entityTrashTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_type" varchar(40) NOT NULL,
+		"entity_handle" varchar(60) DEFAULT '',
+		"created_at" datetime NOT NULL,
+		"updated_at" datetime NOT NULL,
+		"deleted_at" datetime NOT NULL,
+		"deleted_by" varchar(40)
+	);
+	`
+
+	sqlSqlite4 := `
+	CREATE TABLE IF NOT EXISTS "` + st.attributeTrashTableName + `" (
+		"id" varchar(40) NOT NULL PRIMARY KEY,
+		"entity_id" varchar(40) NOT NULL,
+		"attribute_key" varchar(255) NOT NULL,
+		"attribute_value" text,
+		"created_at" datetime NOT NULL,
+		"updated_at" datetime NOT NULL,
+		"deleted_at" datetime NOT NULL,
+		"deleted_by" varchar(40)
+	);
+	`
+
+	// Indexes. The unique index on (entity_id, attribute_key) guarantees
+	// one value per attribute key. MySQL does not support IF NOT EXISTS
+	// on CREATE INDEX, hence the guard, and cannot index a full text
+	// column, hence the prefix on attribute_value. PostgreSQL btree
+	// entries are limited in size, so attribute_value is indexed by prefix
+	// there as well.
+	sqlMysqlIndexes := []string{}
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.attributeTableName, "entity_id_attribute_key", `CREATE UNIQUE INDEX `+st.indexName(st.attributeTableName, "entity_id_attribute_key")+` ON `+st.attributeTableName+` (entity_id, attribute_key);`)...)
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.attributeTableName, "attribute_key_value", `CREATE INDEX `+st.indexName(st.attributeTableName, "attribute_key_value")+` ON `+st.attributeTableName+` (attribute_key, attribute_value(100));`)...)
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.entityTableName, "entity_type", `CREATE INDEX `+st.indexName(st.entityTableName, "entity_type")+` ON `+st.entityTableName+` (entity_type);`)...)
+	sqlMysqlIndexes = append(sqlMysqlIndexes, st.sqlMysqlCreateIndex(st.entityTableName, "entity_handle", `CREATE INDEX `+st.indexName(st.entityTableName, "entity_handle")+` ON `+st.entityTableName+` (entity_handle);`)...)
+
+	sqlPostgresIndexes := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS ` + st.indexName(st.attributeTableName, "entity_id_attribute_key") + ` ON ` + st.attributeTableName + ` ("entity_id", "attribute_key");`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.attributeTableName, "attribute_key_value") + ` ON ` + st.attributeTableName + ` ("attribute_key", left("attribute_value", 100));`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.entityTableName, "entity_type") + ` ON ` + st.entityTableName + ` ("entity_type");`,
+		`CREATE INDEX IF NOT EXISTS ` + st.indexName(st.entityTableName, "entity_handle") + ` ON ` + st.entityTableName + ` ("entity_handle");`,
+	}
+
+	sqlSqliteIndexes := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS "` + st.indexName(st.attributeTableName, "entity_id_attribute_key") + `" ON "` + st.attributeTableName + `" ("entity_id", "attribute_key");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.attributeTableName, "attribute_key_value") + `" ON "` + st.attributeTableName + `" ("attribute_key", "attribute_value");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.entityTableName, "entity_type") + `" ON "` + st.entityTableName + `" ("entity_type");`,
+		`CREATE INDEX IF NOT EXISTS "` + st.indexName(st.entityTableName, "entity_handle") + `" ON "` + st.entityTableName + `" ("entity_handle");`,
+	}
+
+	// SQL Server has no CREATE TABLE IF NOT EXISTS, existence is checked
+	// with OBJECT_ID. A text column cannot be an index key there, so
+	// attribute_value is included in the attribute key index instead
+	sqlMssql1 := `
+	IF OBJECT_ID(N'` + st.attributeTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.attributeTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_id] nvarchar(40) NOT NULL,
+		[attribute_key] nvarchar(255) NOT NULL,
+		[attribute_value] nvarchar(max),
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL
+	);
+	`
+
+	sqlMssql2 := `
+	IF OBJECT_ID(N'` + st.entityTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.entityTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_type] nvarchar(40) NOT NULL,
+		[entity_handle] nvarchar(60) DEFAULT '',
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL
+	);
+	`
+
+	sqlMssql3 := `
+	IF OBJECT_ID(N'` + st.entityTrashTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.entityTrashTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_type] nvarchar(40) NOT NULL,
+		[entity_handle] nvarchar(60) DEFAULT '',
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL,
+		[deleted_at] datetime2 NOT NULL,
+		[deleted_by] nvarchar(40)
+	);
+	`
+
+	sqlMssql4 := `
+	IF OBJECT_ID(N'` + st.attributeTrashTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.attributeTrashTableName + `] (
+		[id] nvarchar(40) NOT NULL PRIMARY KEY,
+		[entity_id] nvarchar(40) NOT NULL,
+		[attribute_key] nvarchar(255) NOT NULL,
+		[attribute_value] nvarchar(max),
+		[created_at] datetime2 NOT NULL,
+		[updated_at] datetime2 NOT NULL,
+		[deleted_at] datetime2 NOT NULL,
+		[deleted_by] nvarchar(40)
+	);
+	`
+
+	sqlMssqlIndexes := []string{
+		st.sqlMssqlCreateIndex(st.attributeTableName, "entity_id_attribute_key", `CREATE UNIQUE INDEX [`+st.indexName(st.attributeTableName, "entity_id_attribute_key")+`] ON [`+st.attributeTableName+`] ([entity_id], [attribute_key]);`),
+		st.sqlMssqlCreateIndex(st.attributeTableName, "attribute_key_value", `CREATE INDEX [`+st.indexName(st.attributeTableName, "attribute_key_value")+`] ON [`+st.attributeTableName+`] ([attribute_key]) INCLUDE ([attribute_value]);`),
+		st.sqlMssqlCreateIndex(st.entityTableName, "entity_type", `CREATE INDEX [`+st.indexName(st.entityTableName, "entity_type")+`] ON [`+st.entityTableName+`] ([entity_type]);`),
+		st.sqlMssqlCreateIndex(st.entityTableName, "entity_handle", `CREATE INDEX [`+st.indexName(st.entityTableName, "entity_handle")+`] ON [`+st.entityTableName+`] ([entity_handle]);`),
+	}
+
+	sqls := []string{}
+
+	if st.dbDriverName == "mysql" {
+		sqls = append(sqls, sqlMysql1)
+		sqls = append(sqls, sqlMysql2)
+		sqls = append(sqls, sqlMysql3)
+		sqls = append(sqls, sqlMysql4)
+		sqls = append(sqls, sqlMysqlIndexes...)
+	} else if st.dbDriverName == "postgres" {
+		sqls = append(sqls, sqlPostgres1)
+		sqls = append(sqls, sqlPostgres2)
+		sqls = append(sqls, sqlPostgres3)
+		sqls = append(sqls, sqlPostgres4)
+		sqls = append(sqls, sqlPostgresIndexes...)
+	} else if st.dbDriverName == "sqlite" {
+		sqls = append(sqls, sqlSqlite1)
+		sqls = append(sqls, sqlSqlite2)
+		sqls = append(sqls, sqlSqlite3)
+		sqls = append(sqls, sqlSqlite4)
+		sqls = append(sqls, sqlSqliteIndexes...)
+	} else if st.dbDriverName == "mssql" {
+		sqls = append(sqls, sqlMssql1)
+		sqls = append(sqls, sqlMssql2)
+		sqls = append(sqls, sqlMssql3)
+		sqls = append(sqls, sqlMssql4)
+		sqls = append(sqls, sqlMssqlIndexes...)
+	} else {
+		return nil, errors.New("unsupported driver " + st.dbDriverName)
+	}
+
+	return sqls, nil
+}
@@ -0,0 +1,146 @@
+package entitystore
+
+import (
+	"errors"
+	"hash/fnv"
+)
+
+// migration is a single versioned schema change. The up function returns
+// the statements for the store's database driver.
+//
+// MySQL commits each DDL statement implicitly, a migration failing halfway
+// stays partially applied and is applied again from the start. Its MySQL
+// statements must therefore be idempotent, the indexes and columns being
+// created with the guards of sqlMysqlCreateIndex and sqlMysqlAddColumn
+type migration struct {
+	version     int
+	description string
+	up          func(st *Store) ([]string, error)
+}
+
+// migrations returns the schema migrations of the store in the order
+// they must be applied. Released migrations must never be changed,
+// schema changes are added as new migrations at the end of the list
+func (st *Store) migrations() []migration {
+	return []migration{
+		{
+			version:     1,
+			description: "create entity, attribute and trash tables",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlMigration1()
+			},
+		},
+		{
+			version:     2,
+			description: "create outbox and change feed cursor tables",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlCreateOutboxTables()
+			},
+		},
+		{
+			version:     3,
+			description: "add version column to entity tables",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlAddEntityVersion()
+			},
+		},
+		{
+			version:     4,
+			description: "create entity lock table",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlCreateLockTable()
+			},
+		},
+		{
+			version:     5,
+			description: "create attribute value table",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlCreateAttributeValueTable()
+			},
+		},
+		{
+			version:     6,
+			description: "create entity handle table",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlCreateHandleTable()
+			},
+		},
+		{
+			version:     7,
+			description: "create outbox lock table",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlCreateOutboxLockTable()
+			},
+		},
+		{
+			version:     8,
+			description: "create attribute value trash table",
+			up: func(st *Store) ([]string, error) {
+				return st.sqlCreateAttributeValueTrashTable()
+			},
+		},
+	}
+}
+
+// sqlCreateSchemaVersionTable returns the SQL creating the table which
+// records the applied migrations
+func (st *Store) sqlCreateSchemaVersionTable() (string, error) {
+	sqlMysql := `
+	CREATE TABLE IF NOT EXISTS ` + st.schemaVersionTableName + ` (
+		version int NOT NULL PRIMARY KEY,
+		description varchar(255) NOT NULL,
+		applied_at datetime NOT NULL
+	);
+	`
+
+	sqlPostgres := `
+	CREATE TABLE IF NOT EXISTS ` + st.schemaVersionTableName + ` (
+		"version" integer NOT NULL PRIMARY KEY,
+		"description" varchar(255) NOT NULL,
+		"applied_at" timestamptz(6) NOT NULL
+	);
+	`
+
+	sqlSqlite := `
+	CREATE TABLE IF NOT EXISTS "` + st.schemaVersionTableName + `" (
+		"version" integer NOT NULL PRIMARY KEY,
+		"description" varchar(255) NOT NULL,
+		"applied_at" datetime NOT NULL
+	);
+	`
+
+	sqlMssql := `
+	IF OBJECT_ID(N'` + st.schemaVersionTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.schemaVersionTableName + `] (
+		[version] int NOT NULL PRIMARY KEY,
+		[description] nvarchar(255) NOT NULL,
+		[applied_at] datetime2 NOT NULL
+	);
+	`
+
+	if st.dbDriverName == "mysql" {
+		return sqlMysql, nil
+	} else if st.dbDriverName == "postgres" {
+		return sqlPostgres, nil
+	} else if st.dbDriverName == "sqlite" {
+		return sqlSqlite, nil
+	} else if st.dbDriverName == "mssql" {
+		return sqlMssql, nil
+	}
+
+	return "", errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// migrationLockName returns the name of the lock held while migrating,
+// unique for each store sharing a database
+func (st *Store) migrationLockName() string {
+	return "entitystore_" + st.schemaVersionTableName
+}
+
+// migrationLockKey returns the migration lock name as a PostgreSQL
+// advisory lock key
+func (st *Store) migrationLockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(st.migrationLockName()))
+	return int64(h.Sum64())
+}
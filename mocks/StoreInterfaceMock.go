@@ -0,0 +1,5762 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"database/sql"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/gouniverse/entitystore"
+	"io"
+	"iter"
+	"sync"
+	"time"
+)
+
+var (
+	lockStoreInterfaceMockAggregate                  sync.RWMutex
+	lockStoreInterfaceMockAttributeAddToSet          sync.RWMutex
+	lockStoreInterfaceMockAttributeAppendToList      sync.RWMutex
+	lockStoreInterfaceMockAttributeCreate            sync.RWMutex
+	lockStoreInterfaceMockAttributeDistinctValues    sync.RWMutex
+	lockStoreInterfaceMockAttributeFind              sync.RWMutex
+	lockStoreInterfaceMockAttributeIncrement         sync.RWMutex
+	lockStoreInterfaceMockAttributeIncrementFloat    sync.RWMutex
+	lockStoreInterfaceMockAttributeInsert            sync.RWMutex
+	lockStoreInterfaceMockAttributeKeys              sync.RWMutex
+	lockStoreInterfaceMockAttributeList              sync.RWMutex
+	lockStoreInterfaceMockAttributeQuery             sync.RWMutex
+	lockStoreInterfaceMockAttributeRemoveFromSet     sync.RWMutex
+	lockStoreInterfaceMockAttributeSetFloat          sync.RWMutex
+	lockStoreInterfaceMockAttributeSetInt            sync.RWMutex
+	lockStoreInterfaceMockAttributeSetString         sync.RWMutex
+	lockStoreInterfaceMockAttributeUpdate            sync.RWMutex
+	lockStoreInterfaceMockAttributeValues            sync.RWMutex
+	lockStoreInterfaceMockAttributesSet              sync.RWMutex
+	lockStoreInterfaceMockAttributesSetIfVersion     sync.RWMutex
+	lockStoreInterfaceMockAutoMigrate                sync.RWMutex
+	lockStoreInterfaceMockBackup                     sync.RWMutex
+	lockStoreInterfaceMockCacheStats                 sync.RWMutex
+	lockStoreInterfaceMockChangeFeed                 sync.RWMutex
+	lockStoreInterfaceMockChangeFeedPrune            sync.RWMutex
+	lockStoreInterfaceMockClose                      sync.RWMutex
+	lockStoreInterfaceMockCopyTo                     sync.RWMutex
+	lockStoreInterfaceMockEnableDebug                sync.RWMutex
+	lockStoreInterfaceMockEntityAttributeList        sync.RWMutex
+	lockStoreInterfaceMockEntityCount                sync.RWMutex
+	lockStoreInterfaceMockEntityCreate               sync.RWMutex
+	lockStoreInterfaceMockEntityCreateWithAttributes sync.RWMutex
+	lockStoreInterfaceMockEntityCreateWithHandle     sync.RWMutex
+	lockStoreInterfaceMockEntityCursor               sync.RWMutex
+	lockStoreInterfaceMockEntityDelete               sync.RWMutex
+	lockStoreInterfaceMockEntityFindByAttribute      sync.RWMutex
+	lockStoreInterfaceMockEntityFindByHandle         sync.RWMutex
+	lockStoreInterfaceMockEntityFindByID             sync.RWMutex
+	lockStoreInterfaceMockEntityHandleGenerate       sync.RWMutex
+	lockStoreInterfaceMockEntityIterate              sync.RWMutex
+	lockStoreInterfaceMockEntityList                 sync.RWMutex
+	lockStoreInterfaceMockEntityListByAttribute      sync.RWMutex
+	lockStoreInterfaceMockEntityLock                 sync.RWMutex
+	lockStoreInterfaceMockEntityLockRenew            sync.RWMutex
+	lockStoreInterfaceMockEntityQuery                sync.RWMutex
+	lockStoreInterfaceMockEntityRestore              sync.RWMutex
+	lockStoreInterfaceMockEntitySetHandle            sync.RWMutex
+	lockStoreInterfaceMockEntityTrash                sync.RWMutex
+	lockStoreInterfaceMockEntityTypes                sync.RWMutex
+	lockStoreInterfaceMockEntityUnlock               sync.RWMutex
+	lockStoreInterfaceMockEntityUpdate               sync.RWMutex
+	lockStoreInterfaceMockEntityUpdateIfVersion      sync.RWMutex
+	lockStoreInterfaceMockExport                     sync.RWMutex
+	lockStoreInterfaceMockExportCSV                  sync.RWMutex
+	lockStoreInterfaceMockFacets                     sync.RWMutex
+	lockStoreInterfaceMockGetAttributeTableName      sync.RWMutex
+	lockStoreInterfaceMockGetAttributeTrashTableName sync.RWMutex
+	lockStoreInterfaceMockGetDB                      sync.RWMutex
+	lockStoreInterfaceMockGetDebug                   sync.RWMutex
+	lockStoreInterfaceMockGetEntityTableName         sync.RWMutex
+	lockStoreInterfaceMockGetEntityTrashTableName    sync.RWMutex
+	lockStoreInterfaceMockGetSchemaVersionTableName  sync.RWMutex
+	lockStoreInterfaceMockImport                     sync.RWMutex
+	lockStoreInterfaceMockImportCSV                  sync.RWMutex
+	lockStoreInterfaceMockMigrate                    sync.RWMutex
+	lockStoreInterfaceMockMigrationStatus            sync.RWMutex
+	lockStoreInterfaceMockNewAttribute               sync.RWMutex
+	lockStoreInterfaceMockNewAttributeFromMap        sync.RWMutex
+	lockStoreInterfaceMockNewEntity                  sync.RWMutex
+	lockStoreInterfaceMockNewEntityFromMap           sync.RWMutex
+	lockStoreInterfaceMockRegisterEntityHook         sync.RWMutex
+	lockStoreInterfaceMockRepairDuplicateAttributes  sync.RWMutex
+	lockStoreInterfaceMockRestore                    sync.RWMutex
+	lockStoreInterfaceMockSqlCreateTable             sync.RWMutex
+	lockStoreInterfaceMockStats                      sync.RWMutex
+	lockStoreInterfaceMockSubscribe                  sync.RWMutex
+	lockStoreInterfaceMockSync                       sync.RWMutex
+)
+
+// Ensure, that StoreInterfaceMock does implement StoreInterface.
+// If this is not the case, regenerate this file with moq.
+var _ entitystore.StoreInterface = &StoreInterfaceMock{}
+
+// StoreInterfaceMock is a mock implementation of StoreInterface.
+//
+//	    func TestSomethingThatUsesStoreInterface(t *testing.T) {
+//
+//	        // make and configure a mocked StoreInterface
+//	        mockedStoreInterface := &StoreInterfaceMock{
+//	            AggregateFunc: func(entityType string, options entitystore.AggregateOptions) ([]entitystore.AggregateResult, error) {
+//		               panic("mock out the Aggregate method")
+//	            },
+//	            AttributeAddToSetFunc: func(entityID string, attributeKey string, values ...string) error {
+//		               panic("mock out the AttributeAddToSet method")
+//	            },
+//	            AttributeAppendToListFunc: func(entityID string, attributeKey string, values ...string) error {
+//		               panic("mock out the AttributeAppendToList method")
+//	            },
+//	            AttributeCreateFunc: func(entityID string, attributeKey string, attributeValue string) (*entitystore.Attribute, error) {
+//		               panic("mock out the AttributeCreate method")
+//	            },
+//	            AttributeDistinctValuesFunc: func(entityType string, attributeKey string, filter map[string]string) ([]string, error) {
+//		               panic("mock out the AttributeDistinctValues method")
+//	            },
+//	            AttributeFindFunc: func(entityID string, attributeKey string, options ...entitystore.FindOptions) (*entitystore.Attribute, error) {
+//		               panic("mock out the AttributeFind method")
+//	            },
+//	            AttributeIncrementFunc: func(entityID string, attributeKey string, delta int64) (int64, error) {
+//		               panic("mock out the AttributeIncrement method")
+//	            },
+//	            AttributeIncrementFloatFunc: func(entityID string, attributeKey string, delta float64) (float64, error) {
+//		               panic("mock out the AttributeIncrementFloat method")
+//	            },
+//	            AttributeInsertFunc: func(attr entitystore.Attribute) (*entitystore.Attribute, error) {
+//		               panic("mock out the AttributeInsert method")
+//	            },
+//	            AttributeKeysFunc: func(entityType string) ([]entitystore.AttributeKeyInfo, error) {
+//		               panic("mock out the AttributeKeys method")
+//	            },
+//	            AttributeListFunc: func(options entitystore.AttributeQueryOptions) ([]entitystore.Attribute, error) {
+//		               panic("mock out the AttributeList method")
+//	            },
+//	            AttributeQueryFunc: func(options entitystore.AttributeQueryOptions) *goqu.SelectDataset {
+//		               panic("mock out the AttributeQuery method")
+//	            },
+//	            AttributeRemoveFromSetFunc: func(entityID string, attributeKey string, values ...string) error {
+//		               panic("mock out the AttributeRemoveFromSet method")
+//	            },
+//	            AttributeSetFloatFunc: func(entityID string, attributeKey string, attributeValue float64) error {
+//		               panic("mock out the AttributeSetFloat method")
+//	            },
+//	            AttributeSetIntFunc: func(entityID string, attributeKey string, attributeValue int64) error {
+//		               panic("mock out the AttributeSetInt method")
+//	            },
+//	            AttributeSetStringFunc: func(entityID string, attributeKey string, attributeValue string) error {
+//		               panic("mock out the AttributeSetString method")
+//	            },
+//	            AttributeUpdateFunc: func(attr entitystore.Attribute) error {
+//		               panic("mock out the AttributeUpdate method")
+//	            },
+//	            AttributeValuesFunc: func(entityID string, attributeKey string) ([]string, error) {
+//		               panic("mock out the AttributeValues method")
+//	            },
+//	            AttributesSetFunc: func(entityID string, attributes map[string]string) error {
+//		               panic("mock out the AttributesSet method")
+//	            },
+//	            AttributesSetIfVersionFunc: func(entityID string, version int64, attributes map[string]string) error {
+//		               panic("mock out the AttributesSetIfVersion method")
+//	            },
+//	            AutoMigrateFunc: func() error {
+//		               panic("mock out the AutoMigrate method")
+//	            },
+//	            BackupFunc: func(writer io.Writer) (*entitystore.BackupManifest, error) {
+//		               panic("mock out the Backup method")
+//	            },
+//	            CacheStatsFunc: func() entitystore.CacheStats {
+//		               panic("mock out the CacheStats method")
+//	            },
+//	            ChangeFeedFunc: func(consumer string) (*entitystore.ChangeFeed, error) {
+//		               panic("mock out the ChangeFeed method")
+//	            },
+//	            ChangeFeedPruneFunc: func() (int64, error) {
+//		               panic("mock out the ChangeFeedPrune method")
+//	            },
+//	            CloseFunc: func() error {
+//		               panic("mock out the Close method")
+//	            },
+//	            CopyToFunc: func(dst entitystore.StoreInterface, filter entitystore.EntityQueryOptions, options entitystore.CopyOptions) (*entitystore.CopyReport, error) {
+//		               panic("mock out the CopyTo method")
+//	            },
+//	            EnableDebugFunc: func(debug bool)  {
+//		               panic("mock out the EnableDebug method")
+//	            },
+//	            EntityAttributeListFunc: func(entityID string) ([]entitystore.Attribute, error) {
+//		               panic("mock out the EntityAttributeList method")
+//	            },
+//	            EntityCountFunc: func(options entitystore.EntityQueryOptions) (int64, error) {
+//		               panic("mock out the EntityCount method")
+//	            },
+//	            EntityCreateFunc: func(entityType string) (*entitystore.Entity, error) {
+//		               panic("mock out the EntityCreate method")
+//	            },
+//	            EntityCreateWithAttributesFunc: func(entityType string, attributes map[string]string) (*entitystore.Entity, error) {
+//		               panic("mock out the EntityCreateWithAttributes method")
+//	            },
+//	            EntityCreateWithHandleFunc: func(entityType string, entityHandle string, attributes map[string]string) (*entitystore.Entity, error) {
+//		               panic("mock out the EntityCreateWithHandle method")
+//	            },
+//	            EntityCursorFunc: func(options entitystore.EntityQueryOptions, iterateOptions ...entitystore.IterateOptions) iter.Seq2[entitystore.EntityWithAttributes, error] {
+//		               panic("mock out the EntityCursor method")
+//	            },
+//	            EntityDeleteFunc: func(entityID string) (bool, error) {
+//		               panic("mock out the EntityDelete method")
+//	            },
+//	            EntityFindByAttributeFunc: func(entityType string, attributeKey string, attributeValue string) (*entitystore.Entity, error) {
+//		               panic("mock out the EntityFindByAttribute method")
+//	            },
+//	            EntityFindByHandleFunc: func(entityType string, entityHandle string, options ...entitystore.FindOptions) (*entitystore.Entity, error) {
+//		               panic("mock out the EntityFindByHandle method")
+//	            },
+//	            EntityFindByIDFunc: func(entityID string, options ...entitystore.FindOptions) (*entitystore.Entity, error) {
+//		               panic("mock out the EntityFindByID method")
+//	            },
+//	            EntityHandleGenerateFunc: func(entityType string, text string) (string, error) {
+//		               panic("mock out the EntityHandleGenerate method")
+//	            },
+//	            EntityIterateFunc: func(options entitystore.EntityQueryOptions, fn func(entity entitystore.Entity, attributes []entitystore.Attribute) error, iterateOptions ...entitystore.IterateOptions) error {
+//		               panic("mock out the EntityIterate method")
+//	            },
+//	            EntityListFunc: func(options entitystore.EntityQueryOptions) ([]entitystore.Entity, error) {
+//		               panic("mock out the EntityList method")
+//	            },
+//	            EntityListByAttributeFunc: func(entityType string, attributeKey string, attributeValue string) ([]entitystore.Entity, error) {
+//		               panic("mock out the EntityListByAttribute method")
+//	            },
+//	            EntityLockFunc: func(entityID string, owner string, ttl time.Duration) (*entitystore.EntityLease, error) {
+//		               panic("mock out the EntityLock method")
+//	            },
+//	            EntityLockRenewFunc: func(entityID string, owner string, ttl time.Duration) (*entitystore.EntityLease, error) {
+//		               panic("mock out the EntityLockRenew method")
+//	            },
+//	            EntityQueryFunc: func(options entitystore.EntityQueryOptions) *goqu.SelectDataset {
+//		               panic("mock out the EntityQuery method")
+//	            },
+//	            EntityRestoreFunc: func(entityID string) (bool, error) {
+//		               panic("mock out the EntityRestore method")
+//	            },
+//	            EntitySetHandleFunc: func(entityID string, entityHandle string) error {
+//		               panic("mock out the EntitySetHandle method")
+//	            },
+//	            EntityTrashFunc: func(entityID string) (bool, error) {
+//		               panic("mock out the EntityTrash method")
+//	            },
+//	            EntityTypesFunc: func() ([]entitystore.EntityTypeCount, error) {
+//		               panic("mock out the EntityTypes method")
+//	            },
+//	            EntityUnlockFunc: func(entityID string, owner string) error {
+//		               panic("mock out the EntityUnlock method")
+//	            },
+//	            EntityUpdateFunc: func(ent entitystore.Entity) (bool, error) {
+//		               panic("mock out the EntityUpdate method")
+//	            },
+//	            EntityUpdateIfVersionFunc: func(ent entitystore.Entity, version int64) (bool, error) {
+//		               panic("mock out the EntityUpdateIfVersion method")
+//	            },
+//	            ExportFunc: func(writer io.Writer, filter entitystore.EntityQueryOptions) (int64, error) {
+//		               panic("mock out the Export method")
+//	            },
+//	            ExportCSVFunc: func(entityType string, attributeKeys []string, writer io.Writer) (int64, error) {
+//		               panic("mock out the ExportCSV method")
+//	            },
+//	            FacetsFunc: func(entityType string, attributeKeys []string, filter map[string]string, limit int) (map[string][]entitystore.FacetValue, error) {
+//		               panic("mock out the Facets method")
+//	            },
+//	            GetAttributeTableNameFunc: func() string {
+//		               panic("mock out the GetAttributeTableName method")
+//	            },
+//	            GetAttributeTrashTableNameFunc: func() string {
+//		               panic("mock out the GetAttributeTrashTableName method")
+//	            },
+//	            GetDBFunc: func() *sql.DB {
+//		               panic("mock out the GetDB method")
+//	            },
+//	            GetDebugFunc: func() bool {
+//		               panic("mock out the GetDebug method")
+//	            },
+//	            GetEntityTableNameFunc: func() string {
+//		               panic("mock out the GetEntityTableName method")
+//	            },
+//	            GetEntityTrashTableNameFunc: func() string {
+//		               panic("mock out the GetEntityTrashTableName method")
+//	            },
+//	            GetSchemaVersionTableNameFunc: func() string {
+//		               panic("mock out the GetSchemaVersionTableName method")
+//	            },
+//	            ImportFunc: func(reader io.Reader, options entitystore.ImportOptions) (*entitystore.ImportReport, error) {
+//		               panic("mock out the Import method")
+//	            },
+//	            ImportCSVFunc: func(entityType string, reader io.Reader, options entitystore.CSVImportOptions) (*entitystore.ImportReport, error) {
+//		               panic("mock out the ImportCSV method")
+//	            },
+//	            MigrateFunc: func() error {
+//		               panic("mock out the Migrate method")
+//	            },
+//	            MigrationStatusFunc: func() ([]entitystore.Migration, error) {
+//		               panic("mock out the MigrationStatus method")
+//	            },
+//	            NewAttributeFunc: func(opts entitystore.NewAttributeOptions) *entitystore.Attribute {
+//		               panic("mock out the NewAttribute method")
+//	            },
+//	            NewAttributeFromMapFunc: func(attributeMap map[string]string) *entitystore.Attribute {
+//		               panic("mock out the NewAttributeFromMap method")
+//	            },
+//	            NewEntityFunc: func(opts entitystore.NewEntityOptions) *entitystore.Entity {
+//		               panic("mock out the NewEntity method")
+//	            },
+//	            NewEntityFromMapFunc: func(entityMap map[string]string) *entitystore.Entity {
+//		               panic("mock out the NewEntityFromMap method")
+//	            },
+//	            RegisterEntityHookFunc: func(entityType string, hookType entitystore.EntityHookType, hook entitystore.EntityHook)  {
+//		               panic("mock out the RegisterEntityHook method")
+//	            },
+//	            RepairDuplicateAttributesFunc: func() (int64, error) {
+//		               panic("mock out the RepairDuplicateAttributes method")
+//	            },
+//	            RestoreFunc: func(reader io.Reader) (*entitystore.BackupManifest, error) {
+//		               panic("mock out the Restore method")
+//	            },
+//	            SqlCreateTableFunc: func() ([]string, error) {
+//		               panic("mock out the SqlCreateTable method")
+//	            },
+//	            StatsFunc: func() (entitystore.StoreStats, error) {
+//		               panic("mock out the Stats method")
+//	            },
+//	            SubscribeFunc: func(entityType string, filter func(event entitystore.ChangeEvent) bool, options entitystore.SubscribeOptions) *entitystore.Subscription {
+//		               panic("mock out the Subscribe method")
+//	            },
+//	            SyncFunc: func(dst entitystore.StoreInterface, filter entitystore.EntityQueryOptions, since time.Time, options entitystore.CopyOptions) (*entitystore.CopyReport, error) {
+//		               panic("mock out the Sync method")
+//	            },
+//	        }
+//
+//	        // use mockedStoreInterface in code that requires StoreInterface
+//	        // and then make assertions.
+//
+//	    }
+type StoreInterfaceMock struct {
+	// AggregateFunc mocks the Aggregate method.
+	AggregateFunc func(entityType string, options entitystore.AggregateOptions) ([]entitystore.AggregateResult, error)
+
+	// AttributeAddToSetFunc mocks the AttributeAddToSet method.
+	AttributeAddToSetFunc func(entityID string, attributeKey string, values ...string) error
+
+	// AttributeAppendToListFunc mocks the AttributeAppendToList method.
+	AttributeAppendToListFunc func(entityID string, attributeKey string, values ...string) error
+
+	// AttributeCreateFunc mocks the AttributeCreate method.
+	AttributeCreateFunc func(entityID string, attributeKey string, attributeValue string) (*entitystore.Attribute, error)
+
+	// AttributeDistinctValuesFunc mocks the AttributeDistinctValues method.
+	AttributeDistinctValuesFunc func(entityType string, attributeKey string, filter map[string]string) ([]string, error)
+
+	// AttributeFindFunc mocks the AttributeFind method.
+	AttributeFindFunc func(entityID string, attributeKey string, options ...entitystore.FindOptions) (*entitystore.Attribute, error)
+
+	// AttributeIncrementFunc mocks the AttributeIncrement method.
+	AttributeIncrementFunc func(entityID string, attributeKey string, delta int64) (int64, error)
+
+	// AttributeIncrementFloatFunc mocks the AttributeIncrementFloat method.
+	AttributeIncrementFloatFunc func(entityID string, attributeKey string, delta float64) (float64, error)
+
+	// AttributeInsertFunc mocks the AttributeInsert method.
+	AttributeInsertFunc func(attr entitystore.Attribute) (*entitystore.Attribute, error)
+
+	// AttributeKeysFunc mocks the AttributeKeys method.
+	AttributeKeysFunc func(entityType string) ([]entitystore.AttributeKeyInfo, error)
+
+	// AttributeListFunc mocks the AttributeList method.
+	AttributeListFunc func(options entitystore.AttributeQueryOptions) ([]entitystore.Attribute, error)
+
+	// AttributeQueryFunc mocks the AttributeQuery method.
+	AttributeQueryFunc func(options entitystore.AttributeQueryOptions) *goqu.SelectDataset
+
+	// AttributeRemoveFromSetFunc mocks the AttributeRemoveFromSet method.
+	AttributeRemoveFromSetFunc func(entityID string, attributeKey string, values ...string) error
+
+	// AttributeSetFloatFunc mocks the AttributeSetFloat method.
+	AttributeSetFloatFunc func(entityID string, attributeKey string, attributeValue float64) error
+
+	// AttributeSetIntFunc mocks the AttributeSetInt method.
+	AttributeSetIntFunc func(entityID string, attributeKey string, attributeValue int64) error
+
+	// AttributeSetStringFunc mocks the AttributeSetString method.
+	AttributeSetStringFunc func(entityID string, attributeKey string, attributeValue string) error
+
+	// AttributeUpdateFunc mocks the AttributeUpdate method.
+	AttributeUpdateFunc func(attr entitystore.Attribute) error
+
+	// AttributeValuesFunc mocks the AttributeValues method.
+	AttributeValuesFunc func(entityID string, attributeKey string) ([]string, error)
+
+	// AttributesSetFunc mocks the AttributesSet method.
+	AttributesSetFunc func(entityID string, attributes map[string]string) error
+
+	// AttributesSetIfVersionFunc mocks the AttributesSetIfVersion method.
+	AttributesSetIfVersionFunc func(entityID string, version int64, attributes map[string]string) error
+
+	// AutoMigrateFunc mocks the AutoMigrate method.
+	AutoMigrateFunc func() error
+
+	// BackupFunc mocks the Backup method.
+	BackupFunc func(writer io.Writer) (*entitystore.BackupManifest, error)
+
+	// CacheStatsFunc mocks the CacheStats method.
+	CacheStatsFunc func() entitystore.CacheStats
+
+	// ChangeFeedFunc mocks the ChangeFeed method.
+	ChangeFeedFunc func(consumer string) (*entitystore.ChangeFeed, error)
+
+	// ChangeFeedPruneFunc mocks the ChangeFeedPrune method.
+	ChangeFeedPruneFunc func() (int64, error)
+
+	// CloseFunc mocks the Close method.
+	CloseFunc func() error
+
+	// CopyToFunc mocks the CopyTo method.
+	CopyToFunc func(dst entitystore.StoreInterface, filter entitystore.EntityQueryOptions, options entitystore.CopyOptions) (*entitystore.CopyReport, error)
+
+	// EnableDebugFunc mocks the EnableDebug method.
+	EnableDebugFunc func(debug bool)
+
+	// EntityAttributeListFunc mocks the EntityAttributeList method.
+	EntityAttributeListFunc func(entityID string) ([]entitystore.Attribute, error)
+
+	// EntityCountFunc mocks the EntityCount method.
+	EntityCountFunc func(options entitystore.EntityQueryOptions) (int64, error)
+
+	// EntityCreateFunc mocks the EntityCreate method.
+	EntityCreateFunc func(entityType string) (*entitystore.Entity, error)
+
+	// EntityCreateWithAttributesFunc mocks the EntityCreateWithAttributes method.
+	EntityCreateWithAttributesFunc func(entityType string, attributes map[string]string) (*entitystore.Entity, error)
+
+	// EntityCreateWithHandleFunc mocks the EntityCreateWithHandle method.
+	EntityCreateWithHandleFunc func(entityType string, entityHandle string, attributes map[string]string) (*entitystore.Entity, error)
+
+	// EntityCursorFunc mocks the EntityCursor method.
+	EntityCursorFunc func(options entitystore.EntityQueryOptions, iterateOptions ...entitystore.IterateOptions) iter.Seq2[entitystore.EntityWithAttributes, error]
+
+	// EntityDeleteFunc mocks the EntityDelete method.
+	EntityDeleteFunc func(entityID string) (bool, error)
+
+	// EntityFindByAttributeFunc mocks the EntityFindByAttribute method.
+	EntityFindByAttributeFunc func(entityType string, attributeKey string, attributeValue string) (*entitystore.Entity, error)
+
+	// EntityFindByHandleFunc mocks the EntityFindByHandle method.
+	EntityFindByHandleFunc func(entityType string, entityHandle string, options ...entitystore.FindOptions) (*entitystore.Entity, error)
+
+	// EntityFindByIDFunc mocks the EntityFindByID method.
+	EntityFindByIDFunc func(entityID string, options ...entitystore.FindOptions) (*entitystore.Entity, error)
+
+	// EntityHandleGenerateFunc mocks the EntityHandleGenerate method.
+	EntityHandleGenerateFunc func(entityType string, text string) (string, error)
+
+	// EntityIterateFunc mocks the EntityIterate method.
+	EntityIterateFunc func(options entitystore.EntityQueryOptions, fn func(entity entitystore.Entity, attributes []entitystore.Attribute) error, iterateOptions ...entitystore.IterateOptions) error
+
+	// EntityListFunc mocks the EntityList method.
+	EntityListFunc func(options entitystore.EntityQueryOptions) ([]entitystore.Entity, error)
+
+	// EntityListByAttributeFunc mocks the EntityListByAttribute method.
+	EntityListByAttributeFunc func(entityType string, attributeKey string, attributeValue string) ([]entitystore.Entity, error)
+
+	// EntityLockFunc mocks the EntityLock method.
+	EntityLockFunc func(entityID string, owner string, ttl time.Duration) (*entitystore.EntityLease, error)
+
+	// EntityLockRenewFunc mocks the EntityLockRenew method.
+	EntityLockRenewFunc func(entityID string, owner string, ttl time.Duration) (*entitystore.EntityLease, error)
+
+	// EntityQueryFunc mocks the EntityQuery method.
+	EntityQueryFunc func(options entitystore.EntityQueryOptions) *goqu.SelectDataset
+
+	// EntityRestoreFunc mocks the EntityRestore method.
+	EntityRestoreFunc func(entityID string) (bool, error)
+
+	// EntitySetHandleFunc mocks the EntitySetHandle method.
+	EntitySetHandleFunc func(entityID string, entityHandle string) error
+
+	// EntityTrashFunc mocks the EntityTrash method.
+	EntityTrashFunc func(entityID string) (bool, error)
+
+	// EntityTypesFunc mocks the EntityTypes method.
+	EntityTypesFunc func() ([]entitystore.EntityTypeCount, error)
+
+	// EntityUnlockFunc mocks the EntityUnlock method.
+	EntityUnlockFunc func(entityID string, owner string) error
+
+	// EntityUpdateFunc mocks the EntityUpdate method.
+	EntityUpdateFunc func(ent entitystore.Entity) (bool, error)
+
+	// EntityUpdateIfVersionFunc mocks the EntityUpdateIfVersion method.
+	EntityUpdateIfVersionFunc func(ent entitystore.Entity, version int64) (bool, error)
+
+	// ExportFunc mocks the Export method.
+	ExportFunc func(writer io.Writer, filter entitystore.EntityQueryOptions) (int64, error)
+
+	// ExportCSVFunc mocks the ExportCSV method.
+	ExportCSVFunc func(entityType string, attributeKeys []string, writer io.Writer) (int64, error)
+
+	// FacetsFunc mocks the Facets method.
+	FacetsFunc func(entityType string, attributeKeys []string, filter map[string]string, limit int) (map[string][]entitystore.FacetValue, error)
+
+	// GetAttributeTableNameFunc mocks the GetAttributeTableName method.
+	GetAttributeTableNameFunc func() string
+
+	// GetAttributeTrashTableNameFunc mocks the GetAttributeTrashTableName method.
+	GetAttributeTrashTableNameFunc func() string
+
+	// GetDBFunc mocks the GetDB method.
+	GetDBFunc func() *sql.DB
+
+	// GetDebugFunc mocks the GetDebug method.
+	GetDebugFunc func() bool
+
+	// GetEntityTableNameFunc mocks the GetEntityTableName method.
+	GetEntityTableNameFunc func() string
+
+	// GetEntityTrashTableNameFunc mocks the GetEntityTrashTableName method.
+	GetEntityTrashTableNameFunc func() string
+
+	// GetSchemaVersionTableNameFunc mocks the GetSchemaVersionTableName method.
+	GetSchemaVersionTableNameFunc func() string
+
+	// ImportFunc mocks the Import method.
+	ImportFunc func(reader io.Reader, options entitystore.ImportOptions) (*entitystore.ImportReport, error)
+
+	// ImportCSVFunc mocks the ImportCSV method.
+	ImportCSVFunc func(entityType string, reader io.Reader, options entitystore.CSVImportOptions) (*entitystore.ImportReport, error)
+
+	// MigrateFunc mocks the Migrate method.
+	MigrateFunc func() error
+
+	// MigrationStatusFunc mocks the MigrationStatus method.
+	MigrationStatusFunc func() ([]entitystore.Migration, error)
+
+	// NewAttributeFunc mocks the NewAttribute method.
+	NewAttributeFunc func(opts entitystore.NewAttributeOptions) *entitystore.Attribute
+
+	// NewAttributeFromMapFunc mocks the NewAttributeFromMap method.
+	NewAttributeFromMapFunc func(attributeMap map[string]string) *entitystore.Attribute
+
+	// NewEntityFunc mocks the NewEntity method.
+	NewEntityFunc func(opts entitystore.NewEntityOptions) *entitystore.Entity
+
+	// NewEntityFromMapFunc mocks the NewEntityFromMap method.
+	NewEntityFromMapFunc func(entityMap map[string]string) *entitystore.Entity
+
+	// RegisterEntityHookFunc mocks the RegisterEntityHook method.
+	RegisterEntityHookFunc func(entityType string, hookType entitystore.EntityHookType, hook entitystore.EntityHook)
+
+	// RepairDuplicateAttributesFunc mocks the RepairDuplicateAttributes method.
+	RepairDuplicateAttributesFunc func() (int64, error)
+
+	// RestoreFunc mocks the Restore method.
+	RestoreFunc func(reader io.Reader) (*entitystore.BackupManifest, error)
+
+	// SqlCreateTableFunc mocks the SqlCreateTable method.
+	SqlCreateTableFunc func() ([]string, error)
+
+	// StatsFunc mocks the Stats method.
+	StatsFunc func() (entitystore.StoreStats, error)
+
+	// SubscribeFunc mocks the Subscribe method.
+	SubscribeFunc func(entityType string, filter func(event entitystore.ChangeEvent) bool, options entitystore.SubscribeOptions) *entitystore.Subscription
+
+	// SyncFunc mocks the Sync method.
+	SyncFunc func(dst entitystore.StoreInterface, filter entitystore.EntityQueryOptions, since time.Time, options entitystore.CopyOptions) (*entitystore.CopyReport, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Aggregate holds details about calls to the Aggregate method.
+		Aggregate []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// Options is the options argument value.
+			Options entitystore.AggregateOptions
+		}
+		// AttributeAddToSet holds details about calls to the AttributeAddToSet method.
+		AttributeAddToSet []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Values is the values argument value.
+			Values []string
+		}
+		// AttributeAppendToList holds details about calls to the AttributeAppendToList method.
+		AttributeAppendToList []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Values is the values argument value.
+			Values []string
+		}
+		// AttributeCreate holds details about calls to the AttributeCreate method.
+		AttributeCreate []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue string
+		}
+		// AttributeDistinctValues holds details about calls to the AttributeDistinctValues method.
+		AttributeDistinctValues []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Filter is the filter argument value.
+			Filter map[string]string
+		}
+		// AttributeFind holds details about calls to the AttributeFind method.
+		AttributeFind []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Options is the options argument value.
+			Options []entitystore.FindOptions
+		}
+		// AttributeIncrement holds details about calls to the AttributeIncrement method.
+		AttributeIncrement []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Delta is the delta argument value.
+			Delta int64
+		}
+		// AttributeIncrementFloat holds details about calls to the AttributeIncrementFloat method.
+		AttributeIncrementFloat []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Delta is the delta argument value.
+			Delta float64
+		}
+		// AttributeInsert holds details about calls to the AttributeInsert method.
+		AttributeInsert []struct {
+			// Attr is the attr argument value.
+			Attr entitystore.Attribute
+		}
+		// AttributeKeys holds details about calls to the AttributeKeys method.
+		AttributeKeys []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+		}
+		// AttributeList holds details about calls to the AttributeList method.
+		AttributeList []struct {
+			// Options is the options argument value.
+			Options entitystore.AttributeQueryOptions
+		}
+		// AttributeQuery holds details about calls to the AttributeQuery method.
+		AttributeQuery []struct {
+			// Options is the options argument value.
+			Options entitystore.AttributeQueryOptions
+		}
+		// AttributeRemoveFromSet holds details about calls to the AttributeRemoveFromSet method.
+		AttributeRemoveFromSet []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Values is the values argument value.
+			Values []string
+		}
+		// AttributeSetFloat holds details about calls to the AttributeSetFloat method.
+		AttributeSetFloat []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue float64
+		}
+		// AttributeSetInt holds details about calls to the AttributeSetInt method.
+		AttributeSetInt []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue int64
+		}
+		// AttributeSetString holds details about calls to the AttributeSetString method.
+		AttributeSetString []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue string
+		}
+		// AttributeUpdate holds details about calls to the AttributeUpdate method.
+		AttributeUpdate []struct {
+			// Attr is the attr argument value.
+			Attr entitystore.Attribute
+		}
+		// AttributeValues holds details about calls to the AttributeValues method.
+		AttributeValues []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+		}
+		// AttributesSet holds details about calls to the AttributesSet method.
+		AttributesSet []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+		// AttributesSetIfVersion holds details about calls to the AttributesSetIfVersion method.
+		AttributesSetIfVersion []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// Version is the version argument value.
+			Version int64
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+		// AutoMigrate holds details about calls to the AutoMigrate method.
+		AutoMigrate []struct {
+		}
+		// Backup holds details about calls to the Backup method.
+		Backup []struct {
+			// Writer is the writer argument value.
+			Writer io.Writer
+		}
+		// CacheStats holds details about calls to the CacheStats method.
+		CacheStats []struct {
+		}
+		// ChangeFeed holds details about calls to the ChangeFeed method.
+		ChangeFeed []struct {
+			// Consumer is the consumer argument value.
+			Consumer string
+		}
+		// ChangeFeedPrune holds details about calls to the ChangeFeedPrune method.
+		ChangeFeedPrune []struct {
+		}
+		// Close holds details about calls to the Close method.
+		Close []struct {
+		}
+		// CopyTo holds details about calls to the CopyTo method.
+		CopyTo []struct {
+			// Dst is the dst argument value.
+			Dst entitystore.StoreInterface
+			// Filter is the filter argument value.
+			Filter entitystore.EntityQueryOptions
+			// Options is the options argument value.
+			Options entitystore.CopyOptions
+		}
+		// EnableDebug holds details about calls to the EnableDebug method.
+		EnableDebug []struct {
+			// Debug is the debug argument value.
+			Debug bool
+		}
+		// EntityAttributeList holds details about calls to the EntityAttributeList method.
+		EntityAttributeList []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+		}
+		// EntityCount holds details about calls to the EntityCount method.
+		EntityCount []struct {
+			// Options is the options argument value.
+			Options entitystore.EntityQueryOptions
+		}
+		// EntityCreate holds details about calls to the EntityCreate method.
+		EntityCreate []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+		}
+		// EntityCreateWithAttributes holds details about calls to the EntityCreateWithAttributes method.
+		EntityCreateWithAttributes []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+		// EntityCreateWithHandle holds details about calls to the EntityCreateWithHandle method.
+		EntityCreateWithHandle []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// EntityHandle is the entityHandle argument value.
+			EntityHandle string
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+		// EntityCursor holds details about calls to the EntityCursor method.
+		EntityCursor []struct {
+			// Options is the options argument value.
+			Options entitystore.EntityQueryOptions
+			// IterateOptions is the iterateOptions argument value.
+			IterateOptions []entitystore.IterateOptions
+		}
+		// EntityDelete holds details about calls to the EntityDelete method.
+		EntityDelete []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+		}
+		// EntityFindByAttribute holds details about calls to the EntityFindByAttribute method.
+		EntityFindByAttribute []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue string
+		}
+		// EntityFindByHandle holds details about calls to the EntityFindByHandle method.
+		EntityFindByHandle []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// EntityHandle is the entityHandle argument value.
+			EntityHandle string
+			// Options is the options argument value.
+			Options []entitystore.FindOptions
+		}
+		// EntityFindByID holds details about calls to the EntityFindByID method.
+		EntityFindByID []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// Options is the options argument value.
+			Options []entitystore.FindOptions
+		}
+		// EntityHandleGenerate holds details about calls to the EntityHandleGenerate method.
+		EntityHandleGenerate []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// Text is the text argument value.
+			Text string
+		}
+		// EntityIterate holds details about calls to the EntityIterate method.
+		EntityIterate []struct {
+			// Options is the options argument value.
+			Options entitystore.EntityQueryOptions
+			// Fn is the fn argument value.
+			Fn func(entity entitystore.Entity, attributes []entitystore.Attribute) error
+			// IterateOptions is the iterateOptions argument value.
+			IterateOptions []entitystore.IterateOptions
+		}
+		// EntityList holds details about calls to the EntityList method.
+		EntityList []struct {
+			// Options is the options argument value.
+			Options entitystore.EntityQueryOptions
+		}
+		// EntityListByAttribute holds details about calls to the EntityListByAttribute method.
+		EntityListByAttribute []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue string
+		}
+		// EntityLock holds details about calls to the EntityLock method.
+		EntityLock []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// Owner is the owner argument value.
+			Owner string
+			// TTL is the ttl argument value.
+			TTL time.Duration
+		}
+		// EntityLockRenew holds details about calls to the EntityLockRenew method.
+		EntityLockRenew []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// Owner is the owner argument value.
+			Owner string
+			// TTL is the ttl argument value.
+			TTL time.Duration
+		}
+		// EntityQuery holds details about calls to the EntityQuery method.
+		EntityQuery []struct {
+			// Options is the options argument value.
+			Options entitystore.EntityQueryOptions
+		}
+		// EntityRestore holds details about calls to the EntityRestore method.
+		EntityRestore []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+		}
+		// EntitySetHandle holds details about calls to the EntitySetHandle method.
+		EntitySetHandle []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// EntityHandle is the entityHandle argument value.
+			EntityHandle string
+		}
+		// EntityTrash holds details about calls to the EntityTrash method.
+		EntityTrash []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+		}
+		// EntityTypes holds details about calls to the EntityTypes method.
+		EntityTypes []struct {
+		}
+		// EntityUnlock holds details about calls to the EntityUnlock method.
+		EntityUnlock []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+			// Owner is the owner argument value.
+			Owner string
+		}
+		// EntityUpdate holds details about calls to the EntityUpdate method.
+		EntityUpdate []struct {
+			// Ent is the ent argument value.
+			Ent entitystore.Entity
+		}
+		// EntityUpdateIfVersion holds details about calls to the EntityUpdateIfVersion method.
+		EntityUpdateIfVersion []struct {
+			// Ent is the ent argument value.
+			Ent entitystore.Entity
+			// Version is the version argument value.
+			Version int64
+		}
+		// Export holds details about calls to the Export method.
+		Export []struct {
+			// Writer is the writer argument value.
+			Writer io.Writer
+			// Filter is the filter argument value.
+			Filter entitystore.EntityQueryOptions
+		}
+		// ExportCSV holds details about calls to the ExportCSV method.
+		ExportCSV []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// AttributeKeys is the attributeKeys argument value.
+			AttributeKeys []string
+			// Writer is the writer argument value.
+			Writer io.Writer
+		}
+		// Facets holds details about calls to the Facets method.
+		Facets []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// AttributeKeys is the attributeKeys argument value.
+			AttributeKeys []string
+			// Filter is the filter argument value.
+			Filter map[string]string
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// GetAttributeTableName holds details about calls to the GetAttributeTableName method.
+		GetAttributeTableName []struct {
+		}
+		// GetAttributeTrashTableName holds details about calls to the GetAttributeTrashTableName method.
+		GetAttributeTrashTableName []struct {
+		}
+		// GetDB holds details about calls to the GetDB method.
+		GetDB []struct {
+		}
+		// GetDebug holds details about calls to the GetDebug method.
+		GetDebug []struct {
+		}
+		// GetEntityTableName holds details about calls to the GetEntityTableName method.
+		GetEntityTableName []struct {
+		}
+		// GetEntityTrashTableName holds details about calls to the GetEntityTrashTableName method.
+		GetEntityTrashTableName []struct {
+		}
+		// GetSchemaVersionTableName holds details about calls to the GetSchemaVersionTableName method.
+		GetSchemaVersionTableName []struct {
+		}
+		// Import holds details about calls to the Import method.
+		Import []struct {
+			// Reader is the reader argument value.
+			Reader io.Reader
+			// Options is the options argument value.
+			Options entitystore.ImportOptions
+		}
+		// ImportCSV holds details about calls to the ImportCSV method.
+		ImportCSV []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// Reader is the reader argument value.
+			Reader io.Reader
+			// Options is the options argument value.
+			Options entitystore.CSVImportOptions
+		}
+		// Migrate holds details about calls to the Migrate method.
+		Migrate []struct {
+		}
+		// MigrationStatus holds details about calls to the MigrationStatus method.
+		MigrationStatus []struct {
+		}
+		// NewAttribute holds details about calls to the NewAttribute method.
+		NewAttribute []struct {
+			// Opts is the opts argument value.
+			Opts entitystore.NewAttributeOptions
+		}
+		// NewAttributeFromMap holds details about calls to the NewAttributeFromMap method.
+		NewAttributeFromMap []struct {
+			// AttributeMap is the attributeMap argument value.
+			AttributeMap map[string]string
+		}
+		// NewEntity holds details about calls to the NewEntity method.
+		NewEntity []struct {
+			// Opts is the opts argument value.
+			Opts entitystore.NewEntityOptions
+		}
+		// NewEntityFromMap holds details about calls to the NewEntityFromMap method.
+		NewEntityFromMap []struct {
+			// EntityMap is the entityMap argument value.
+			EntityMap map[string]string
+		}
+		// RegisterEntityHook holds details about calls to the RegisterEntityHook method.
+		RegisterEntityHook []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// HookType is the hookType argument value.
+			HookType entitystore.EntityHookType
+			// Hook is the hook argument value.
+			Hook entitystore.EntityHook
+		}
+		// RepairDuplicateAttributes holds details about calls to the RepairDuplicateAttributes method.
+		RepairDuplicateAttributes []struct {
+		}
+		// Restore holds details about calls to the Restore method.
+		Restore []struct {
+			// Reader is the reader argument value.
+			Reader io.Reader
+		}
+		// SqlCreateTable holds details about calls to the SqlCreateTable method.
+		SqlCreateTable []struct {
+		}
+		// Stats holds details about calls to the Stats method.
+		Stats []struct {
+		}
+		// Subscribe holds details about calls to the Subscribe method.
+		Subscribe []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+			// Filter is the filter argument value.
+			Filter func(event entitystore.ChangeEvent) bool
+			// Options is the options argument value.
+			Options entitystore.SubscribeOptions
+		}
+		// Sync holds details about calls to the Sync method.
+		Sync []struct {
+			// Dst is the dst argument value.
+			Dst entitystore.StoreInterface
+			// Filter is the filter argument value.
+			Filter entitystore.EntityQueryOptions
+			// Since is the since argument value.
+			Since time.Time
+			// Options is the options argument value.
+			Options entitystore.CopyOptions
+		}
+	}
+}
+
+// Aggregate calls AggregateFunc.
+func (mock *StoreInterfaceMock) Aggregate(entityType string, options entitystore.AggregateOptions) ([]entitystore.AggregateResult, error) {
+	if mock.AggregateFunc == nil {
+		panic("StoreInterfaceMock.AggregateFunc: method is nil but StoreInterface.Aggregate was just called")
+	}
+	callInfo := struct {
+		EntityType string
+		Options    entitystore.AggregateOptions
+	}{
+		EntityType: entityType,
+		Options:    options,
+	}
+	lockStoreInterfaceMockAggregate.Lock()
+	mock.calls.Aggregate = append(mock.calls.Aggregate, callInfo)
+	lockStoreInterfaceMockAggregate.Unlock()
+	return mock.AggregateFunc(entityType, options)
+}
+
+// AggregateCalls gets all the calls that were made to Aggregate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AggregateCalls())
+func (mock *StoreInterfaceMock) AggregateCalls() []struct {
+	EntityType string
+	Options    entitystore.AggregateOptions
+} {
+	var calls []struct {
+		EntityType string
+		Options    entitystore.AggregateOptions
+	}
+	lockStoreInterfaceMockAggregate.RLock()
+	calls = mock.calls.Aggregate
+	lockStoreInterfaceMockAggregate.RUnlock()
+	return calls
+}
+
+// AttributeAddToSet calls AttributeAddToSetFunc.
+func (mock *StoreInterfaceMock) AttributeAddToSet(entityID string, attributeKey string, values ...string) error {
+	if mock.AttributeAddToSetFunc == nil {
+		panic("StoreInterfaceMock.AttributeAddToSetFunc: method is nil but StoreInterface.AttributeAddToSet was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+		Values       []string
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+		Values:       values,
+	}
+	lockStoreInterfaceMockAttributeAddToSet.Lock()
+	mock.calls.AttributeAddToSet = append(mock.calls.AttributeAddToSet, callInfo)
+	lockStoreInterfaceMockAttributeAddToSet.Unlock()
+	return mock.AttributeAddToSetFunc(entityID, attributeKey, values...)
+}
+
+// AttributeAddToSetCalls gets all the calls that were made to AttributeAddToSet.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeAddToSetCalls())
+func (mock *StoreInterfaceMock) AttributeAddToSetCalls() []struct {
+	EntityID     string
+	AttributeKey string
+	Values       []string
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+		Values       []string
+	}
+	lockStoreInterfaceMockAttributeAddToSet.RLock()
+	calls = mock.calls.AttributeAddToSet
+	lockStoreInterfaceMockAttributeAddToSet.RUnlock()
+	return calls
+}
+
+// AttributeAppendToList calls AttributeAppendToListFunc.
+func (mock *StoreInterfaceMock) AttributeAppendToList(entityID string, attributeKey string, values ...string) error {
+	if mock.AttributeAppendToListFunc == nil {
+		panic("StoreInterfaceMock.AttributeAppendToListFunc: method is nil but StoreInterface.AttributeAppendToList was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+		Values       []string
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+		Values:       values,
+	}
+	lockStoreInterfaceMockAttributeAppendToList.Lock()
+	mock.calls.AttributeAppendToList = append(mock.calls.AttributeAppendToList, callInfo)
+	lockStoreInterfaceMockAttributeAppendToList.Unlock()
+	return mock.AttributeAppendToListFunc(entityID, attributeKey, values...)
+}
+
+// AttributeAppendToListCalls gets all the calls that were made to AttributeAppendToList.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeAppendToListCalls())
+func (mock *StoreInterfaceMock) AttributeAppendToListCalls() []struct {
+	EntityID     string
+	AttributeKey string
+	Values       []string
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+		Values       []string
+	}
+	lockStoreInterfaceMockAttributeAppendToList.RLock()
+	calls = mock.calls.AttributeAppendToList
+	lockStoreInterfaceMockAttributeAppendToList.RUnlock()
+	return calls
+}
+
+// AttributeCreate calls AttributeCreateFunc.
+func (mock *StoreInterfaceMock) AttributeCreate(entityID string, attributeKey string, attributeValue string) (*entitystore.Attribute, error) {
+	if mock.AttributeCreateFunc == nil {
+		panic("StoreInterfaceMock.AttributeCreateFunc: method is nil but StoreInterface.AttributeCreate was just called")
+	}
+	callInfo := struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue string
+	}{
+		EntityID:       entityID,
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockStoreInterfaceMockAttributeCreate.Lock()
+	mock.calls.AttributeCreate = append(mock.calls.AttributeCreate, callInfo)
+	lockStoreInterfaceMockAttributeCreate.Unlock()
+	return mock.AttributeCreateFunc(entityID, attributeKey, attributeValue)
+}
+
+// AttributeCreateCalls gets all the calls that were made to AttributeCreate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeCreateCalls())
+func (mock *StoreInterfaceMock) AttributeCreateCalls() []struct {
+	EntityID       string
+	AttributeKey   string
+	AttributeValue string
+} {
+	var calls []struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue string
+	}
+	lockStoreInterfaceMockAttributeCreate.RLock()
+	calls = mock.calls.AttributeCreate
+	lockStoreInterfaceMockAttributeCreate.RUnlock()
+	return calls
+}
+
+// AttributeDistinctValues calls AttributeDistinctValuesFunc.
+func (mock *StoreInterfaceMock) AttributeDistinctValues(entityType string, attributeKey string, filter map[string]string) ([]string, error) {
+	if mock.AttributeDistinctValuesFunc == nil {
+		panic("StoreInterfaceMock.AttributeDistinctValuesFunc: method is nil but StoreInterface.AttributeDistinctValues was just called")
+	}
+	callInfo := struct {
+		EntityType   string
+		AttributeKey string
+		Filter       map[string]string
+	}{
+		EntityType:   entityType,
+		AttributeKey: attributeKey,
+		Filter:       filter,
+	}
+	lockStoreInterfaceMockAttributeDistinctValues.Lock()
+	mock.calls.AttributeDistinctValues = append(mock.calls.AttributeDistinctValues, callInfo)
+	lockStoreInterfaceMockAttributeDistinctValues.Unlock()
+	return mock.AttributeDistinctValuesFunc(entityType, attributeKey, filter)
+}
+
+// AttributeDistinctValuesCalls gets all the calls that were made to AttributeDistinctValues.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeDistinctValuesCalls())
+func (mock *StoreInterfaceMock) AttributeDistinctValuesCalls() []struct {
+	EntityType   string
+	AttributeKey string
+	Filter       map[string]string
+} {
+	var calls []struct {
+		EntityType   string
+		AttributeKey string
+		Filter       map[string]string
+	}
+	lockStoreInterfaceMockAttributeDistinctValues.RLock()
+	calls = mock.calls.AttributeDistinctValues
+	lockStoreInterfaceMockAttributeDistinctValues.RUnlock()
+	return calls
+}
+
+// AttributeFind calls AttributeFindFunc.
+func (mock *StoreInterfaceMock) AttributeFind(entityID string, attributeKey string, options ...entitystore.FindOptions) (*entitystore.Attribute, error) {
+	if mock.AttributeFindFunc == nil {
+		panic("StoreInterfaceMock.AttributeFindFunc: method is nil but StoreInterface.AttributeFind was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+		Options      []entitystore.FindOptions
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+		Options:      options,
+	}
+	lockStoreInterfaceMockAttributeFind.Lock()
+	mock.calls.AttributeFind = append(mock.calls.AttributeFind, callInfo)
+	lockStoreInterfaceMockAttributeFind.Unlock()
+	return mock.AttributeFindFunc(entityID, attributeKey, options...)
+}
+
+// AttributeFindCalls gets all the calls that were made to AttributeFind.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeFindCalls())
+func (mock *StoreInterfaceMock) AttributeFindCalls() []struct {
+	EntityID     string
+	AttributeKey string
+	Options      []entitystore.FindOptions
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+		Options      []entitystore.FindOptions
+	}
+	lockStoreInterfaceMockAttributeFind.RLock()
+	calls = mock.calls.AttributeFind
+	lockStoreInterfaceMockAttributeFind.RUnlock()
+	return calls
+}
+
+// AttributeIncrement calls AttributeIncrementFunc.
+func (mock *StoreInterfaceMock) AttributeIncrement(entityID string, attributeKey string, delta int64) (int64, error) {
+	if mock.AttributeIncrementFunc == nil {
+		panic("StoreInterfaceMock.AttributeIncrementFunc: method is nil but StoreInterface.AttributeIncrement was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+		Delta        int64
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+		Delta:        delta,
+	}
+	lockStoreInterfaceMockAttributeIncrement.Lock()
+	mock.calls.AttributeIncrement = append(mock.calls.AttributeIncrement, callInfo)
+	lockStoreInterfaceMockAttributeIncrement.Unlock()
+	return mock.AttributeIncrementFunc(entityID, attributeKey, delta)
+}
+
+// AttributeIncrementCalls gets all the calls that were made to AttributeIncrement.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeIncrementCalls())
+func (mock *StoreInterfaceMock) AttributeIncrementCalls() []struct {
+	EntityID     string
+	AttributeKey string
+	Delta        int64
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+		Delta        int64
+	}
+	lockStoreInterfaceMockAttributeIncrement.RLock()
+	calls = mock.calls.AttributeIncrement
+	lockStoreInterfaceMockAttributeIncrement.RUnlock()
+	return calls
+}
+
+// AttributeIncrementFloat calls AttributeIncrementFloatFunc.
+func (mock *StoreInterfaceMock) AttributeIncrementFloat(entityID string, attributeKey string, delta float64) (float64, error) {
+	if mock.AttributeIncrementFloatFunc == nil {
+		panic("StoreInterfaceMock.AttributeIncrementFloatFunc: method is nil but StoreInterface.AttributeIncrementFloat was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+		Delta        float64
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+		Delta:        delta,
+	}
+	lockStoreInterfaceMockAttributeIncrementFloat.Lock()
+	mock.calls.AttributeIncrementFloat = append(mock.calls.AttributeIncrementFloat, callInfo)
+	lockStoreInterfaceMockAttributeIncrementFloat.Unlock()
+	return mock.AttributeIncrementFloatFunc(entityID, attributeKey, delta)
+}
+
+// AttributeIncrementFloatCalls gets all the calls that were made to AttributeIncrementFloat.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeIncrementFloatCalls())
+func (mock *StoreInterfaceMock) AttributeIncrementFloatCalls() []struct {
+	EntityID     string
+	AttributeKey string
+	Delta        float64
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+		Delta        float64
+	}
+	lockStoreInterfaceMockAttributeIncrementFloat.RLock()
+	calls = mock.calls.AttributeIncrementFloat
+	lockStoreInterfaceMockAttributeIncrementFloat.RUnlock()
+	return calls
+}
+
+// AttributeInsert calls AttributeInsertFunc.
+func (mock *StoreInterfaceMock) AttributeInsert(attr entitystore.Attribute) (*entitystore.Attribute, error) {
+	if mock.AttributeInsertFunc == nil {
+		panic("StoreInterfaceMock.AttributeInsertFunc: method is nil but StoreInterface.AttributeInsert was just called")
+	}
+	callInfo := struct {
+		Attr entitystore.Attribute
+	}{
+		Attr: attr,
+	}
+	lockStoreInterfaceMockAttributeInsert.Lock()
+	mock.calls.AttributeInsert = append(mock.calls.AttributeInsert, callInfo)
+	lockStoreInterfaceMockAttributeInsert.Unlock()
+	return mock.AttributeInsertFunc(attr)
+}
+
+// AttributeInsertCalls gets all the calls that were made to AttributeInsert.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeInsertCalls())
+func (mock *StoreInterfaceMock) AttributeInsertCalls() []struct {
+	Attr entitystore.Attribute
+} {
+	var calls []struct {
+		Attr entitystore.Attribute
+	}
+	lockStoreInterfaceMockAttributeInsert.RLock()
+	calls = mock.calls.AttributeInsert
+	lockStoreInterfaceMockAttributeInsert.RUnlock()
+	return calls
+}
+
+// AttributeKeys calls AttributeKeysFunc.
+func (mock *StoreInterfaceMock) AttributeKeys(entityType string) ([]entitystore.AttributeKeyInfo, error) {
+	if mock.AttributeKeysFunc == nil {
+		panic("StoreInterfaceMock.AttributeKeysFunc: method is nil but StoreInterface.AttributeKeys was just called")
+	}
+	callInfo := struct {
+		EntityType string
+	}{
+		EntityType: entityType,
+	}
+	lockStoreInterfaceMockAttributeKeys.Lock()
+	mock.calls.AttributeKeys = append(mock.calls.AttributeKeys, callInfo)
+	lockStoreInterfaceMockAttributeKeys.Unlock()
+	return mock.AttributeKeysFunc(entityType)
+}
+
+// AttributeKeysCalls gets all the calls that were made to AttributeKeys.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeKeysCalls())
+func (mock *StoreInterfaceMock) AttributeKeysCalls() []struct {
+	EntityType string
+} {
+	var calls []struct {
+		EntityType string
+	}
+	lockStoreInterfaceMockAttributeKeys.RLock()
+	calls = mock.calls.AttributeKeys
+	lockStoreInterfaceMockAttributeKeys.RUnlock()
+	return calls
+}
+
+// AttributeList calls AttributeListFunc.
+func (mock *StoreInterfaceMock) AttributeList(options entitystore.AttributeQueryOptions) ([]entitystore.Attribute, error) {
+	if mock.AttributeListFunc == nil {
+		panic("StoreInterfaceMock.AttributeListFunc: method is nil but StoreInterface.AttributeList was just called")
+	}
+	callInfo := struct {
+		Options entitystore.AttributeQueryOptions
+	}{
+		Options: options,
+	}
+	lockStoreInterfaceMockAttributeList.Lock()
+	mock.calls.AttributeList = append(mock.calls.AttributeList, callInfo)
+	lockStoreInterfaceMockAttributeList.Unlock()
+	return mock.AttributeListFunc(options)
+}
+
+// AttributeListCalls gets all the calls that were made to AttributeList.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeListCalls())
+func (mock *StoreInterfaceMock) AttributeListCalls() []struct {
+	Options entitystore.AttributeQueryOptions
+} {
+	var calls []struct {
+		Options entitystore.AttributeQueryOptions
+	}
+	lockStoreInterfaceMockAttributeList.RLock()
+	calls = mock.calls.AttributeList
+	lockStoreInterfaceMockAttributeList.RUnlock()
+	return calls
+}
+
+// AttributeQuery calls AttributeQueryFunc.
+func (mock *StoreInterfaceMock) AttributeQuery(options entitystore.AttributeQueryOptions) *goqu.SelectDataset {
+	if mock.AttributeQueryFunc == nil {
+		panic("StoreInterfaceMock.AttributeQueryFunc: method is nil but StoreInterface.AttributeQuery was just called")
+	}
+	callInfo := struct {
+		Options entitystore.AttributeQueryOptions
+	}{
+		Options: options,
+	}
+	lockStoreInterfaceMockAttributeQuery.Lock()
+	mock.calls.AttributeQuery = append(mock.calls.AttributeQuery, callInfo)
+	lockStoreInterfaceMockAttributeQuery.Unlock()
+	return mock.AttributeQueryFunc(options)
+}
+
+// AttributeQueryCalls gets all the calls that were made to AttributeQuery.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeQueryCalls())
+func (mock *StoreInterfaceMock) AttributeQueryCalls() []struct {
+	Options entitystore.AttributeQueryOptions
+} {
+	var calls []struct {
+		Options entitystore.AttributeQueryOptions
+	}
+	lockStoreInterfaceMockAttributeQuery.RLock()
+	calls = mock.calls.AttributeQuery
+	lockStoreInterfaceMockAttributeQuery.RUnlock()
+	return calls
+}
+
+// AttributeRemoveFromSet calls AttributeRemoveFromSetFunc.
+func (mock *StoreInterfaceMock) AttributeRemoveFromSet(entityID string, attributeKey string, values ...string) error {
+	if mock.AttributeRemoveFromSetFunc == nil {
+		panic("StoreInterfaceMock.AttributeRemoveFromSetFunc: method is nil but StoreInterface.AttributeRemoveFromSet was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+		Values       []string
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+		Values:       values,
+	}
+	lockStoreInterfaceMockAttributeRemoveFromSet.Lock()
+	mock.calls.AttributeRemoveFromSet = append(mock.calls.AttributeRemoveFromSet, callInfo)
+	lockStoreInterfaceMockAttributeRemoveFromSet.Unlock()
+	return mock.AttributeRemoveFromSetFunc(entityID, attributeKey, values...)
+}
+
+// AttributeRemoveFromSetCalls gets all the calls that were made to AttributeRemoveFromSet.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeRemoveFromSetCalls())
+func (mock *StoreInterfaceMock) AttributeRemoveFromSetCalls() []struct {
+	EntityID     string
+	AttributeKey string
+	Values       []string
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+		Values       []string
+	}
+	lockStoreInterfaceMockAttributeRemoveFromSet.RLock()
+	calls = mock.calls.AttributeRemoveFromSet
+	lockStoreInterfaceMockAttributeRemoveFromSet.RUnlock()
+	return calls
+}
+
+// AttributeSetFloat calls AttributeSetFloatFunc.
+func (mock *StoreInterfaceMock) AttributeSetFloat(entityID string, attributeKey string, attributeValue float64) error {
+	if mock.AttributeSetFloatFunc == nil {
+		panic("StoreInterfaceMock.AttributeSetFloatFunc: method is nil but StoreInterface.AttributeSetFloat was just called")
+	}
+	callInfo := struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue float64
+	}{
+		EntityID:       entityID,
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockStoreInterfaceMockAttributeSetFloat.Lock()
+	mock.calls.AttributeSetFloat = append(mock.calls.AttributeSetFloat, callInfo)
+	lockStoreInterfaceMockAttributeSetFloat.Unlock()
+	return mock.AttributeSetFloatFunc(entityID, attributeKey, attributeValue)
+}
+
+// AttributeSetFloatCalls gets all the calls that were made to AttributeSetFloat.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeSetFloatCalls())
+func (mock *StoreInterfaceMock) AttributeSetFloatCalls() []struct {
+	EntityID       string
+	AttributeKey   string
+	AttributeValue float64
+} {
+	var calls []struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue float64
+	}
+	lockStoreInterfaceMockAttributeSetFloat.RLock()
+	calls = mock.calls.AttributeSetFloat
+	lockStoreInterfaceMockAttributeSetFloat.RUnlock()
+	return calls
+}
+
+// AttributeSetInt calls AttributeSetIntFunc.
+func (mock *StoreInterfaceMock) AttributeSetInt(entityID string, attributeKey string, attributeValue int64) error {
+	if mock.AttributeSetIntFunc == nil {
+		panic("StoreInterfaceMock.AttributeSetIntFunc: method is nil but StoreInterface.AttributeSetInt was just called")
+	}
+	callInfo := struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue int64
+	}{
+		EntityID:       entityID,
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockStoreInterfaceMockAttributeSetInt.Lock()
+	mock.calls.AttributeSetInt = append(mock.calls.AttributeSetInt, callInfo)
+	lockStoreInterfaceMockAttributeSetInt.Unlock()
+	return mock.AttributeSetIntFunc(entityID, attributeKey, attributeValue)
+}
+
+// AttributeSetIntCalls gets all the calls that were made to AttributeSetInt.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeSetIntCalls())
+func (mock *StoreInterfaceMock) AttributeSetIntCalls() []struct {
+	EntityID       string
+	AttributeKey   string
+	AttributeValue int64
+} {
+	var calls []struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue int64
+	}
+	lockStoreInterfaceMockAttributeSetInt.RLock()
+	calls = mock.calls.AttributeSetInt
+	lockStoreInterfaceMockAttributeSetInt.RUnlock()
+	return calls
+}
+
+// AttributeSetString calls AttributeSetStringFunc.
+func (mock *StoreInterfaceMock) AttributeSetString(entityID string, attributeKey string, attributeValue string) error {
+	if mock.AttributeSetStringFunc == nil {
+		panic("StoreInterfaceMock.AttributeSetStringFunc: method is nil but StoreInterface.AttributeSetString was just called")
+	}
+	callInfo := struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue string
+	}{
+		EntityID:       entityID,
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockStoreInterfaceMockAttributeSetString.Lock()
+	mock.calls.AttributeSetString = append(mock.calls.AttributeSetString, callInfo)
+	lockStoreInterfaceMockAttributeSetString.Unlock()
+	return mock.AttributeSetStringFunc(entityID, attributeKey, attributeValue)
+}
+
+// AttributeSetStringCalls gets all the calls that were made to AttributeSetString.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeSetStringCalls())
+func (mock *StoreInterfaceMock) AttributeSetStringCalls() []struct {
+	EntityID       string
+	AttributeKey   string
+	AttributeValue string
+} {
+	var calls []struct {
+		EntityID       string
+		AttributeKey   string
+		AttributeValue string
+	}
+	lockStoreInterfaceMockAttributeSetString.RLock()
+	calls = mock.calls.AttributeSetString
+	lockStoreInterfaceMockAttributeSetString.RUnlock()
+	return calls
+}
+
+// AttributeUpdate calls AttributeUpdateFunc.
+func (mock *StoreInterfaceMock) AttributeUpdate(attr entitystore.Attribute) error {
+	if mock.AttributeUpdateFunc == nil {
+		panic("StoreInterfaceMock.AttributeUpdateFunc: method is nil but StoreInterface.AttributeUpdate was just called")
+	}
+	callInfo := struct {
+		Attr entitystore.Attribute
+	}{
+		Attr: attr,
+	}
+	lockStoreInterfaceMockAttributeUpdate.Lock()
+	mock.calls.AttributeUpdate = append(mock.calls.AttributeUpdate, callInfo)
+	lockStoreInterfaceMockAttributeUpdate.Unlock()
+	return mock.AttributeUpdateFunc(attr)
+}
+
+// AttributeUpdateCalls gets all the calls that were made to AttributeUpdate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeUpdateCalls())
+func (mock *StoreInterfaceMock) AttributeUpdateCalls() []struct {
+	Attr entitystore.Attribute
+} {
+	var calls []struct {
+		Attr entitystore.Attribute
+	}
+	lockStoreInterfaceMockAttributeUpdate.RLock()
+	calls = mock.calls.AttributeUpdate
+	lockStoreInterfaceMockAttributeUpdate.RUnlock()
+	return calls
+}
+
+// AttributeValues calls AttributeValuesFunc.
+func (mock *StoreInterfaceMock) AttributeValues(entityID string, attributeKey string) ([]string, error) {
+	if mock.AttributeValuesFunc == nil {
+		panic("StoreInterfaceMock.AttributeValuesFunc: method is nil but StoreInterface.AttributeValues was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		AttributeKey string
+	}{
+		EntityID:     entityID,
+		AttributeKey: attributeKey,
+	}
+	lockStoreInterfaceMockAttributeValues.Lock()
+	mock.calls.AttributeValues = append(mock.calls.AttributeValues, callInfo)
+	lockStoreInterfaceMockAttributeValues.Unlock()
+	return mock.AttributeValuesFunc(entityID, attributeKey)
+}
+
+// AttributeValuesCalls gets all the calls that were made to AttributeValues.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributeValuesCalls())
+func (mock *StoreInterfaceMock) AttributeValuesCalls() []struct {
+	EntityID     string
+	AttributeKey string
+} {
+	var calls []struct {
+		EntityID     string
+		AttributeKey string
+	}
+	lockStoreInterfaceMockAttributeValues.RLock()
+	calls = mock.calls.AttributeValues
+	lockStoreInterfaceMockAttributeValues.RUnlock()
+	return calls
+}
+
+// AttributesSet calls AttributesSetFunc.
+func (mock *StoreInterfaceMock) AttributesSet(entityID string, attributes map[string]string) error {
+	if mock.AttributesSetFunc == nil {
+		panic("StoreInterfaceMock.AttributesSetFunc: method is nil but StoreInterface.AttributesSet was just called")
+	}
+	callInfo := struct {
+		EntityID   string
+		Attributes map[string]string
+	}{
+		EntityID:   entityID,
+		Attributes: attributes,
+	}
+	lockStoreInterfaceMockAttributesSet.Lock()
+	mock.calls.AttributesSet = append(mock.calls.AttributesSet, callInfo)
+	lockStoreInterfaceMockAttributesSet.Unlock()
+	return mock.AttributesSetFunc(entityID, attributes)
+}
+
+// AttributesSetCalls gets all the calls that were made to AttributesSet.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributesSetCalls())
+func (mock *StoreInterfaceMock) AttributesSetCalls() []struct {
+	EntityID   string
+	Attributes map[string]string
+} {
+	var calls []struct {
+		EntityID   string
+		Attributes map[string]string
+	}
+	lockStoreInterfaceMockAttributesSet.RLock()
+	calls = mock.calls.AttributesSet
+	lockStoreInterfaceMockAttributesSet.RUnlock()
+	return calls
+}
+
+// AttributesSetIfVersion calls AttributesSetIfVersionFunc.
+func (mock *StoreInterfaceMock) AttributesSetIfVersion(entityID string, version int64, attributes map[string]string) error {
+	if mock.AttributesSetIfVersionFunc == nil {
+		panic("StoreInterfaceMock.AttributesSetIfVersionFunc: method is nil but StoreInterface.AttributesSetIfVersion was just called")
+	}
+	callInfo := struct {
+		EntityID   string
+		Version    int64
+		Attributes map[string]string
+	}{
+		EntityID:   entityID,
+		Version:    version,
+		Attributes: attributes,
+	}
+	lockStoreInterfaceMockAttributesSetIfVersion.Lock()
+	mock.calls.AttributesSetIfVersion = append(mock.calls.AttributesSetIfVersion, callInfo)
+	lockStoreInterfaceMockAttributesSetIfVersion.Unlock()
+	return mock.AttributesSetIfVersionFunc(entityID, version, attributes)
+}
+
+// AttributesSetIfVersionCalls gets all the calls that were made to AttributesSetIfVersion.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AttributesSetIfVersionCalls())
+func (mock *StoreInterfaceMock) AttributesSetIfVersionCalls() []struct {
+	EntityID   string
+	Version    int64
+	Attributes map[string]string
+} {
+	var calls []struct {
+		EntityID   string
+		Version    int64
+		Attributes map[string]string
+	}
+	lockStoreInterfaceMockAttributesSetIfVersion.RLock()
+	calls = mock.calls.AttributesSetIfVersion
+	lockStoreInterfaceMockAttributesSetIfVersion.RUnlock()
+	return calls
+}
+
+// AutoMigrate calls AutoMigrateFunc.
+func (mock *StoreInterfaceMock) AutoMigrate() error {
+	if mock.AutoMigrateFunc == nil {
+		panic("StoreInterfaceMock.AutoMigrateFunc: method is nil but StoreInterface.AutoMigrate was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockAutoMigrate.Lock()
+	mock.calls.AutoMigrate = append(mock.calls.AutoMigrate, callInfo)
+	lockStoreInterfaceMockAutoMigrate.Unlock()
+	return mock.AutoMigrateFunc()
+}
+
+// AutoMigrateCalls gets all the calls that were made to AutoMigrate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.AutoMigrateCalls())
+func (mock *StoreInterfaceMock) AutoMigrateCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockAutoMigrate.RLock()
+	calls = mock.calls.AutoMigrate
+	lockStoreInterfaceMockAutoMigrate.RUnlock()
+	return calls
+}
+
+// Backup calls BackupFunc.
+func (mock *StoreInterfaceMock) Backup(writer io.Writer) (*entitystore.BackupManifest, error) {
+	if mock.BackupFunc == nil {
+		panic("StoreInterfaceMock.BackupFunc: method is nil but StoreInterface.Backup was just called")
+	}
+	callInfo := struct {
+		Writer io.Writer
+	}{
+		Writer: writer,
+	}
+	lockStoreInterfaceMockBackup.Lock()
+	mock.calls.Backup = append(mock.calls.Backup, callInfo)
+	lockStoreInterfaceMockBackup.Unlock()
+	return mock.BackupFunc(writer)
+}
+
+// BackupCalls gets all the calls that were made to Backup.
+// Check the length with:
+//
+//	len(mockedStoreInterface.BackupCalls())
+func (mock *StoreInterfaceMock) BackupCalls() []struct {
+	Writer io.Writer
+} {
+	var calls []struct {
+		Writer io.Writer
+	}
+	lockStoreInterfaceMockBackup.RLock()
+	calls = mock.calls.Backup
+	lockStoreInterfaceMockBackup.RUnlock()
+	return calls
+}
+
+// CacheStats calls CacheStatsFunc.
+func (mock *StoreInterfaceMock) CacheStats() entitystore.CacheStats {
+	if mock.CacheStatsFunc == nil {
+		panic("StoreInterfaceMock.CacheStatsFunc: method is nil but StoreInterface.CacheStats was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockCacheStats.Lock()
+	mock.calls.CacheStats = append(mock.calls.CacheStats, callInfo)
+	lockStoreInterfaceMockCacheStats.Unlock()
+	return mock.CacheStatsFunc()
+}
+
+// CacheStatsCalls gets all the calls that were made to CacheStats.
+// Check the length with:
+//
+//	len(mockedStoreInterface.CacheStatsCalls())
+func (mock *StoreInterfaceMock) CacheStatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockCacheStats.RLock()
+	calls = mock.calls.CacheStats
+	lockStoreInterfaceMockCacheStats.RUnlock()
+	return calls
+}
+
+// ChangeFeed calls ChangeFeedFunc.
+func (mock *StoreInterfaceMock) ChangeFeed(consumer string) (*entitystore.ChangeFeed, error) {
+	if mock.ChangeFeedFunc == nil {
+		panic("StoreInterfaceMock.ChangeFeedFunc: method is nil but StoreInterface.ChangeFeed was just called")
+	}
+	callInfo := struct {
+		Consumer string
+	}{
+		Consumer: consumer,
+	}
+	lockStoreInterfaceMockChangeFeed.Lock()
+	mock.calls.ChangeFeed = append(mock.calls.ChangeFeed, callInfo)
+	lockStoreInterfaceMockChangeFeed.Unlock()
+	return mock.ChangeFeedFunc(consumer)
+}
+
+// ChangeFeedCalls gets all the calls that were made to ChangeFeed.
+// Check the length with:
+//
+//	len(mockedStoreInterface.ChangeFeedCalls())
+func (mock *StoreInterfaceMock) ChangeFeedCalls() []struct {
+	Consumer string
+} {
+	var calls []struct {
+		Consumer string
+	}
+	lockStoreInterfaceMockChangeFeed.RLock()
+	calls = mock.calls.ChangeFeed
+	lockStoreInterfaceMockChangeFeed.RUnlock()
+	return calls
+}
+
+// ChangeFeedPrune calls ChangeFeedPruneFunc.
+func (mock *StoreInterfaceMock) ChangeFeedPrune() (int64, error) {
+	if mock.ChangeFeedPruneFunc == nil {
+		panic("StoreInterfaceMock.ChangeFeedPruneFunc: method is nil but StoreInterface.ChangeFeedPrune was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockChangeFeedPrune.Lock()
+	mock.calls.ChangeFeedPrune = append(mock.calls.ChangeFeedPrune, callInfo)
+	lockStoreInterfaceMockChangeFeedPrune.Unlock()
+	return mock.ChangeFeedPruneFunc()
+}
+
+// ChangeFeedPruneCalls gets all the calls that were made to ChangeFeedPrune.
+// Check the length with:
+//
+//	len(mockedStoreInterface.ChangeFeedPruneCalls())
+func (mock *StoreInterfaceMock) ChangeFeedPruneCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockChangeFeedPrune.RLock()
+	calls = mock.calls.ChangeFeedPrune
+	lockStoreInterfaceMockChangeFeedPrune.RUnlock()
+	return calls
+}
+
+// Close calls CloseFunc.
+func (mock *StoreInterfaceMock) Close() error {
+	if mock.CloseFunc == nil {
+		panic("StoreInterfaceMock.CloseFunc: method is nil but StoreInterface.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	lockStoreInterfaceMockClose.Unlock()
+	return mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//
+//	len(mockedStoreInterface.CloseCalls())
+func (mock *StoreInterfaceMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockClose.RLock()
+	calls = mock.calls.Close
+	lockStoreInterfaceMockClose.RUnlock()
+	return calls
+}
+
+// CopyTo calls CopyToFunc.
+func (mock *StoreInterfaceMock) CopyTo(dst entitystore.StoreInterface, filter entitystore.EntityQueryOptions, options entitystore.CopyOptions) (*entitystore.CopyReport, error) {
+	if mock.CopyToFunc == nil {
+		panic("StoreInterfaceMock.CopyToFunc: method is nil but StoreInterface.CopyTo was just called")
+	}
+	callInfo := struct {
+		Dst     entitystore.StoreInterface
+		Filter  entitystore.EntityQueryOptions
+		Options entitystore.CopyOptions
+	}{
+		Dst:     dst,
+		Filter:  filter,
+		Options: options,
+	}
+	lockStoreInterfaceMockCopyTo.Lock()
+	mock.calls.CopyTo = append(mock.calls.CopyTo, callInfo)
+	lockStoreInterfaceMockCopyTo.Unlock()
+	return mock.CopyToFunc(dst, filter, options)
+}
+
+// CopyToCalls gets all the calls that were made to CopyTo.
+// Check the length with:
+//
+//	len(mockedStoreInterface.CopyToCalls())
+func (mock *StoreInterfaceMock) CopyToCalls() []struct {
+	Dst     entitystore.StoreInterface
+	Filter  entitystore.EntityQueryOptions
+	Options entitystore.CopyOptions
+} {
+	var calls []struct {
+		Dst     entitystore.StoreInterface
+		Filter  entitystore.EntityQueryOptions
+		Options entitystore.CopyOptions
+	}
+	lockStoreInterfaceMockCopyTo.RLock()
+	calls = mock.calls.CopyTo
+	lockStoreInterfaceMockCopyTo.RUnlock()
+	return calls
+}
+
+// EnableDebug calls EnableDebugFunc.
+func (mock *StoreInterfaceMock) EnableDebug(debug bool) {
+	if mock.EnableDebugFunc == nil {
+		panic("StoreInterfaceMock.EnableDebugFunc: method is nil but StoreInterface.EnableDebug was just called")
+	}
+	callInfo := struct {
+		Debug bool
+	}{
+		Debug: debug,
+	}
+	lockStoreInterfaceMockEnableDebug.Lock()
+	mock.calls.EnableDebug = append(mock.calls.EnableDebug, callInfo)
+	lockStoreInterfaceMockEnableDebug.Unlock()
+	mock.EnableDebugFunc(debug)
+}
+
+// EnableDebugCalls gets all the calls that were made to EnableDebug.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EnableDebugCalls())
+func (mock *StoreInterfaceMock) EnableDebugCalls() []struct {
+	Debug bool
+} {
+	var calls []struct {
+		Debug bool
+	}
+	lockStoreInterfaceMockEnableDebug.RLock()
+	calls = mock.calls.EnableDebug
+	lockStoreInterfaceMockEnableDebug.RUnlock()
+	return calls
+}
+
+// EntityAttributeList calls EntityAttributeListFunc.
+func (mock *StoreInterfaceMock) EntityAttributeList(entityID string) ([]entitystore.Attribute, error) {
+	if mock.EntityAttributeListFunc == nil {
+		panic("StoreInterfaceMock.EntityAttributeListFunc: method is nil but StoreInterface.EntityAttributeList was just called")
+	}
+	callInfo := struct {
+		EntityID string
+	}{
+		EntityID: entityID,
+	}
+	lockStoreInterfaceMockEntityAttributeList.Lock()
+	mock.calls.EntityAttributeList = append(mock.calls.EntityAttributeList, callInfo)
+	lockStoreInterfaceMockEntityAttributeList.Unlock()
+	return mock.EntityAttributeListFunc(entityID)
+}
+
+// EntityAttributeListCalls gets all the calls that were made to EntityAttributeList.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityAttributeListCalls())
+func (mock *StoreInterfaceMock) EntityAttributeListCalls() []struct {
+	EntityID string
+} {
+	var calls []struct {
+		EntityID string
+	}
+	lockStoreInterfaceMockEntityAttributeList.RLock()
+	calls = mock.calls.EntityAttributeList
+	lockStoreInterfaceMockEntityAttributeList.RUnlock()
+	return calls
+}
+
+// EntityCount calls EntityCountFunc.
+func (mock *StoreInterfaceMock) EntityCount(options entitystore.EntityQueryOptions) (int64, error) {
+	if mock.EntityCountFunc == nil {
+		panic("StoreInterfaceMock.EntityCountFunc: method is nil but StoreInterface.EntityCount was just called")
+	}
+	callInfo := struct {
+		Options entitystore.EntityQueryOptions
+	}{
+		Options: options,
+	}
+	lockStoreInterfaceMockEntityCount.Lock()
+	mock.calls.EntityCount = append(mock.calls.EntityCount, callInfo)
+	lockStoreInterfaceMockEntityCount.Unlock()
+	return mock.EntityCountFunc(options)
+}
+
+// EntityCountCalls gets all the calls that were made to EntityCount.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityCountCalls())
+func (mock *StoreInterfaceMock) EntityCountCalls() []struct {
+	Options entitystore.EntityQueryOptions
+} {
+	var calls []struct {
+		Options entitystore.EntityQueryOptions
+	}
+	lockStoreInterfaceMockEntityCount.RLock()
+	calls = mock.calls.EntityCount
+	lockStoreInterfaceMockEntityCount.RUnlock()
+	return calls
+}
+
+// EntityCreate calls EntityCreateFunc.
+func (mock *StoreInterfaceMock) EntityCreate(entityType string) (*entitystore.Entity, error) {
+	if mock.EntityCreateFunc == nil {
+		panic("StoreInterfaceMock.EntityCreateFunc: method is nil but StoreInterface.EntityCreate was just called")
+	}
+	callInfo := struct {
+		EntityType string
+	}{
+		EntityType: entityType,
+	}
+	lockStoreInterfaceMockEntityCreate.Lock()
+	mock.calls.EntityCreate = append(mock.calls.EntityCreate, callInfo)
+	lockStoreInterfaceMockEntityCreate.Unlock()
+	return mock.EntityCreateFunc(entityType)
+}
+
+// EntityCreateCalls gets all the calls that were made to EntityCreate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityCreateCalls())
+func (mock *StoreInterfaceMock) EntityCreateCalls() []struct {
+	EntityType string
+} {
+	var calls []struct {
+		EntityType string
+	}
+	lockStoreInterfaceMockEntityCreate.RLock()
+	calls = mock.calls.EntityCreate
+	lockStoreInterfaceMockEntityCreate.RUnlock()
+	return calls
+}
+
+// EntityCreateWithAttributes calls EntityCreateWithAttributesFunc.
+func (mock *StoreInterfaceMock) EntityCreateWithAttributes(entityType string, attributes map[string]string) (*entitystore.Entity, error) {
+	if mock.EntityCreateWithAttributesFunc == nil {
+		panic("StoreInterfaceMock.EntityCreateWithAttributesFunc: method is nil but StoreInterface.EntityCreateWithAttributes was just called")
+	}
+	callInfo := struct {
+		EntityType string
+		Attributes map[string]string
+	}{
+		EntityType: entityType,
+		Attributes: attributes,
+	}
+	lockStoreInterfaceMockEntityCreateWithAttributes.Lock()
+	mock.calls.EntityCreateWithAttributes = append(mock.calls.EntityCreateWithAttributes, callInfo)
+	lockStoreInterfaceMockEntityCreateWithAttributes.Unlock()
+	return mock.EntityCreateWithAttributesFunc(entityType, attributes)
+}
+
+// EntityCreateWithAttributesCalls gets all the calls that were made to EntityCreateWithAttributes.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityCreateWithAttributesCalls())
+func (mock *StoreInterfaceMock) EntityCreateWithAttributesCalls() []struct {
+	EntityType string
+	Attributes map[string]string
+} {
+	var calls []struct {
+		EntityType string
+		Attributes map[string]string
+	}
+	lockStoreInterfaceMockEntityCreateWithAttributes.RLock()
+	calls = mock.calls.EntityCreateWithAttributes
+	lockStoreInterfaceMockEntityCreateWithAttributes.RUnlock()
+	return calls
+}
+
+// EntityCreateWithHandle calls EntityCreateWithHandleFunc.
+func (mock *StoreInterfaceMock) EntityCreateWithHandle(entityType string, entityHandle string, attributes map[string]string) (*entitystore.Entity, error) {
+	if mock.EntityCreateWithHandleFunc == nil {
+		panic("StoreInterfaceMock.EntityCreateWithHandleFunc: method is nil but StoreInterface.EntityCreateWithHandle was just called")
+	}
+	callInfo := struct {
+		EntityType   string
+		EntityHandle string
+		Attributes   map[string]string
+	}{
+		EntityType:   entityType,
+		EntityHandle: entityHandle,
+		Attributes:   attributes,
+	}
+	lockStoreInterfaceMockEntityCreateWithHandle.Lock()
+	mock.calls.EntityCreateWithHandle = append(mock.calls.EntityCreateWithHandle, callInfo)
+	lockStoreInterfaceMockEntityCreateWithHandle.Unlock()
+	return mock.EntityCreateWithHandleFunc(entityType, entityHandle, attributes)
+}
+
+// EntityCreateWithHandleCalls gets all the calls that were made to EntityCreateWithHandle.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityCreateWithHandleCalls())
+func (mock *StoreInterfaceMock) EntityCreateWithHandleCalls() []struct {
+	EntityType   string
+	EntityHandle string
+	Attributes   map[string]string
+} {
+	var calls []struct {
+		EntityType   string
+		EntityHandle string
+		Attributes   map[string]string
+	}
+	lockStoreInterfaceMockEntityCreateWithHandle.RLock()
+	calls = mock.calls.EntityCreateWithHandle
+	lockStoreInterfaceMockEntityCreateWithHandle.RUnlock()
+	return calls
+}
+
+// EntityCursor calls EntityCursorFunc.
+func (mock *StoreInterfaceMock) EntityCursor(options entitystore.EntityQueryOptions, iterateOptions ...entitystore.IterateOptions) iter.Seq2[entitystore.EntityWithAttributes, error] {
+	if mock.EntityCursorFunc == nil {
+		panic("StoreInterfaceMock.EntityCursorFunc: method is nil but StoreInterface.EntityCursor was just called")
+	}
+	callInfo := struct {
+		Options        entitystore.EntityQueryOptions
+		IterateOptions []entitystore.IterateOptions
+	}{
+		Options:        options,
+		IterateOptions: iterateOptions,
+	}
+	lockStoreInterfaceMockEntityCursor.Lock()
+	mock.calls.EntityCursor = append(mock.calls.EntityCursor, callInfo)
+	lockStoreInterfaceMockEntityCursor.Unlock()
+	return mock.EntityCursorFunc(options, iterateOptions...)
+}
+
+// EntityCursorCalls gets all the calls that were made to EntityCursor.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityCursorCalls())
+func (mock *StoreInterfaceMock) EntityCursorCalls() []struct {
+	Options        entitystore.EntityQueryOptions
+	IterateOptions []entitystore.IterateOptions
+} {
+	var calls []struct {
+		Options        entitystore.EntityQueryOptions
+		IterateOptions []entitystore.IterateOptions
+	}
+	lockStoreInterfaceMockEntityCursor.RLock()
+	calls = mock.calls.EntityCursor
+	lockStoreInterfaceMockEntityCursor.RUnlock()
+	return calls
+}
+
+// EntityDelete calls EntityDeleteFunc.
+func (mock *StoreInterfaceMock) EntityDelete(entityID string) (bool, error) {
+	if mock.EntityDeleteFunc == nil {
+		panic("StoreInterfaceMock.EntityDeleteFunc: method is nil but StoreInterface.EntityDelete was just called")
+	}
+	callInfo := struct {
+		EntityID string
+	}{
+		EntityID: entityID,
+	}
+	lockStoreInterfaceMockEntityDelete.Lock()
+	mock.calls.EntityDelete = append(mock.calls.EntityDelete, callInfo)
+	lockStoreInterfaceMockEntityDelete.Unlock()
+	return mock.EntityDeleteFunc(entityID)
+}
+
+// EntityDeleteCalls gets all the calls that were made to EntityDelete.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityDeleteCalls())
+func (mock *StoreInterfaceMock) EntityDeleteCalls() []struct {
+	EntityID string
+} {
+	var calls []struct {
+		EntityID string
+	}
+	lockStoreInterfaceMockEntityDelete.RLock()
+	calls = mock.calls.EntityDelete
+	lockStoreInterfaceMockEntityDelete.RUnlock()
+	return calls
+}
+
+// EntityFindByAttribute calls EntityFindByAttributeFunc.
+func (mock *StoreInterfaceMock) EntityFindByAttribute(entityType string, attributeKey string, attributeValue string) (*entitystore.Entity, error) {
+	if mock.EntityFindByAttributeFunc == nil {
+		panic("StoreInterfaceMock.EntityFindByAttributeFunc: method is nil but StoreInterface.EntityFindByAttribute was just called")
+	}
+	callInfo := struct {
+		EntityType     string
+		AttributeKey   string
+		AttributeValue string
+	}{
+		EntityType:     entityType,
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockStoreInterfaceMockEntityFindByAttribute.Lock()
+	mock.calls.EntityFindByAttribute = append(mock.calls.EntityFindByAttribute, callInfo)
+	lockStoreInterfaceMockEntityFindByAttribute.Unlock()
+	return mock.EntityFindByAttributeFunc(entityType, attributeKey, attributeValue)
+}
+
+// EntityFindByAttributeCalls gets all the calls that were made to EntityFindByAttribute.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityFindByAttributeCalls())
+func (mock *StoreInterfaceMock) EntityFindByAttributeCalls() []struct {
+	EntityType     string
+	AttributeKey   string
+	AttributeValue string
+} {
+	var calls []struct {
+		EntityType     string
+		AttributeKey   string
+		AttributeValue string
+	}
+	lockStoreInterfaceMockEntityFindByAttribute.RLock()
+	calls = mock.calls.EntityFindByAttribute
+	lockStoreInterfaceMockEntityFindByAttribute.RUnlock()
+	return calls
+}
+
+// EntityFindByHandle calls EntityFindByHandleFunc.
+func (mock *StoreInterfaceMock) EntityFindByHandle(entityType string, entityHandle string, options ...entitystore.FindOptions) (*entitystore.Entity, error) {
+	if mock.EntityFindByHandleFunc == nil {
+		panic("StoreInterfaceMock.EntityFindByHandleFunc: method is nil but StoreInterface.EntityFindByHandle was just called")
+	}
+	callInfo := struct {
+		EntityType   string
+		EntityHandle string
+		Options      []entitystore.FindOptions
+	}{
+		EntityType:   entityType,
+		EntityHandle: entityHandle,
+		Options:      options,
+	}
+	lockStoreInterfaceMockEntityFindByHandle.Lock()
+	mock.calls.EntityFindByHandle = append(mock.calls.EntityFindByHandle, callInfo)
+	lockStoreInterfaceMockEntityFindByHandle.Unlock()
+	return mock.EntityFindByHandleFunc(entityType, entityHandle, options...)
+}
+
+// EntityFindByHandleCalls gets all the calls that were made to EntityFindByHandle.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityFindByHandleCalls())
+func (mock *StoreInterfaceMock) EntityFindByHandleCalls() []struct {
+	EntityType   string
+	EntityHandle string
+	Options      []entitystore.FindOptions
+} {
+	var calls []struct {
+		EntityType   string
+		EntityHandle string
+		Options      []entitystore.FindOptions
+	}
+	lockStoreInterfaceMockEntityFindByHandle.RLock()
+	calls = mock.calls.EntityFindByHandle
+	lockStoreInterfaceMockEntityFindByHandle.RUnlock()
+	return calls
+}
+
+// EntityFindByID calls EntityFindByIDFunc.
+func (mock *StoreInterfaceMock) EntityFindByID(entityID string, options ...entitystore.FindOptions) (*entitystore.Entity, error) {
+	if mock.EntityFindByIDFunc == nil {
+		panic("StoreInterfaceMock.EntityFindByIDFunc: method is nil but StoreInterface.EntityFindByID was just called")
+	}
+	callInfo := struct {
+		EntityID string
+		Options  []entitystore.FindOptions
+	}{
+		EntityID: entityID,
+		Options:  options,
+	}
+	lockStoreInterfaceMockEntityFindByID.Lock()
+	mock.calls.EntityFindByID = append(mock.calls.EntityFindByID, callInfo)
+	lockStoreInterfaceMockEntityFindByID.Unlock()
+	return mock.EntityFindByIDFunc(entityID, options...)
+}
+
+// EntityFindByIDCalls gets all the calls that were made to EntityFindByID.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityFindByIDCalls())
+func (mock *StoreInterfaceMock) EntityFindByIDCalls() []struct {
+	EntityID string
+	Options  []entitystore.FindOptions
+} {
+	var calls []struct {
+		EntityID string
+		Options  []entitystore.FindOptions
+	}
+	lockStoreInterfaceMockEntityFindByID.RLock()
+	calls = mock.calls.EntityFindByID
+	lockStoreInterfaceMockEntityFindByID.RUnlock()
+	return calls
+}
+
+// EntityHandleGenerate calls EntityHandleGenerateFunc.
+func (mock *StoreInterfaceMock) EntityHandleGenerate(entityType string, text string) (string, error) {
+	if mock.EntityHandleGenerateFunc == nil {
+		panic("StoreInterfaceMock.EntityHandleGenerateFunc: method is nil but StoreInterface.EntityHandleGenerate was just called")
+	}
+	callInfo := struct {
+		EntityType string
+		Text       string
+	}{
+		EntityType: entityType,
+		Text:       text,
+	}
+	lockStoreInterfaceMockEntityHandleGenerate.Lock()
+	mock.calls.EntityHandleGenerate = append(mock.calls.EntityHandleGenerate, callInfo)
+	lockStoreInterfaceMockEntityHandleGenerate.Unlock()
+	return mock.EntityHandleGenerateFunc(entityType, text)
+}
+
+// EntityHandleGenerateCalls gets all the calls that were made to EntityHandleGenerate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityHandleGenerateCalls())
+func (mock *StoreInterfaceMock) EntityHandleGenerateCalls() []struct {
+	EntityType string
+	Text       string
+} {
+	var calls []struct {
+		EntityType string
+		Text       string
+	}
+	lockStoreInterfaceMockEntityHandleGenerate.RLock()
+	calls = mock.calls.EntityHandleGenerate
+	lockStoreInterfaceMockEntityHandleGenerate.RUnlock()
+	return calls
+}
+
+// EntityIterate calls EntityIterateFunc.
+func (mock *StoreInterfaceMock) EntityIterate(options entitystore.EntityQueryOptions, fn func(entity entitystore.Entity, attributes []entitystore.Attribute) error, iterateOptions ...entitystore.IterateOptions) error {
+	if mock.EntityIterateFunc == nil {
+		panic("StoreInterfaceMock.EntityIterateFunc: method is nil but StoreInterface.EntityIterate was just called")
+	}
+	callInfo := struct {
+		Options        entitystore.EntityQueryOptions
+		Fn             func(entity entitystore.Entity, attributes []entitystore.Attribute) error
+		IterateOptions []entitystore.IterateOptions
+	}{
+		Options:        options,
+		Fn:             fn,
+		IterateOptions: iterateOptions,
+	}
+	lockStoreInterfaceMockEntityIterate.Lock()
+	mock.calls.EntityIterate = append(mock.calls.EntityIterate, callInfo)
+	lockStoreInterfaceMockEntityIterate.Unlock()
+	return mock.EntityIterateFunc(options, fn, iterateOptions...)
+}
+
+// EntityIterateCalls gets all the calls that were made to EntityIterate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityIterateCalls())
+func (mock *StoreInterfaceMock) EntityIterateCalls() []struct {
+	Options        entitystore.EntityQueryOptions
+	Fn             func(entity entitystore.Entity, attributes []entitystore.Attribute) error
+	IterateOptions []entitystore.IterateOptions
+} {
+	var calls []struct {
+		Options        entitystore.EntityQueryOptions
+		Fn             func(entity entitystore.Entity, attributes []entitystore.Attribute) error
+		IterateOptions []entitystore.IterateOptions
+	}
+	lockStoreInterfaceMockEntityIterate.RLock()
+	calls = mock.calls.EntityIterate
+	lockStoreInterfaceMockEntityIterate.RUnlock()
+	return calls
+}
+
+// EntityList calls EntityListFunc.
+func (mock *StoreInterfaceMock) EntityList(options entitystore.EntityQueryOptions) ([]entitystore.Entity, error) {
+	if mock.EntityListFunc == nil {
+		panic("StoreInterfaceMock.EntityListFunc: method is nil but StoreInterface.EntityList was just called")
+	}
+	callInfo := struct {
+		Options entitystore.EntityQueryOptions
+	}{
+		Options: options,
+	}
+	lockStoreInterfaceMockEntityList.Lock()
+	mock.calls.EntityList = append(mock.calls.EntityList, callInfo)
+	lockStoreInterfaceMockEntityList.Unlock()
+	return mock.EntityListFunc(options)
+}
+
+// EntityListCalls gets all the calls that were made to EntityList.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityListCalls())
+func (mock *StoreInterfaceMock) EntityListCalls() []struct {
+	Options entitystore.EntityQueryOptions
+} {
+	var calls []struct {
+		Options entitystore.EntityQueryOptions
+	}
+	lockStoreInterfaceMockEntityList.RLock()
+	calls = mock.calls.EntityList
+	lockStoreInterfaceMockEntityList.RUnlock()
+	return calls
+}
+
+// EntityListByAttribute calls EntityListByAttributeFunc.
+func (mock *StoreInterfaceMock) EntityListByAttribute(entityType string, attributeKey string, attributeValue string) ([]entitystore.Entity, error) {
+	if mock.EntityListByAttributeFunc == nil {
+		panic("StoreInterfaceMock.EntityListByAttributeFunc: method is nil but StoreInterface.EntityListByAttribute was just called")
+	}
+	callInfo := struct {
+		EntityType     string
+		AttributeKey   string
+		AttributeValue string
+	}{
+		EntityType:     entityType,
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockStoreInterfaceMockEntityListByAttribute.Lock()
+	mock.calls.EntityListByAttribute = append(mock.calls.EntityListByAttribute, callInfo)
+	lockStoreInterfaceMockEntityListByAttribute.Unlock()
+	return mock.EntityListByAttributeFunc(entityType, attributeKey, attributeValue)
+}
+
+// EntityListByAttributeCalls gets all the calls that were made to EntityListByAttribute.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityListByAttributeCalls())
+func (mock *StoreInterfaceMock) EntityListByAttributeCalls() []struct {
+	EntityType     string
+	AttributeKey   string
+	AttributeValue string
+} {
+	var calls []struct {
+		EntityType     string
+		AttributeKey   string
+		AttributeValue string
+	}
+	lockStoreInterfaceMockEntityListByAttribute.RLock()
+	calls = mock.calls.EntityListByAttribute
+	lockStoreInterfaceMockEntityListByAttribute.RUnlock()
+	return calls
+}
+
+// EntityLock calls EntityLockFunc.
+func (mock *StoreInterfaceMock) EntityLock(entityID string, owner string, ttl time.Duration) (*entitystore.EntityLease, error) {
+	if mock.EntityLockFunc == nil {
+		panic("StoreInterfaceMock.EntityLockFunc: method is nil but StoreInterface.EntityLock was just called")
+	}
+	callInfo := struct {
+		EntityID string
+		Owner    string
+		TTL      time.Duration
+	}{
+		EntityID: entityID,
+		Owner:    owner,
+		TTL:      ttl,
+	}
+	lockStoreInterfaceMockEntityLock.Lock()
+	mock.calls.EntityLock = append(mock.calls.EntityLock, callInfo)
+	lockStoreInterfaceMockEntityLock.Unlock()
+	return mock.EntityLockFunc(entityID, owner, ttl)
+}
+
+// EntityLockCalls gets all the calls that were made to EntityLock.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityLockCalls())
+func (mock *StoreInterfaceMock) EntityLockCalls() []struct {
+	EntityID string
+	Owner    string
+	TTL      time.Duration
+} {
+	var calls []struct {
+		EntityID string
+		Owner    string
+		TTL      time.Duration
+	}
+	lockStoreInterfaceMockEntityLock.RLock()
+	calls = mock.calls.EntityLock
+	lockStoreInterfaceMockEntityLock.RUnlock()
+	return calls
+}
+
+// EntityLockRenew calls EntityLockRenewFunc.
+func (mock *StoreInterfaceMock) EntityLockRenew(entityID string, owner string, ttl time.Duration) (*entitystore.EntityLease, error) {
+	if mock.EntityLockRenewFunc == nil {
+		panic("StoreInterfaceMock.EntityLockRenewFunc: method is nil but StoreInterface.EntityLockRenew was just called")
+	}
+	callInfo := struct {
+		EntityID string
+		Owner    string
+		TTL      time.Duration
+	}{
+		EntityID: entityID,
+		Owner:    owner,
+		TTL:      ttl,
+	}
+	lockStoreInterfaceMockEntityLockRenew.Lock()
+	mock.calls.EntityLockRenew = append(mock.calls.EntityLockRenew, callInfo)
+	lockStoreInterfaceMockEntityLockRenew.Unlock()
+	return mock.EntityLockRenewFunc(entityID, owner, ttl)
+}
+
+// EntityLockRenewCalls gets all the calls that were made to EntityLockRenew.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityLockRenewCalls())
+func (mock *StoreInterfaceMock) EntityLockRenewCalls() []struct {
+	EntityID string
+	Owner    string
+	TTL      time.Duration
+} {
+	var calls []struct {
+		EntityID string
+		Owner    string
+		TTL      time.Duration
+	}
+	lockStoreInterfaceMockEntityLockRenew.RLock()
+	calls = mock.calls.EntityLockRenew
+	lockStoreInterfaceMockEntityLockRenew.RUnlock()
+	return calls
+}
+
+// EntityQuery calls EntityQueryFunc.
+func (mock *StoreInterfaceMock) EntityQuery(options entitystore.EntityQueryOptions) *goqu.SelectDataset {
+	if mock.EntityQueryFunc == nil {
+		panic("StoreInterfaceMock.EntityQueryFunc: method is nil but StoreInterface.EntityQuery was just called")
+	}
+	callInfo := struct {
+		Options entitystore.EntityQueryOptions
+	}{
+		Options: options,
+	}
+	lockStoreInterfaceMockEntityQuery.Lock()
+	mock.calls.EntityQuery = append(mock.calls.EntityQuery, callInfo)
+	lockStoreInterfaceMockEntityQuery.Unlock()
+	return mock.EntityQueryFunc(options)
+}
+
+// EntityQueryCalls gets all the calls that were made to EntityQuery.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityQueryCalls())
+func (mock *StoreInterfaceMock) EntityQueryCalls() []struct {
+	Options entitystore.EntityQueryOptions
+} {
+	var calls []struct {
+		Options entitystore.EntityQueryOptions
+	}
+	lockStoreInterfaceMockEntityQuery.RLock()
+	calls = mock.calls.EntityQuery
+	lockStoreInterfaceMockEntityQuery.RUnlock()
+	return calls
+}
+
+// EntityRestore calls EntityRestoreFunc.
+func (mock *StoreInterfaceMock) EntityRestore(entityID string) (bool, error) {
+	if mock.EntityRestoreFunc == nil {
+		panic("StoreInterfaceMock.EntityRestoreFunc: method is nil but StoreInterface.EntityRestore was just called")
+	}
+	callInfo := struct {
+		EntityID string
+	}{
+		EntityID: entityID,
+	}
+	lockStoreInterfaceMockEntityRestore.Lock()
+	mock.calls.EntityRestore = append(mock.calls.EntityRestore, callInfo)
+	lockStoreInterfaceMockEntityRestore.Unlock()
+	return mock.EntityRestoreFunc(entityID)
+}
+
+// EntityRestoreCalls gets all the calls that were made to EntityRestore.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityRestoreCalls())
+func (mock *StoreInterfaceMock) EntityRestoreCalls() []struct {
+	EntityID string
+} {
+	var calls []struct {
+		EntityID string
+	}
+	lockStoreInterfaceMockEntityRestore.RLock()
+	calls = mock.calls.EntityRestore
+	lockStoreInterfaceMockEntityRestore.RUnlock()
+	return calls
+}
+
+// EntitySetHandle calls EntitySetHandleFunc.
+func (mock *StoreInterfaceMock) EntitySetHandle(entityID string, entityHandle string) error {
+	if mock.EntitySetHandleFunc == nil {
+		panic("StoreInterfaceMock.EntitySetHandleFunc: method is nil but StoreInterface.EntitySetHandle was just called")
+	}
+	callInfo := struct {
+		EntityID     string
+		EntityHandle string
+	}{
+		EntityID:     entityID,
+		EntityHandle: entityHandle,
+	}
+	lockStoreInterfaceMockEntitySetHandle.Lock()
+	mock.calls.EntitySetHandle = append(mock.calls.EntitySetHandle, callInfo)
+	lockStoreInterfaceMockEntitySetHandle.Unlock()
+	return mock.EntitySetHandleFunc(entityID, entityHandle)
+}
+
+// EntitySetHandleCalls gets all the calls that were made to EntitySetHandle.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntitySetHandleCalls())
+func (mock *StoreInterfaceMock) EntitySetHandleCalls() []struct {
+	EntityID     string
+	EntityHandle string
+} {
+	var calls []struct {
+		EntityID     string
+		EntityHandle string
+	}
+	lockStoreInterfaceMockEntitySetHandle.RLock()
+	calls = mock.calls.EntitySetHandle
+	lockStoreInterfaceMockEntitySetHandle.RUnlock()
+	return calls
+}
+
+// EntityTrash calls EntityTrashFunc.
+func (mock *StoreInterfaceMock) EntityTrash(entityID string) (bool, error) {
+	if mock.EntityTrashFunc == nil {
+		panic("StoreInterfaceMock.EntityTrashFunc: method is nil but StoreInterface.EntityTrash was just called")
+	}
+	callInfo := struct {
+		EntityID string
+	}{
+		EntityID: entityID,
+	}
+	lockStoreInterfaceMockEntityTrash.Lock()
+	mock.calls.EntityTrash = append(mock.calls.EntityTrash, callInfo)
+	lockStoreInterfaceMockEntityTrash.Unlock()
+	return mock.EntityTrashFunc(entityID)
+}
+
+// EntityTrashCalls gets all the calls that were made to EntityTrash.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityTrashCalls())
+func (mock *StoreInterfaceMock) EntityTrashCalls() []struct {
+	EntityID string
+} {
+	var calls []struct {
+		EntityID string
+	}
+	lockStoreInterfaceMockEntityTrash.RLock()
+	calls = mock.calls.EntityTrash
+	lockStoreInterfaceMockEntityTrash.RUnlock()
+	return calls
+}
+
+// EntityTypes calls EntityTypesFunc.
+func (mock *StoreInterfaceMock) EntityTypes() ([]entitystore.EntityTypeCount, error) {
+	if mock.EntityTypesFunc == nil {
+		panic("StoreInterfaceMock.EntityTypesFunc: method is nil but StoreInterface.EntityTypes was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockEntityTypes.Lock()
+	mock.calls.EntityTypes = append(mock.calls.EntityTypes, callInfo)
+	lockStoreInterfaceMockEntityTypes.Unlock()
+	return mock.EntityTypesFunc()
+}
+
+// EntityTypesCalls gets all the calls that were made to EntityTypes.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityTypesCalls())
+func (mock *StoreInterfaceMock) EntityTypesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockEntityTypes.RLock()
+	calls = mock.calls.EntityTypes
+	lockStoreInterfaceMockEntityTypes.RUnlock()
+	return calls
+}
+
+// EntityUnlock calls EntityUnlockFunc.
+func (mock *StoreInterfaceMock) EntityUnlock(entityID string, owner string) error {
+	if mock.EntityUnlockFunc == nil {
+		panic("StoreInterfaceMock.EntityUnlockFunc: method is nil but StoreInterface.EntityUnlock was just called")
+	}
+	callInfo := struct {
+		EntityID string
+		Owner    string
+	}{
+		EntityID: entityID,
+		Owner:    owner,
+	}
+	lockStoreInterfaceMockEntityUnlock.Lock()
+	mock.calls.EntityUnlock = append(mock.calls.EntityUnlock, callInfo)
+	lockStoreInterfaceMockEntityUnlock.Unlock()
+	return mock.EntityUnlockFunc(entityID, owner)
+}
+
+// EntityUnlockCalls gets all the calls that were made to EntityUnlock.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityUnlockCalls())
+func (mock *StoreInterfaceMock) EntityUnlockCalls() []struct {
+	EntityID string
+	Owner    string
+} {
+	var calls []struct {
+		EntityID string
+		Owner    string
+	}
+	lockStoreInterfaceMockEntityUnlock.RLock()
+	calls = mock.calls.EntityUnlock
+	lockStoreInterfaceMockEntityUnlock.RUnlock()
+	return calls
+}
+
+// EntityUpdate calls EntityUpdateFunc.
+func (mock *StoreInterfaceMock) EntityUpdate(ent entitystore.Entity) (bool, error) {
+	if mock.EntityUpdateFunc == nil {
+		panic("StoreInterfaceMock.EntityUpdateFunc: method is nil but StoreInterface.EntityUpdate was just called")
+	}
+	callInfo := struct {
+		Ent entitystore.Entity
+	}{
+		Ent: ent,
+	}
+	lockStoreInterfaceMockEntityUpdate.Lock()
+	mock.calls.EntityUpdate = append(mock.calls.EntityUpdate, callInfo)
+	lockStoreInterfaceMockEntityUpdate.Unlock()
+	return mock.EntityUpdateFunc(ent)
+}
+
+// EntityUpdateCalls gets all the calls that were made to EntityUpdate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityUpdateCalls())
+func (mock *StoreInterfaceMock) EntityUpdateCalls() []struct {
+	Ent entitystore.Entity
+} {
+	var calls []struct {
+		Ent entitystore.Entity
+	}
+	lockStoreInterfaceMockEntityUpdate.RLock()
+	calls = mock.calls.EntityUpdate
+	lockStoreInterfaceMockEntityUpdate.RUnlock()
+	return calls
+}
+
+// EntityUpdateIfVersion calls EntityUpdateIfVersionFunc.
+func (mock *StoreInterfaceMock) EntityUpdateIfVersion(ent entitystore.Entity, version int64) (bool, error) {
+	if mock.EntityUpdateIfVersionFunc == nil {
+		panic("StoreInterfaceMock.EntityUpdateIfVersionFunc: method is nil but StoreInterface.EntityUpdateIfVersion was just called")
+	}
+	callInfo := struct {
+		Ent     entitystore.Entity
+		Version int64
+	}{
+		Ent:     ent,
+		Version: version,
+	}
+	lockStoreInterfaceMockEntityUpdateIfVersion.Lock()
+	mock.calls.EntityUpdateIfVersion = append(mock.calls.EntityUpdateIfVersion, callInfo)
+	lockStoreInterfaceMockEntityUpdateIfVersion.Unlock()
+	return mock.EntityUpdateIfVersionFunc(ent, version)
+}
+
+// EntityUpdateIfVersionCalls gets all the calls that were made to EntityUpdateIfVersion.
+// Check the length with:
+//
+//	len(mockedStoreInterface.EntityUpdateIfVersionCalls())
+func (mock *StoreInterfaceMock) EntityUpdateIfVersionCalls() []struct {
+	Ent     entitystore.Entity
+	Version int64
+} {
+	var calls []struct {
+		Ent     entitystore.Entity
+		Version int64
+	}
+	lockStoreInterfaceMockEntityUpdateIfVersion.RLock()
+	calls = mock.calls.EntityUpdateIfVersion
+	lockStoreInterfaceMockEntityUpdateIfVersion.RUnlock()
+	return calls
+}
+
+// Export calls ExportFunc.
+func (mock *StoreInterfaceMock) Export(writer io.Writer, filter entitystore.EntityQueryOptions) (int64, error) {
+	if mock.ExportFunc == nil {
+		panic("StoreInterfaceMock.ExportFunc: method is nil but StoreInterface.Export was just called")
+	}
+	callInfo := struct {
+		Writer io.Writer
+		Filter entitystore.EntityQueryOptions
+	}{
+		Writer: writer,
+		Filter: filter,
+	}
+	lockStoreInterfaceMockExport.Lock()
+	mock.calls.Export = append(mock.calls.Export, callInfo)
+	lockStoreInterfaceMockExport.Unlock()
+	return mock.ExportFunc(writer, filter)
+}
+
+// ExportCalls gets all the calls that were made to Export.
+// Check the length with:
+//
+//	len(mockedStoreInterface.ExportCalls())
+func (mock *StoreInterfaceMock) ExportCalls() []struct {
+	Writer io.Writer
+	Filter entitystore.EntityQueryOptions
+} {
+	var calls []struct {
+		Writer io.Writer
+		Filter entitystore.EntityQueryOptions
+	}
+	lockStoreInterfaceMockExport.RLock()
+	calls = mock.calls.Export
+	lockStoreInterfaceMockExport.RUnlock()
+	return calls
+}
+
+// ExportCSV calls ExportCSVFunc.
+func (mock *StoreInterfaceMock) ExportCSV(entityType string, attributeKeys []string, writer io.Writer) (int64, error) {
+	if mock.ExportCSVFunc == nil {
+		panic("StoreInterfaceMock.ExportCSVFunc: method is nil but StoreInterface.ExportCSV was just called")
+	}
+	callInfo := struct {
+		EntityType    string
+		AttributeKeys []string
+		Writer        io.Writer
+	}{
+		EntityType:    entityType,
+		AttributeKeys: attributeKeys,
+		Writer:        writer,
+	}
+	lockStoreInterfaceMockExportCSV.Lock()
+	mock.calls.ExportCSV = append(mock.calls.ExportCSV, callInfo)
+	lockStoreInterfaceMockExportCSV.Unlock()
+	return mock.ExportCSVFunc(entityType, attributeKeys, writer)
+}
+
+// ExportCSVCalls gets all the calls that were made to ExportCSV.
+// Check the length with:
+//
+//	len(mockedStoreInterface.ExportCSVCalls())
+func (mock *StoreInterfaceMock) ExportCSVCalls() []struct {
+	EntityType    string
+	AttributeKeys []string
+	Writer        io.Writer
+} {
+	var calls []struct {
+		EntityType    string
+		AttributeKeys []string
+		Writer        io.Writer
+	}
+	lockStoreInterfaceMockExportCSV.RLock()
+	calls = mock.calls.ExportCSV
+	lockStoreInterfaceMockExportCSV.RUnlock()
+	return calls
+}
+
+// Facets calls FacetsFunc.
+func (mock *StoreInterfaceMock) Facets(entityType string, attributeKeys []string, filter map[string]string, limit int) (map[string][]entitystore.FacetValue, error) {
+	if mock.FacetsFunc == nil {
+		panic("StoreInterfaceMock.FacetsFunc: method is nil but StoreInterface.Facets was just called")
+	}
+	callInfo := struct {
+		EntityType    string
+		AttributeKeys []string
+		Filter        map[string]string
+		Limit         int
+	}{
+		EntityType:    entityType,
+		AttributeKeys: attributeKeys,
+		Filter:        filter,
+		Limit:         limit,
+	}
+	lockStoreInterfaceMockFacets.Lock()
+	mock.calls.Facets = append(mock.calls.Facets, callInfo)
+	lockStoreInterfaceMockFacets.Unlock()
+	return mock.FacetsFunc(entityType, attributeKeys, filter, limit)
+}
+
+// FacetsCalls gets all the calls that were made to Facets.
+// Check the length with:
+//
+//	len(mockedStoreInterface.FacetsCalls())
+func (mock *StoreInterfaceMock) FacetsCalls() []struct {
+	EntityType    string
+	AttributeKeys []string
+	Filter        map[string]string
+	Limit         int
+} {
+	var calls []struct {
+		EntityType    string
+		AttributeKeys []string
+		Filter        map[string]string
+		Limit         int
+	}
+	lockStoreInterfaceMockFacets.RLock()
+	calls = mock.calls.Facets
+	lockStoreInterfaceMockFacets.RUnlock()
+	return calls
+}
+
+// GetAttributeTableName calls GetAttributeTableNameFunc.
+func (mock *StoreInterfaceMock) GetAttributeTableName() string {
+	if mock.GetAttributeTableNameFunc == nil {
+		panic("StoreInterfaceMock.GetAttributeTableNameFunc: method is nil but StoreInterface.GetAttributeTableName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetAttributeTableName.Lock()
+	mock.calls.GetAttributeTableName = append(mock.calls.GetAttributeTableName, callInfo)
+	lockStoreInterfaceMockGetAttributeTableName.Unlock()
+	return mock.GetAttributeTableNameFunc()
+}
+
+// GetAttributeTableNameCalls gets all the calls that were made to GetAttributeTableName.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetAttributeTableNameCalls())
+func (mock *StoreInterfaceMock) GetAttributeTableNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetAttributeTableName.RLock()
+	calls = mock.calls.GetAttributeTableName
+	lockStoreInterfaceMockGetAttributeTableName.RUnlock()
+	return calls
+}
+
+// GetAttributeTrashTableName calls GetAttributeTrashTableNameFunc.
+func (mock *StoreInterfaceMock) GetAttributeTrashTableName() string {
+	if mock.GetAttributeTrashTableNameFunc == nil {
+		panic("StoreInterfaceMock.GetAttributeTrashTableNameFunc: method is nil but StoreInterface.GetAttributeTrashTableName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetAttributeTrashTableName.Lock()
+	mock.calls.GetAttributeTrashTableName = append(mock.calls.GetAttributeTrashTableName, callInfo)
+	lockStoreInterfaceMockGetAttributeTrashTableName.Unlock()
+	return mock.GetAttributeTrashTableNameFunc()
+}
+
+// GetAttributeTrashTableNameCalls gets all the calls that were made to GetAttributeTrashTableName.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetAttributeTrashTableNameCalls())
+func (mock *StoreInterfaceMock) GetAttributeTrashTableNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetAttributeTrashTableName.RLock()
+	calls = mock.calls.GetAttributeTrashTableName
+	lockStoreInterfaceMockGetAttributeTrashTableName.RUnlock()
+	return calls
+}
+
+// GetDB calls GetDBFunc.
+func (mock *StoreInterfaceMock) GetDB() *sql.DB {
+	if mock.GetDBFunc == nil {
+		panic("StoreInterfaceMock.GetDBFunc: method is nil but StoreInterface.GetDB was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetDB.Lock()
+	mock.calls.GetDB = append(mock.calls.GetDB, callInfo)
+	lockStoreInterfaceMockGetDB.Unlock()
+	return mock.GetDBFunc()
+}
+
+// GetDBCalls gets all the calls that were made to GetDB.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetDBCalls())
+func (mock *StoreInterfaceMock) GetDBCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetDB.RLock()
+	calls = mock.calls.GetDB
+	lockStoreInterfaceMockGetDB.RUnlock()
+	return calls
+}
+
+// GetDebug calls GetDebugFunc.
+func (mock *StoreInterfaceMock) GetDebug() bool {
+	if mock.GetDebugFunc == nil {
+		panic("StoreInterfaceMock.GetDebugFunc: method is nil but StoreInterface.GetDebug was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetDebug.Lock()
+	mock.calls.GetDebug = append(mock.calls.GetDebug, callInfo)
+	lockStoreInterfaceMockGetDebug.Unlock()
+	return mock.GetDebugFunc()
+}
+
+// GetDebugCalls gets all the calls that were made to GetDebug.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetDebugCalls())
+func (mock *StoreInterfaceMock) GetDebugCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetDebug.RLock()
+	calls = mock.calls.GetDebug
+	lockStoreInterfaceMockGetDebug.RUnlock()
+	return calls
+}
+
+// GetEntityTableName calls GetEntityTableNameFunc.
+func (mock *StoreInterfaceMock) GetEntityTableName() string {
+	if mock.GetEntityTableNameFunc == nil {
+		panic("StoreInterfaceMock.GetEntityTableNameFunc: method is nil but StoreInterface.GetEntityTableName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetEntityTableName.Lock()
+	mock.calls.GetEntityTableName = append(mock.calls.GetEntityTableName, callInfo)
+	lockStoreInterfaceMockGetEntityTableName.Unlock()
+	return mock.GetEntityTableNameFunc()
+}
+
+// GetEntityTableNameCalls gets all the calls that were made to GetEntityTableName.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetEntityTableNameCalls())
+func (mock *StoreInterfaceMock) GetEntityTableNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetEntityTableName.RLock()
+	calls = mock.calls.GetEntityTableName
+	lockStoreInterfaceMockGetEntityTableName.RUnlock()
+	return calls
+}
+
+// GetEntityTrashTableName calls GetEntityTrashTableNameFunc.
+func (mock *StoreInterfaceMock) GetEntityTrashTableName() string {
+	if mock.GetEntityTrashTableNameFunc == nil {
+		panic("StoreInterfaceMock.GetEntityTrashTableNameFunc: method is nil but StoreInterface.GetEntityTrashTableName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetEntityTrashTableName.Lock()
+	mock.calls.GetEntityTrashTableName = append(mock.calls.GetEntityTrashTableName, callInfo)
+	lockStoreInterfaceMockGetEntityTrashTableName.Unlock()
+	return mock.GetEntityTrashTableNameFunc()
+}
+
+// GetEntityTrashTableNameCalls gets all the calls that were made to GetEntityTrashTableName.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetEntityTrashTableNameCalls())
+func (mock *StoreInterfaceMock) GetEntityTrashTableNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetEntityTrashTableName.RLock()
+	calls = mock.calls.GetEntityTrashTableName
+	lockStoreInterfaceMockGetEntityTrashTableName.RUnlock()
+	return calls
+}
+
+// GetSchemaVersionTableName calls GetSchemaVersionTableNameFunc.
+func (mock *StoreInterfaceMock) GetSchemaVersionTableName() string {
+	if mock.GetSchemaVersionTableNameFunc == nil {
+		panic("StoreInterfaceMock.GetSchemaVersionTableNameFunc: method is nil but StoreInterface.GetSchemaVersionTableName was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockGetSchemaVersionTableName.Lock()
+	mock.calls.GetSchemaVersionTableName = append(mock.calls.GetSchemaVersionTableName, callInfo)
+	lockStoreInterfaceMockGetSchemaVersionTableName.Unlock()
+	return mock.GetSchemaVersionTableNameFunc()
+}
+
+// GetSchemaVersionTableNameCalls gets all the calls that were made to GetSchemaVersionTableName.
+// Check the length with:
+//
+//	len(mockedStoreInterface.GetSchemaVersionTableNameCalls())
+func (mock *StoreInterfaceMock) GetSchemaVersionTableNameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockGetSchemaVersionTableName.RLock()
+	calls = mock.calls.GetSchemaVersionTableName
+	lockStoreInterfaceMockGetSchemaVersionTableName.RUnlock()
+	return calls
+}
+
+// Import calls ImportFunc.
+func (mock *StoreInterfaceMock) Import(reader io.Reader, options entitystore.ImportOptions) (*entitystore.ImportReport, error) {
+	if mock.ImportFunc == nil {
+		panic("StoreInterfaceMock.ImportFunc: method is nil but StoreInterface.Import was just called")
+	}
+	callInfo := struct {
+		Reader  io.Reader
+		Options entitystore.ImportOptions
+	}{
+		Reader:  reader,
+		Options: options,
+	}
+	lockStoreInterfaceMockImport.Lock()
+	mock.calls.Import = append(mock.calls.Import, callInfo)
+	lockStoreInterfaceMockImport.Unlock()
+	return mock.ImportFunc(reader, options)
+}
+
+// ImportCalls gets all the calls that were made to Import.
+// Check the length with:
+//
+//	len(mockedStoreInterface.ImportCalls())
+func (mock *StoreInterfaceMock) ImportCalls() []struct {
+	Reader  io.Reader
+	Options entitystore.ImportOptions
+} {
+	var calls []struct {
+		Reader  io.Reader
+		Options entitystore.ImportOptions
+	}
+	lockStoreInterfaceMockImport.RLock()
+	calls = mock.calls.Import
+	lockStoreInterfaceMockImport.RUnlock()
+	return calls
+}
+
+// ImportCSV calls ImportCSVFunc.
+func (mock *StoreInterfaceMock) ImportCSV(entityType string, reader io.Reader, options entitystore.CSVImportOptions) (*entitystore.ImportReport, error) {
+	if mock.ImportCSVFunc == nil {
+		panic("StoreInterfaceMock.ImportCSVFunc: method is nil but StoreInterface.ImportCSV was just called")
+	}
+	callInfo := struct {
+		EntityType string
+		Reader     io.Reader
+		Options    entitystore.CSVImportOptions
+	}{
+		EntityType: entityType,
+		Reader:     reader,
+		Options:    options,
+	}
+	lockStoreInterfaceMockImportCSV.Lock()
+	mock.calls.ImportCSV = append(mock.calls.ImportCSV, callInfo)
+	lockStoreInterfaceMockImportCSV.Unlock()
+	return mock.ImportCSVFunc(entityType, reader, options)
+}
+
+// ImportCSVCalls gets all the calls that were made to ImportCSV.
+// Check the length with:
+//
+//	len(mockedStoreInterface.ImportCSVCalls())
+func (mock *StoreInterfaceMock) ImportCSVCalls() []struct {
+	EntityType string
+	Reader     io.Reader
+	Options    entitystore.CSVImportOptions
+} {
+	var calls []struct {
+		EntityType string
+		Reader     io.Reader
+		Options    entitystore.CSVImportOptions
+	}
+	lockStoreInterfaceMockImportCSV.RLock()
+	calls = mock.calls.ImportCSV
+	lockStoreInterfaceMockImportCSV.RUnlock()
+	return calls
+}
+
+// Migrate calls MigrateFunc.
+func (mock *StoreInterfaceMock) Migrate() error {
+	if mock.MigrateFunc == nil {
+		panic("StoreInterfaceMock.MigrateFunc: method is nil but StoreInterface.Migrate was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockMigrate.Lock()
+	mock.calls.Migrate = append(mock.calls.Migrate, callInfo)
+	lockStoreInterfaceMockMigrate.Unlock()
+	return mock.MigrateFunc()
+}
+
+// MigrateCalls gets all the calls that were made to Migrate.
+// Check the length with:
+//
+//	len(mockedStoreInterface.MigrateCalls())
+func (mock *StoreInterfaceMock) MigrateCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockMigrate.RLock()
+	calls = mock.calls.Migrate
+	lockStoreInterfaceMockMigrate.RUnlock()
+	return calls
+}
+
+// MigrationStatus calls MigrationStatusFunc.
+func (mock *StoreInterfaceMock) MigrationStatus() ([]entitystore.Migration, error) {
+	if mock.MigrationStatusFunc == nil {
+		panic("StoreInterfaceMock.MigrationStatusFunc: method is nil but StoreInterface.MigrationStatus was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockMigrationStatus.Lock()
+	mock.calls.MigrationStatus = append(mock.calls.MigrationStatus, callInfo)
+	lockStoreInterfaceMockMigrationStatus.Unlock()
+	return mock.MigrationStatusFunc()
+}
+
+// MigrationStatusCalls gets all the calls that were made to MigrationStatus.
+// Check the length with:
+//
+//	len(mockedStoreInterface.MigrationStatusCalls())
+func (mock *StoreInterfaceMock) MigrationStatusCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockMigrationStatus.RLock()
+	calls = mock.calls.MigrationStatus
+	lockStoreInterfaceMockMigrationStatus.RUnlock()
+	return calls
+}
+
+// NewAttribute calls NewAttributeFunc.
+func (mock *StoreInterfaceMock) NewAttribute(opts entitystore.NewAttributeOptions) *entitystore.Attribute {
+	if mock.NewAttributeFunc == nil {
+		panic("StoreInterfaceMock.NewAttributeFunc: method is nil but StoreInterface.NewAttribute was just called")
+	}
+	callInfo := struct {
+		Opts entitystore.NewAttributeOptions
+	}{
+		Opts: opts,
+	}
+	lockStoreInterfaceMockNewAttribute.Lock()
+	mock.calls.NewAttribute = append(mock.calls.NewAttribute, callInfo)
+	lockStoreInterfaceMockNewAttribute.Unlock()
+	return mock.NewAttributeFunc(opts)
+}
+
+// NewAttributeCalls gets all the calls that were made to NewAttribute.
+// Check the length with:
+//
+//	len(mockedStoreInterface.NewAttributeCalls())
+func (mock *StoreInterfaceMock) NewAttributeCalls() []struct {
+	Opts entitystore.NewAttributeOptions
+} {
+	var calls []struct {
+		Opts entitystore.NewAttributeOptions
+	}
+	lockStoreInterfaceMockNewAttribute.RLock()
+	calls = mock.calls.NewAttribute
+	lockStoreInterfaceMockNewAttribute.RUnlock()
+	return calls
+}
+
+// NewAttributeFromMap calls NewAttributeFromMapFunc.
+func (mock *StoreInterfaceMock) NewAttributeFromMap(attributeMap map[string]string) *entitystore.Attribute {
+	if mock.NewAttributeFromMapFunc == nil {
+		panic("StoreInterfaceMock.NewAttributeFromMapFunc: method is nil but StoreInterface.NewAttributeFromMap was just called")
+	}
+	callInfo := struct {
+		AttributeMap map[string]string
+	}{
+		AttributeMap: attributeMap,
+	}
+	lockStoreInterfaceMockNewAttributeFromMap.Lock()
+	mock.calls.NewAttributeFromMap = append(mock.calls.NewAttributeFromMap, callInfo)
+	lockStoreInterfaceMockNewAttributeFromMap.Unlock()
+	return mock.NewAttributeFromMapFunc(attributeMap)
+}
+
+// NewAttributeFromMapCalls gets all the calls that were made to NewAttributeFromMap.
+// Check the length with:
+//
+//	len(mockedStoreInterface.NewAttributeFromMapCalls())
+func (mock *StoreInterfaceMock) NewAttributeFromMapCalls() []struct {
+	AttributeMap map[string]string
+} {
+	var calls []struct {
+		AttributeMap map[string]string
+	}
+	lockStoreInterfaceMockNewAttributeFromMap.RLock()
+	calls = mock.calls.NewAttributeFromMap
+	lockStoreInterfaceMockNewAttributeFromMap.RUnlock()
+	return calls
+}
+
+// NewEntity calls NewEntityFunc.
+func (mock *StoreInterfaceMock) NewEntity(opts entitystore.NewEntityOptions) *entitystore.Entity {
+	if mock.NewEntityFunc == nil {
+		panic("StoreInterfaceMock.NewEntityFunc: method is nil but StoreInterface.NewEntity was just called")
+	}
+	callInfo := struct {
+		Opts entitystore.NewEntityOptions
+	}{
+		Opts: opts,
+	}
+	lockStoreInterfaceMockNewEntity.Lock()
+	mock.calls.NewEntity = append(mock.calls.NewEntity, callInfo)
+	lockStoreInterfaceMockNewEntity.Unlock()
+	return mock.NewEntityFunc(opts)
+}
+
+// NewEntityCalls gets all the calls that were made to NewEntity.
+// Check the length with:
+//
+//	len(mockedStoreInterface.NewEntityCalls())
+func (mock *StoreInterfaceMock) NewEntityCalls() []struct {
+	Opts entitystore.NewEntityOptions
+} {
+	var calls []struct {
+		Opts entitystore.NewEntityOptions
+	}
+	lockStoreInterfaceMockNewEntity.RLock()
+	calls = mock.calls.NewEntity
+	lockStoreInterfaceMockNewEntity.RUnlock()
+	return calls
+}
+
+// NewEntityFromMap calls NewEntityFromMapFunc.
+func (mock *StoreInterfaceMock) NewEntityFromMap(entityMap map[string]string) *entitystore.Entity {
+	if mock.NewEntityFromMapFunc == nil {
+		panic("StoreInterfaceMock.NewEntityFromMapFunc: method is nil but StoreInterface.NewEntityFromMap was just called")
+	}
+	callInfo := struct {
+		EntityMap map[string]string
+	}{
+		EntityMap: entityMap,
+	}
+	lockStoreInterfaceMockNewEntityFromMap.Lock()
+	mock.calls.NewEntityFromMap = append(mock.calls.NewEntityFromMap, callInfo)
+	lockStoreInterfaceMockNewEntityFromMap.Unlock()
+	return mock.NewEntityFromMapFunc(entityMap)
+}
+
+// NewEntityFromMapCalls gets all the calls that were made to NewEntityFromMap.
+// Check the length with:
+//
+//	len(mockedStoreInterface.NewEntityFromMapCalls())
+func (mock *StoreInterfaceMock) NewEntityFromMapCalls() []struct {
+	EntityMap map[string]string
+} {
+	var calls []struct {
+		EntityMap map[string]string
+	}
+	lockStoreInterfaceMockNewEntityFromMap.RLock()
+	calls = mock.calls.NewEntityFromMap
+	lockStoreInterfaceMockNewEntityFromMap.RUnlock()
+	return calls
+}
+
+// RegisterEntityHook calls RegisterEntityHookFunc.
+func (mock *StoreInterfaceMock) RegisterEntityHook(entityType string, hookType entitystore.EntityHookType, hook entitystore.EntityHook) {
+	if mock.RegisterEntityHookFunc == nil {
+		panic("StoreInterfaceMock.RegisterEntityHookFunc: method is nil but StoreInterface.RegisterEntityHook was just called")
+	}
+	callInfo := struct {
+		EntityType string
+		HookType   entitystore.EntityHookType
+		Hook       entitystore.EntityHook
+	}{
+		EntityType: entityType,
+		HookType:   hookType,
+		Hook:       hook,
+	}
+	lockStoreInterfaceMockRegisterEntityHook.Lock()
+	mock.calls.RegisterEntityHook = append(mock.calls.RegisterEntityHook, callInfo)
+	lockStoreInterfaceMockRegisterEntityHook.Unlock()
+	mock.RegisterEntityHookFunc(entityType, hookType, hook)
+}
+
+// RegisterEntityHookCalls gets all the calls that were made to RegisterEntityHook.
+// Check the length with:
+//
+//	len(mockedStoreInterface.RegisterEntityHookCalls())
+func (mock *StoreInterfaceMock) RegisterEntityHookCalls() []struct {
+	EntityType string
+	HookType   entitystore.EntityHookType
+	Hook       entitystore.EntityHook
+} {
+	var calls []struct {
+		EntityType string
+		HookType   entitystore.EntityHookType
+		Hook       entitystore.EntityHook
+	}
+	lockStoreInterfaceMockRegisterEntityHook.RLock()
+	calls = mock.calls.RegisterEntityHook
+	lockStoreInterfaceMockRegisterEntityHook.RUnlock()
+	return calls
+}
+
+// RepairDuplicateAttributes calls RepairDuplicateAttributesFunc.
+func (mock *StoreInterfaceMock) RepairDuplicateAttributes() (int64, error) {
+	if mock.RepairDuplicateAttributesFunc == nil {
+		panic("StoreInterfaceMock.RepairDuplicateAttributesFunc: method is nil but StoreInterface.RepairDuplicateAttributes was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockRepairDuplicateAttributes.Lock()
+	mock.calls.RepairDuplicateAttributes = append(mock.calls.RepairDuplicateAttributes, callInfo)
+	lockStoreInterfaceMockRepairDuplicateAttributes.Unlock()
+	return mock.RepairDuplicateAttributesFunc()
+}
+
+// RepairDuplicateAttributesCalls gets all the calls that were made to RepairDuplicateAttributes.
+// Check the length with:
+//
+//	len(mockedStoreInterface.RepairDuplicateAttributesCalls())
+func (mock *StoreInterfaceMock) RepairDuplicateAttributesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockRepairDuplicateAttributes.RLock()
+	calls = mock.calls.RepairDuplicateAttributes
+	lockStoreInterfaceMockRepairDuplicateAttributes.RUnlock()
+	return calls
+}
+
+// Restore calls RestoreFunc.
+func (mock *StoreInterfaceMock) Restore(reader io.Reader) (*entitystore.BackupManifest, error) {
+	if mock.RestoreFunc == nil {
+		panic("StoreInterfaceMock.RestoreFunc: method is nil but StoreInterface.Restore was just called")
+	}
+	callInfo := struct {
+		Reader io.Reader
+	}{
+		Reader: reader,
+	}
+	lockStoreInterfaceMockRestore.Lock()
+	mock.calls.Restore = append(mock.calls.Restore, callInfo)
+	lockStoreInterfaceMockRestore.Unlock()
+	return mock.RestoreFunc(reader)
+}
+
+// RestoreCalls gets all the calls that were made to Restore.
+// Check the length with:
+//
+//	len(mockedStoreInterface.RestoreCalls())
+func (mock *StoreInterfaceMock) RestoreCalls() []struct {
+	Reader io.Reader
+} {
+	var calls []struct {
+		Reader io.Reader
+	}
+	lockStoreInterfaceMockRestore.RLock()
+	calls = mock.calls.Restore
+	lockStoreInterfaceMockRestore.RUnlock()
+	return calls
+}
+
+// SqlCreateTable calls SqlCreateTableFunc.
+func (mock *StoreInterfaceMock) SqlCreateTable() ([]string, error) {
+	if mock.SqlCreateTableFunc == nil {
+		panic("StoreInterfaceMock.SqlCreateTableFunc: method is nil but StoreInterface.SqlCreateTable was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockSqlCreateTable.Lock()
+	mock.calls.SqlCreateTable = append(mock.calls.SqlCreateTable, callInfo)
+	lockStoreInterfaceMockSqlCreateTable.Unlock()
+	return mock.SqlCreateTableFunc()
+}
+
+// SqlCreateTableCalls gets all the calls that were made to SqlCreateTable.
+// Check the length with:
+//
+//	len(mockedStoreInterface.SqlCreateTableCalls())
+func (mock *StoreInterfaceMock) SqlCreateTableCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockSqlCreateTable.RLock()
+	calls = mock.calls.SqlCreateTable
+	lockStoreInterfaceMockSqlCreateTable.RUnlock()
+	return calls
+}
+
+// Stats calls StatsFunc.
+func (mock *StoreInterfaceMock) Stats() (entitystore.StoreStats, error) {
+	if mock.StatsFunc == nil {
+		panic("StoreInterfaceMock.StatsFunc: method is nil but StoreInterface.Stats was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockStoreInterfaceMockStats.Lock()
+	mock.calls.Stats = append(mock.calls.Stats, callInfo)
+	lockStoreInterfaceMockStats.Unlock()
+	return mock.StatsFunc()
+}
+
+// StatsCalls gets all the calls that were made to Stats.
+// Check the length with:
+//
+//	len(mockedStoreInterface.StatsCalls())
+func (mock *StoreInterfaceMock) StatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockStoreInterfaceMockStats.RLock()
+	calls = mock.calls.Stats
+	lockStoreInterfaceMockStats.RUnlock()
+	return calls
+}
+
+// Subscribe calls SubscribeFunc.
+func (mock *StoreInterfaceMock) Subscribe(entityType string, filter func(event entitystore.ChangeEvent) bool, options entitystore.SubscribeOptions) *entitystore.Subscription {
+	if mock.SubscribeFunc == nil {
+		panic("StoreInterfaceMock.SubscribeFunc: method is nil but StoreInterface.Subscribe was just called")
+	}
+	callInfo := struct {
+		EntityType string
+		Filter     func(event entitystore.ChangeEvent) bool
+		Options    entitystore.SubscribeOptions
+	}{
+		EntityType: entityType,
+		Filter:     filter,
+		Options:    options,
+	}
+	lockStoreInterfaceMockSubscribe.Lock()
+	mock.calls.Subscribe = append(mock.calls.Subscribe, callInfo)
+	lockStoreInterfaceMockSubscribe.Unlock()
+	return mock.SubscribeFunc(entityType, filter, options)
+}
+
+// SubscribeCalls gets all the calls that were made to Subscribe.
+// Check the length with:
+//
+//	len(mockedStoreInterface.SubscribeCalls())
+func (mock *StoreInterfaceMock) SubscribeCalls() []struct {
+	EntityType string
+	Filter     func(event entitystore.ChangeEvent) bool
+	Options    entitystore.SubscribeOptions
+} {
+	var calls []struct {
+		EntityType string
+		Filter     func(event entitystore.ChangeEvent) bool
+		Options    entitystore.SubscribeOptions
+	}
+	lockStoreInterfaceMockSubscribe.RLock()
+	calls = mock.calls.Subscribe
+	lockStoreInterfaceMockSubscribe.RUnlock()
+	return calls
+}
+
+// Sync calls SyncFunc.
+func (mock *StoreInterfaceMock) Sync(dst entitystore.StoreInterface, filter entitystore.EntityQueryOptions, since time.Time, options entitystore.CopyOptions) (*entitystore.CopyReport, error) {
+	if mock.SyncFunc == nil {
+		panic("StoreInterfaceMock.SyncFunc: method is nil but StoreInterface.Sync was just called")
+	}
+	callInfo := struct {
+		Dst     entitystore.StoreInterface
+		Filter  entitystore.EntityQueryOptions
+		Since   time.Time
+		Options entitystore.CopyOptions
+	}{
+		Dst:     dst,
+		Filter:  filter,
+		Since:   since,
+		Options: options,
+	}
+	lockStoreInterfaceMockSync.Lock()
+	mock.calls.Sync = append(mock.calls.Sync, callInfo)
+	lockStoreInterfaceMockSync.Unlock()
+	return mock.SyncFunc(dst, filter, since, options)
+}
+
+// SyncCalls gets all the calls that were made to Sync.
+// Check the length with:
+//
+//	len(mockedStoreInterface.SyncCalls())
+func (mock *StoreInterfaceMock) SyncCalls() []struct {
+	Dst     entitystore.StoreInterface
+	Filter  entitystore.EntityQueryOptions
+	Since   time.Time
+	Options entitystore.CopyOptions
+} {
+	var calls []struct {
+		Dst     entitystore.StoreInterface
+		Filter  entitystore.EntityQueryOptions
+		Since   time.Time
+		Options entitystore.CopyOptions
+	}
+	lockStoreInterfaceMockSync.RLock()
+	calls = mock.calls.Sync
+	lockStoreInterfaceMockSync.RUnlock()
+	return calls
+}
+
+var (
+	lockEntityInterfaceMockAddToSet       sync.RWMutex
+	lockEntityInterfaceMockAppendToList   sync.RWMutex
+	lockEntityInterfaceMockCreatedAt      sync.RWMutex
+	lockEntityInterfaceMockGetAttribute   sync.RWMutex
+	lockEntityInterfaceMockGetAttributes  sync.RWMutex
+	lockEntityInterfaceMockGetFloat       sync.RWMutex
+	lockEntityInterfaceMockGetInt         sync.RWMutex
+	lockEntityInterfaceMockGetList        sync.RWMutex
+	lockEntityInterfaceMockGetString      sync.RWMutex
+	lockEntityInterfaceMockHandle         sync.RWMutex
+	lockEntityInterfaceMockID             sync.RWMutex
+	lockEntityInterfaceMockIncrement      sync.RWMutex
+	lockEntityInterfaceMockIncrementFloat sync.RWMutex
+	lockEntityInterfaceMockRemoveFromSet  sync.RWMutex
+	lockEntityInterfaceMockSetAll         sync.RWMutex
+	lockEntityInterfaceMockSetCreatedAt   sync.RWMutex
+	lockEntityInterfaceMockSetFloat       sync.RWMutex
+	lockEntityInterfaceMockSetHandle      sync.RWMutex
+	lockEntityInterfaceMockSetID          sync.RWMutex
+	lockEntityInterfaceMockSetInt         sync.RWMutex
+	lockEntityInterfaceMockSetString      sync.RWMutex
+	lockEntityInterfaceMockSetType        sync.RWMutex
+	lockEntityInterfaceMockSetUpdatedAt   sync.RWMutex
+	lockEntityInterfaceMockSetVersion     sync.RWMutex
+	lockEntityInterfaceMockToMap          sync.RWMutex
+	lockEntityInterfaceMockType           sync.RWMutex
+	lockEntityInterfaceMockUpdatedAt      sync.RWMutex
+	lockEntityInterfaceMockVersion        sync.RWMutex
+)
+
+// Ensure, that EntityInterfaceMock does implement EntityInterface.
+// If this is not the case, regenerate this file with moq.
+var _ entitystore.EntityInterface = &EntityInterfaceMock{}
+
+// EntityInterfaceMock is a mock implementation of EntityInterface.
+//
+//	    func TestSomethingThatUsesEntityInterface(t *testing.T) {
+//
+//	        // make and configure a mocked EntityInterface
+//	        mockedEntityInterface := &EntityInterfaceMock{
+//	            AddToSetFunc: func(attributeKey string, values ...string) error {
+//		               panic("mock out the AddToSet method")
+//	            },
+//	            AppendToListFunc: func(attributeKey string, values ...string) error {
+//		               panic("mock out the AppendToList method")
+//	            },
+//	            CreatedAtFunc: func() time.Time {
+//		               panic("mock out the CreatedAt method")
+//	            },
+//	            GetAttributeFunc: func(attributeKey string) (*entitystore.Attribute, error) {
+//		               panic("mock out the GetAttribute method")
+//	            },
+//	            GetAttributesFunc: func() ([]entitystore.Attribute, error) {
+//		               panic("mock out the GetAttributes method")
+//	            },
+//	            GetFloatFunc: func(attributeKey string, defaultValue float64) (float64, error) {
+//		               panic("mock out the GetFloat method")
+//	            },
+//	            GetIntFunc: func(attributeKey string, defaultValue int64) (int64, error) {
+//		               panic("mock out the GetInt method")
+//	            },
+//	            GetListFunc: func(attributeKey string) ([]string, error) {
+//		               panic("mock out the GetList method")
+//	            },
+//	            GetStringFunc: func(attributeKey string, defaultValue string) (string, error) {
+//		               panic("mock out the GetString method")
+//	            },
+//	            HandleFunc: func() string {
+//		               panic("mock out the Handle method")
+//	            },
+//	            IDFunc: func() string {
+//		               panic("mock out the ID method")
+//	            },
+//	            IncrementFunc: func(attributeKey string, delta int64) (int64, error) {
+//		               panic("mock out the Increment method")
+//	            },
+//	            IncrementFloatFunc: func(attributeKey string, delta float64) (float64, error) {
+//		               panic("mock out the IncrementFloat method")
+//	            },
+//	            RemoveFromSetFunc: func(attributeKey string, values ...string) error {
+//		               panic("mock out the RemoveFromSet method")
+//	            },
+//	            SetAllFunc: func(attributes map[string]string) error {
+//		               panic("mock out the SetAll method")
+//	            },
+//	            SetCreatedAtFunc: func(createdAt time.Time) *entitystore.Entity {
+//		               panic("mock out the SetCreatedAt method")
+//	            },
+//	            SetFloatFunc: func(attributeKey string, attributeValue float64) error {
+//		               panic("mock out the SetFloat method")
+//	            },
+//	            SetHandleFunc: func(handle string) *entitystore.Entity {
+//		               panic("mock out the SetHandle method")
+//	            },
+//	            SetIDFunc: func(id string) *entitystore.Entity {
+//		               panic("mock out the SetID method")
+//	            },
+//	            SetIntFunc: func(attributeKey string, attributeValue int64) error {
+//		               panic("mock out the SetInt method")
+//	            },
+//	            SetStringFunc: func(attributeKey string, attributeValue string) error {
+//		               panic("mock out the SetString method")
+//	            },
+//	            SetTypeFunc: func(entityType string) *entitystore.Entity {
+//		               panic("mock out the SetType method")
+//	            },
+//	            SetUpdatedAtFunc: func(updatedAt time.Time) *entitystore.Entity {
+//		               panic("mock out the SetUpdatedAt method")
+//	            },
+//	            SetVersionFunc: func(version int64) *entitystore.Entity {
+//		               panic("mock out the SetVersion method")
+//	            },
+//	            ToMapFunc: func() map[string]any {
+//		               panic("mock out the ToMap method")
+//	            },
+//	            TypeFunc: func() string {
+//		               panic("mock out the Type method")
+//	            },
+//	            UpdatedAtFunc: func() time.Time {
+//		               panic("mock out the UpdatedAt method")
+//	            },
+//	            VersionFunc: func() int64 {
+//		               panic("mock out the Version method")
+//	            },
+//	        }
+//
+//	        // use mockedEntityInterface in code that requires EntityInterface
+//	        // and then make assertions.
+//
+//	    }
+type EntityInterfaceMock struct {
+	// AddToSetFunc mocks the AddToSet method.
+	AddToSetFunc func(attributeKey string, values ...string) error
+
+	// AppendToListFunc mocks the AppendToList method.
+	AppendToListFunc func(attributeKey string, values ...string) error
+
+	// CreatedAtFunc mocks the CreatedAt method.
+	CreatedAtFunc func() time.Time
+
+	// GetAttributeFunc mocks the GetAttribute method.
+	GetAttributeFunc func(attributeKey string) (*entitystore.Attribute, error)
+
+	// GetAttributesFunc mocks the GetAttributes method.
+	GetAttributesFunc func() ([]entitystore.Attribute, error)
+
+	// GetFloatFunc mocks the GetFloat method.
+	GetFloatFunc func(attributeKey string, defaultValue float64) (float64, error)
+
+	// GetIntFunc mocks the GetInt method.
+	GetIntFunc func(attributeKey string, defaultValue int64) (int64, error)
+
+	// GetListFunc mocks the GetList method.
+	GetListFunc func(attributeKey string) ([]string, error)
+
+	// GetStringFunc mocks the GetString method.
+	GetStringFunc func(attributeKey string, defaultValue string) (string, error)
+
+	// HandleFunc mocks the Handle method.
+	HandleFunc func() string
+
+	// IDFunc mocks the ID method.
+	IDFunc func() string
+
+	// IncrementFunc mocks the Increment method.
+	IncrementFunc func(attributeKey string, delta int64) (int64, error)
+
+	// IncrementFloatFunc mocks the IncrementFloat method.
+	IncrementFloatFunc func(attributeKey string, delta float64) (float64, error)
+
+	// RemoveFromSetFunc mocks the RemoveFromSet method.
+	RemoveFromSetFunc func(attributeKey string, values ...string) error
+
+	// SetAllFunc mocks the SetAll method.
+	SetAllFunc func(attributes map[string]string) error
+
+	// SetCreatedAtFunc mocks the SetCreatedAt method.
+	SetCreatedAtFunc func(createdAt time.Time) *entitystore.Entity
+
+	// SetFloatFunc mocks the SetFloat method.
+	SetFloatFunc func(attributeKey string, attributeValue float64) error
+
+	// SetHandleFunc mocks the SetHandle method.
+	SetHandleFunc func(handle string) *entitystore.Entity
+
+	// SetIDFunc mocks the SetID method.
+	SetIDFunc func(id string) *entitystore.Entity
+
+	// SetIntFunc mocks the SetInt method.
+	SetIntFunc func(attributeKey string, attributeValue int64) error
+
+	// SetStringFunc mocks the SetString method.
+	SetStringFunc func(attributeKey string, attributeValue string) error
+
+	// SetTypeFunc mocks the SetType method.
+	SetTypeFunc func(entityType string) *entitystore.Entity
+
+	// SetUpdatedAtFunc mocks the SetUpdatedAt method.
+	SetUpdatedAtFunc func(updatedAt time.Time) *entitystore.Entity
+
+	// SetVersionFunc mocks the SetVersion method.
+	SetVersionFunc func(version int64) *entitystore.Entity
+
+	// ToMapFunc mocks the ToMap method.
+	ToMapFunc func() map[string]any
+
+	// TypeFunc mocks the Type method.
+	TypeFunc func() string
+
+	// UpdatedAtFunc mocks the UpdatedAt method.
+	UpdatedAtFunc func() time.Time
+
+	// VersionFunc mocks the Version method.
+	VersionFunc func() int64
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddToSet holds details about calls to the AddToSet method.
+		AddToSet []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Values is the values argument value.
+			Values []string
+		}
+		// AppendToList holds details about calls to the AppendToList method.
+		AppendToList []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Values is the values argument value.
+			Values []string
+		}
+		// CreatedAt holds details about calls to the CreatedAt method.
+		CreatedAt []struct {
+		}
+		// GetAttribute holds details about calls to the GetAttribute method.
+		GetAttribute []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+		}
+		// GetAttributes holds details about calls to the GetAttributes method.
+		GetAttributes []struct {
+		}
+		// GetFloat holds details about calls to the GetFloat method.
+		GetFloat []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// DefaultValue is the defaultValue argument value.
+			DefaultValue float64
+		}
+		// GetInt holds details about calls to the GetInt method.
+		GetInt []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// DefaultValue is the defaultValue argument value.
+			DefaultValue int64
+		}
+		// GetList holds details about calls to the GetList method.
+		GetList []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+		}
+		// GetString holds details about calls to the GetString method.
+		GetString []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// DefaultValue is the defaultValue argument value.
+			DefaultValue string
+		}
+		// Handle holds details about calls to the Handle method.
+		Handle []struct {
+		}
+		// ID holds details about calls to the ID method.
+		ID []struct {
+		}
+		// Increment holds details about calls to the Increment method.
+		Increment []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Delta is the delta argument value.
+			Delta int64
+		}
+		// IncrementFloat holds details about calls to the IncrementFloat method.
+		IncrementFloat []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Delta is the delta argument value.
+			Delta float64
+		}
+		// RemoveFromSet holds details about calls to the RemoveFromSet method.
+		RemoveFromSet []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// Values is the values argument value.
+			Values []string
+		}
+		// SetAll holds details about calls to the SetAll method.
+		SetAll []struct {
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+		// SetCreatedAt holds details about calls to the SetCreatedAt method.
+		SetCreatedAt []struct {
+			// CreatedAt is the createdAt argument value.
+			CreatedAt time.Time
+		}
+		// SetFloat holds details about calls to the SetFloat method.
+		SetFloat []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue float64
+		}
+		// SetHandle holds details about calls to the SetHandle method.
+		SetHandle []struct {
+			// Handle is the handle argument value.
+			Handle string
+		}
+		// SetID holds details about calls to the SetID method.
+		SetID []struct {
+			// ID is the id argument value.
+			ID string
+		}
+		// SetInt holds details about calls to the SetInt method.
+		SetInt []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue int64
+		}
+		// SetString holds details about calls to the SetString method.
+		SetString []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue string
+		}
+		// SetType holds details about calls to the SetType method.
+		SetType []struct {
+			// EntityType is the entityType argument value.
+			EntityType string
+		}
+		// SetUpdatedAt holds details about calls to the SetUpdatedAt method.
+		SetUpdatedAt []struct {
+			// UpdatedAt is the updatedAt argument value.
+			UpdatedAt time.Time
+		}
+		// SetVersion holds details about calls to the SetVersion method.
+		SetVersion []struct {
+			// Version is the version argument value.
+			Version int64
+		}
+		// ToMap holds details about calls to the ToMap method.
+		ToMap []struct {
+		}
+		// Type holds details about calls to the Type method.
+		Type []struct {
+		}
+		// UpdatedAt holds details about calls to the UpdatedAt method.
+		UpdatedAt []struct {
+		}
+		// Version holds details about calls to the Version method.
+		Version []struct {
+		}
+	}
+}
+
+// AddToSet calls AddToSetFunc.
+func (mock *EntityInterfaceMock) AddToSet(attributeKey string, values ...string) error {
+	if mock.AddToSetFunc == nil {
+		panic("EntityInterfaceMock.AddToSetFunc: method is nil but EntityInterface.AddToSet was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		Values       []string
+	}{
+		AttributeKey: attributeKey,
+		Values:       values,
+	}
+	lockEntityInterfaceMockAddToSet.Lock()
+	mock.calls.AddToSet = append(mock.calls.AddToSet, callInfo)
+	lockEntityInterfaceMockAddToSet.Unlock()
+	return mock.AddToSetFunc(attributeKey, values...)
+}
+
+// AddToSetCalls gets all the calls that were made to AddToSet.
+// Check the length with:
+//
+//	len(mockedEntityInterface.AddToSetCalls())
+func (mock *EntityInterfaceMock) AddToSetCalls() []struct {
+	AttributeKey string
+	Values       []string
+} {
+	var calls []struct {
+		AttributeKey string
+		Values       []string
+	}
+	lockEntityInterfaceMockAddToSet.RLock()
+	calls = mock.calls.AddToSet
+	lockEntityInterfaceMockAddToSet.RUnlock()
+	return calls
+}
+
+// AppendToList calls AppendToListFunc.
+func (mock *EntityInterfaceMock) AppendToList(attributeKey string, values ...string) error {
+	if mock.AppendToListFunc == nil {
+		panic("EntityInterfaceMock.AppendToListFunc: method is nil but EntityInterface.AppendToList was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		Values       []string
+	}{
+		AttributeKey: attributeKey,
+		Values:       values,
+	}
+	lockEntityInterfaceMockAppendToList.Lock()
+	mock.calls.AppendToList = append(mock.calls.AppendToList, callInfo)
+	lockEntityInterfaceMockAppendToList.Unlock()
+	return mock.AppendToListFunc(attributeKey, values...)
+}
+
+// AppendToListCalls gets all the calls that were made to AppendToList.
+// Check the length with:
+//
+//	len(mockedEntityInterface.AppendToListCalls())
+func (mock *EntityInterfaceMock) AppendToListCalls() []struct {
+	AttributeKey string
+	Values       []string
+} {
+	var calls []struct {
+		AttributeKey string
+		Values       []string
+	}
+	lockEntityInterfaceMockAppendToList.RLock()
+	calls = mock.calls.AppendToList
+	lockEntityInterfaceMockAppendToList.RUnlock()
+	return calls
+}
+
+// CreatedAt calls CreatedAtFunc.
+func (mock *EntityInterfaceMock) CreatedAt() time.Time {
+	if mock.CreatedAtFunc == nil {
+		panic("EntityInterfaceMock.CreatedAtFunc: method is nil but EntityInterface.CreatedAt was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockCreatedAt.Lock()
+	mock.calls.CreatedAt = append(mock.calls.CreatedAt, callInfo)
+	lockEntityInterfaceMockCreatedAt.Unlock()
+	return mock.CreatedAtFunc()
+}
+
+// CreatedAtCalls gets all the calls that were made to CreatedAt.
+// Check the length with:
+//
+//	len(mockedEntityInterface.CreatedAtCalls())
+func (mock *EntityInterfaceMock) CreatedAtCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockCreatedAt.RLock()
+	calls = mock.calls.CreatedAt
+	lockEntityInterfaceMockCreatedAt.RUnlock()
+	return calls
+}
+
+// GetAttribute calls GetAttributeFunc.
+func (mock *EntityInterfaceMock) GetAttribute(attributeKey string) (*entitystore.Attribute, error) {
+	if mock.GetAttributeFunc == nil {
+		panic("EntityInterfaceMock.GetAttributeFunc: method is nil but EntityInterface.GetAttribute was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+	}{
+		AttributeKey: attributeKey,
+	}
+	lockEntityInterfaceMockGetAttribute.Lock()
+	mock.calls.GetAttribute = append(mock.calls.GetAttribute, callInfo)
+	lockEntityInterfaceMockGetAttribute.Unlock()
+	return mock.GetAttributeFunc(attributeKey)
+}
+
+// GetAttributeCalls gets all the calls that were made to GetAttribute.
+// Check the length with:
+//
+//	len(mockedEntityInterface.GetAttributeCalls())
+func (mock *EntityInterfaceMock) GetAttributeCalls() []struct {
+	AttributeKey string
+} {
+	var calls []struct {
+		AttributeKey string
+	}
+	lockEntityInterfaceMockGetAttribute.RLock()
+	calls = mock.calls.GetAttribute
+	lockEntityInterfaceMockGetAttribute.RUnlock()
+	return calls
+}
+
+// GetAttributes calls GetAttributesFunc.
+func (mock *EntityInterfaceMock) GetAttributes() ([]entitystore.Attribute, error) {
+	if mock.GetAttributesFunc == nil {
+		panic("EntityInterfaceMock.GetAttributesFunc: method is nil but EntityInterface.GetAttributes was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockGetAttributes.Lock()
+	mock.calls.GetAttributes = append(mock.calls.GetAttributes, callInfo)
+	lockEntityInterfaceMockGetAttributes.Unlock()
+	return mock.GetAttributesFunc()
+}
+
+// GetAttributesCalls gets all the calls that were made to GetAttributes.
+// Check the length with:
+//
+//	len(mockedEntityInterface.GetAttributesCalls())
+func (mock *EntityInterfaceMock) GetAttributesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockGetAttributes.RLock()
+	calls = mock.calls.GetAttributes
+	lockEntityInterfaceMockGetAttributes.RUnlock()
+	return calls
+}
+
+// GetFloat calls GetFloatFunc.
+func (mock *EntityInterfaceMock) GetFloat(attributeKey string, defaultValue float64) (float64, error) {
+	if mock.GetFloatFunc == nil {
+		panic("EntityInterfaceMock.GetFloatFunc: method is nil but EntityInterface.GetFloat was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		DefaultValue float64
+	}{
+		AttributeKey: attributeKey,
+		DefaultValue: defaultValue,
+	}
+	lockEntityInterfaceMockGetFloat.Lock()
+	mock.calls.GetFloat = append(mock.calls.GetFloat, callInfo)
+	lockEntityInterfaceMockGetFloat.Unlock()
+	return mock.GetFloatFunc(attributeKey, defaultValue)
+}
+
+// GetFloatCalls gets all the calls that were made to GetFloat.
+// Check the length with:
+//
+//	len(mockedEntityInterface.GetFloatCalls())
+func (mock *EntityInterfaceMock) GetFloatCalls() []struct {
+	AttributeKey string
+	DefaultValue float64
+} {
+	var calls []struct {
+		AttributeKey string
+		DefaultValue float64
+	}
+	lockEntityInterfaceMockGetFloat.RLock()
+	calls = mock.calls.GetFloat
+	lockEntityInterfaceMockGetFloat.RUnlock()
+	return calls
+}
+
+// GetInt calls GetIntFunc.
+func (mock *EntityInterfaceMock) GetInt(attributeKey string, defaultValue int64) (int64, error) {
+	if mock.GetIntFunc == nil {
+		panic("EntityInterfaceMock.GetIntFunc: method is nil but EntityInterface.GetInt was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		DefaultValue int64
+	}{
+		AttributeKey: attributeKey,
+		DefaultValue: defaultValue,
+	}
+	lockEntityInterfaceMockGetInt.Lock()
+	mock.calls.GetInt = append(mock.calls.GetInt, callInfo)
+	lockEntityInterfaceMockGetInt.Unlock()
+	return mock.GetIntFunc(attributeKey, defaultValue)
+}
+
+// GetIntCalls gets all the calls that were made to GetInt.
+// Check the length with:
+//
+//	len(mockedEntityInterface.GetIntCalls())
+func (mock *EntityInterfaceMock) GetIntCalls() []struct {
+	AttributeKey string
+	DefaultValue int64
+} {
+	var calls []struct {
+		AttributeKey string
+		DefaultValue int64
+	}
+	lockEntityInterfaceMockGetInt.RLock()
+	calls = mock.calls.GetInt
+	lockEntityInterfaceMockGetInt.RUnlock()
+	return calls
+}
+
+// GetList calls GetListFunc.
+func (mock *EntityInterfaceMock) GetList(attributeKey string) ([]string, error) {
+	if mock.GetListFunc == nil {
+		panic("EntityInterfaceMock.GetListFunc: method is nil but EntityInterface.GetList was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+	}{
+		AttributeKey: attributeKey,
+	}
+	lockEntityInterfaceMockGetList.Lock()
+	mock.calls.GetList = append(mock.calls.GetList, callInfo)
+	lockEntityInterfaceMockGetList.Unlock()
+	return mock.GetListFunc(attributeKey)
+}
+
+// GetListCalls gets all the calls that were made to GetList.
+// Check the length with:
+//
+//	len(mockedEntityInterface.GetListCalls())
+func (mock *EntityInterfaceMock) GetListCalls() []struct {
+	AttributeKey string
+} {
+	var calls []struct {
+		AttributeKey string
+	}
+	lockEntityInterfaceMockGetList.RLock()
+	calls = mock.calls.GetList
+	lockEntityInterfaceMockGetList.RUnlock()
+	return calls
+}
+
+// GetString calls GetStringFunc.
+func (mock *EntityInterfaceMock) GetString(attributeKey string, defaultValue string) (string, error) {
+	if mock.GetStringFunc == nil {
+		panic("EntityInterfaceMock.GetStringFunc: method is nil but EntityInterface.GetString was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		DefaultValue string
+	}{
+		AttributeKey: attributeKey,
+		DefaultValue: defaultValue,
+	}
+	lockEntityInterfaceMockGetString.Lock()
+	mock.calls.GetString = append(mock.calls.GetString, callInfo)
+	lockEntityInterfaceMockGetString.Unlock()
+	return mock.GetStringFunc(attributeKey, defaultValue)
+}
+
+// GetStringCalls gets all the calls that were made to GetString.
+// Check the length with:
+//
+//	len(mockedEntityInterface.GetStringCalls())
+func (mock *EntityInterfaceMock) GetStringCalls() []struct {
+	AttributeKey string
+	DefaultValue string
+} {
+	var calls []struct {
+		AttributeKey string
+		DefaultValue string
+	}
+	lockEntityInterfaceMockGetString.RLock()
+	calls = mock.calls.GetString
+	lockEntityInterfaceMockGetString.RUnlock()
+	return calls
+}
+
+// Handle calls HandleFunc.
+func (mock *EntityInterfaceMock) Handle() string {
+	if mock.HandleFunc == nil {
+		panic("EntityInterfaceMock.HandleFunc: method is nil but EntityInterface.Handle was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockHandle.Lock()
+	mock.calls.Handle = append(mock.calls.Handle, callInfo)
+	lockEntityInterfaceMockHandle.Unlock()
+	return mock.HandleFunc()
+}
+
+// HandleCalls gets all the calls that were made to Handle.
+// Check the length with:
+//
+//	len(mockedEntityInterface.HandleCalls())
+func (mock *EntityInterfaceMock) HandleCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockHandle.RLock()
+	calls = mock.calls.Handle
+	lockEntityInterfaceMockHandle.RUnlock()
+	return calls
+}
+
+// ID calls IDFunc.
+func (mock *EntityInterfaceMock) ID() string {
+	if mock.IDFunc == nil {
+		panic("EntityInterfaceMock.IDFunc: method is nil but EntityInterface.ID was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockID.Lock()
+	mock.calls.ID = append(mock.calls.ID, callInfo)
+	lockEntityInterfaceMockID.Unlock()
+	return mock.IDFunc()
+}
+
+// IDCalls gets all the calls that were made to ID.
+// Check the length with:
+//
+//	len(mockedEntityInterface.IDCalls())
+func (mock *EntityInterfaceMock) IDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockID.RLock()
+	calls = mock.calls.ID
+	lockEntityInterfaceMockID.RUnlock()
+	return calls
+}
+
+// Increment calls IncrementFunc.
+func (mock *EntityInterfaceMock) Increment(attributeKey string, delta int64) (int64, error) {
+	if mock.IncrementFunc == nil {
+		panic("EntityInterfaceMock.IncrementFunc: method is nil but EntityInterface.Increment was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		Delta        int64
+	}{
+		AttributeKey: attributeKey,
+		Delta:        delta,
+	}
+	lockEntityInterfaceMockIncrement.Lock()
+	mock.calls.Increment = append(mock.calls.Increment, callInfo)
+	lockEntityInterfaceMockIncrement.Unlock()
+	return mock.IncrementFunc(attributeKey, delta)
+}
+
+// IncrementCalls gets all the calls that were made to Increment.
+// Check the length with:
+//
+//	len(mockedEntityInterface.IncrementCalls())
+func (mock *EntityInterfaceMock) IncrementCalls() []struct {
+	AttributeKey string
+	Delta        int64
+} {
+	var calls []struct {
+		AttributeKey string
+		Delta        int64
+	}
+	lockEntityInterfaceMockIncrement.RLock()
+	calls = mock.calls.Increment
+	lockEntityInterfaceMockIncrement.RUnlock()
+	return calls
+}
+
+// IncrementFloat calls IncrementFloatFunc.
+func (mock *EntityInterfaceMock) IncrementFloat(attributeKey string, delta float64) (float64, error) {
+	if mock.IncrementFloatFunc == nil {
+		panic("EntityInterfaceMock.IncrementFloatFunc: method is nil but EntityInterface.IncrementFloat was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		Delta        float64
+	}{
+		AttributeKey: attributeKey,
+		Delta:        delta,
+	}
+	lockEntityInterfaceMockIncrementFloat.Lock()
+	mock.calls.IncrementFloat = append(mock.calls.IncrementFloat, callInfo)
+	lockEntityInterfaceMockIncrementFloat.Unlock()
+	return mock.IncrementFloatFunc(attributeKey, delta)
+}
+
+// IncrementFloatCalls gets all the calls that were made to IncrementFloat.
+// Check the length with:
+//
+//	len(mockedEntityInterface.IncrementFloatCalls())
+func (mock *EntityInterfaceMock) IncrementFloatCalls() []struct {
+	AttributeKey string
+	Delta        float64
+} {
+	var calls []struct {
+		AttributeKey string
+		Delta        float64
+	}
+	lockEntityInterfaceMockIncrementFloat.RLock()
+	calls = mock.calls.IncrementFloat
+	lockEntityInterfaceMockIncrementFloat.RUnlock()
+	return calls
+}
+
+// RemoveFromSet calls RemoveFromSetFunc.
+func (mock *EntityInterfaceMock) RemoveFromSet(attributeKey string, values ...string) error {
+	if mock.RemoveFromSetFunc == nil {
+		panic("EntityInterfaceMock.RemoveFromSetFunc: method is nil but EntityInterface.RemoveFromSet was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+		Values       []string
+	}{
+		AttributeKey: attributeKey,
+		Values:       values,
+	}
+	lockEntityInterfaceMockRemoveFromSet.Lock()
+	mock.calls.RemoveFromSet = append(mock.calls.RemoveFromSet, callInfo)
+	lockEntityInterfaceMockRemoveFromSet.Unlock()
+	return mock.RemoveFromSetFunc(attributeKey, values...)
+}
+
+// RemoveFromSetCalls gets all the calls that were made to RemoveFromSet.
+// Check the length with:
+//
+//	len(mockedEntityInterface.RemoveFromSetCalls())
+func (mock *EntityInterfaceMock) RemoveFromSetCalls() []struct {
+	AttributeKey string
+	Values       []string
+} {
+	var calls []struct {
+		AttributeKey string
+		Values       []string
+	}
+	lockEntityInterfaceMockRemoveFromSet.RLock()
+	calls = mock.calls.RemoveFromSet
+	lockEntityInterfaceMockRemoveFromSet.RUnlock()
+	return calls
+}
+
+// SetAll calls SetAllFunc.
+func (mock *EntityInterfaceMock) SetAll(attributes map[string]string) error {
+	if mock.SetAllFunc == nil {
+		panic("EntityInterfaceMock.SetAllFunc: method is nil but EntityInterface.SetAll was just called")
+	}
+	callInfo := struct {
+		Attributes map[string]string
+	}{
+		Attributes: attributes,
+	}
+	lockEntityInterfaceMockSetAll.Lock()
+	mock.calls.SetAll = append(mock.calls.SetAll, callInfo)
+	lockEntityInterfaceMockSetAll.Unlock()
+	return mock.SetAllFunc(attributes)
+}
+
+// SetAllCalls gets all the calls that were made to SetAll.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetAllCalls())
+func (mock *EntityInterfaceMock) SetAllCalls() []struct {
+	Attributes map[string]string
+} {
+	var calls []struct {
+		Attributes map[string]string
+	}
+	lockEntityInterfaceMockSetAll.RLock()
+	calls = mock.calls.SetAll
+	lockEntityInterfaceMockSetAll.RUnlock()
+	return calls
+}
+
+// SetCreatedAt calls SetCreatedAtFunc.
+func (mock *EntityInterfaceMock) SetCreatedAt(createdAt time.Time) *entitystore.Entity {
+	if mock.SetCreatedAtFunc == nil {
+		panic("EntityInterfaceMock.SetCreatedAtFunc: method is nil but EntityInterface.SetCreatedAt was just called")
+	}
+	callInfo := struct {
+		CreatedAt time.Time
+	}{
+		CreatedAt: createdAt,
+	}
+	lockEntityInterfaceMockSetCreatedAt.Lock()
+	mock.calls.SetCreatedAt = append(mock.calls.SetCreatedAt, callInfo)
+	lockEntityInterfaceMockSetCreatedAt.Unlock()
+	return mock.SetCreatedAtFunc(createdAt)
+}
+
+// SetCreatedAtCalls gets all the calls that were made to SetCreatedAt.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetCreatedAtCalls())
+func (mock *EntityInterfaceMock) SetCreatedAtCalls() []struct {
+	CreatedAt time.Time
+} {
+	var calls []struct {
+		CreatedAt time.Time
+	}
+	lockEntityInterfaceMockSetCreatedAt.RLock()
+	calls = mock.calls.SetCreatedAt
+	lockEntityInterfaceMockSetCreatedAt.RUnlock()
+	return calls
+}
+
+// SetFloat calls SetFloatFunc.
+func (mock *EntityInterfaceMock) SetFloat(attributeKey string, attributeValue float64) error {
+	if mock.SetFloatFunc == nil {
+		panic("EntityInterfaceMock.SetFloatFunc: method is nil but EntityInterface.SetFloat was just called")
+	}
+	callInfo := struct {
+		AttributeKey   string
+		AttributeValue float64
+	}{
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockEntityInterfaceMockSetFloat.Lock()
+	mock.calls.SetFloat = append(mock.calls.SetFloat, callInfo)
+	lockEntityInterfaceMockSetFloat.Unlock()
+	return mock.SetFloatFunc(attributeKey, attributeValue)
+}
+
+// SetFloatCalls gets all the calls that were made to SetFloat.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetFloatCalls())
+func (mock *EntityInterfaceMock) SetFloatCalls() []struct {
+	AttributeKey   string
+	AttributeValue float64
+} {
+	var calls []struct {
+		AttributeKey   string
+		AttributeValue float64
+	}
+	lockEntityInterfaceMockSetFloat.RLock()
+	calls = mock.calls.SetFloat
+	lockEntityInterfaceMockSetFloat.RUnlock()
+	return calls
+}
+
+// SetHandle calls SetHandleFunc.
+func (mock *EntityInterfaceMock) SetHandle(handle string) *entitystore.Entity {
+	if mock.SetHandleFunc == nil {
+		panic("EntityInterfaceMock.SetHandleFunc: method is nil but EntityInterface.SetHandle was just called")
+	}
+	callInfo := struct {
+		Handle string
+	}{
+		Handle: handle,
+	}
+	lockEntityInterfaceMockSetHandle.Lock()
+	mock.calls.SetHandle = append(mock.calls.SetHandle, callInfo)
+	lockEntityInterfaceMockSetHandle.Unlock()
+	return mock.SetHandleFunc(handle)
+}
+
+// SetHandleCalls gets all the calls that were made to SetHandle.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetHandleCalls())
+func (mock *EntityInterfaceMock) SetHandleCalls() []struct {
+	Handle string
+} {
+	var calls []struct {
+		Handle string
+	}
+	lockEntityInterfaceMockSetHandle.RLock()
+	calls = mock.calls.SetHandle
+	lockEntityInterfaceMockSetHandle.RUnlock()
+	return calls
+}
+
+// SetID calls SetIDFunc.
+func (mock *EntityInterfaceMock) SetID(id string) *entitystore.Entity {
+	if mock.SetIDFunc == nil {
+		panic("EntityInterfaceMock.SetIDFunc: method is nil but EntityInterface.SetID was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	lockEntityInterfaceMockSetID.Lock()
+	mock.calls.SetID = append(mock.calls.SetID, callInfo)
+	lockEntityInterfaceMockSetID.Unlock()
+	return mock.SetIDFunc(id)
+}
+
+// SetIDCalls gets all the calls that were made to SetID.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetIDCalls())
+func (mock *EntityInterfaceMock) SetIDCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockEntityInterfaceMockSetID.RLock()
+	calls = mock.calls.SetID
+	lockEntityInterfaceMockSetID.RUnlock()
+	return calls
+}
+
+// SetInt calls SetIntFunc.
+func (mock *EntityInterfaceMock) SetInt(attributeKey string, attributeValue int64) error {
+	if mock.SetIntFunc == nil {
+		panic("EntityInterfaceMock.SetIntFunc: method is nil but EntityInterface.SetInt was just called")
+	}
+	callInfo := struct {
+		AttributeKey   string
+		AttributeValue int64
+	}{
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockEntityInterfaceMockSetInt.Lock()
+	mock.calls.SetInt = append(mock.calls.SetInt, callInfo)
+	lockEntityInterfaceMockSetInt.Unlock()
+	return mock.SetIntFunc(attributeKey, attributeValue)
+}
+
+// SetIntCalls gets all the calls that were made to SetInt.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetIntCalls())
+func (mock *EntityInterfaceMock) SetIntCalls() []struct {
+	AttributeKey   string
+	AttributeValue int64
+} {
+	var calls []struct {
+		AttributeKey   string
+		AttributeValue int64
+	}
+	lockEntityInterfaceMockSetInt.RLock()
+	calls = mock.calls.SetInt
+	lockEntityInterfaceMockSetInt.RUnlock()
+	return calls
+}
+
+// SetString calls SetStringFunc.
+func (mock *EntityInterfaceMock) SetString(attributeKey string, attributeValue string) error {
+	if mock.SetStringFunc == nil {
+		panic("EntityInterfaceMock.SetStringFunc: method is nil but EntityInterface.SetString was just called")
+	}
+	callInfo := struct {
+		AttributeKey   string
+		AttributeValue string
+	}{
+		AttributeKey:   attributeKey,
+		AttributeValue: attributeValue,
+	}
+	lockEntityInterfaceMockSetString.Lock()
+	mock.calls.SetString = append(mock.calls.SetString, callInfo)
+	lockEntityInterfaceMockSetString.Unlock()
+	return mock.SetStringFunc(attributeKey, attributeValue)
+}
+
+// SetStringCalls gets all the calls that were made to SetString.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetStringCalls())
+func (mock *EntityInterfaceMock) SetStringCalls() []struct {
+	AttributeKey   string
+	AttributeValue string
+} {
+	var calls []struct {
+		AttributeKey   string
+		AttributeValue string
+	}
+	lockEntityInterfaceMockSetString.RLock()
+	calls = mock.calls.SetString
+	lockEntityInterfaceMockSetString.RUnlock()
+	return calls
+}
+
+// SetType calls SetTypeFunc.
+func (mock *EntityInterfaceMock) SetType(entityType string) *entitystore.Entity {
+	if mock.SetTypeFunc == nil {
+		panic("EntityInterfaceMock.SetTypeFunc: method is nil but EntityInterface.SetType was just called")
+	}
+	callInfo := struct {
+		EntityType string
+	}{
+		EntityType: entityType,
+	}
+	lockEntityInterfaceMockSetType.Lock()
+	mock.calls.SetType = append(mock.calls.SetType, callInfo)
+	lockEntityInterfaceMockSetType.Unlock()
+	return mock.SetTypeFunc(entityType)
+}
+
+// SetTypeCalls gets all the calls that were made to SetType.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetTypeCalls())
+func (mock *EntityInterfaceMock) SetTypeCalls() []struct {
+	EntityType string
+} {
+	var calls []struct {
+		EntityType string
+	}
+	lockEntityInterfaceMockSetType.RLock()
+	calls = mock.calls.SetType
+	lockEntityInterfaceMockSetType.RUnlock()
+	return calls
+}
+
+// SetUpdatedAt calls SetUpdatedAtFunc.
+func (mock *EntityInterfaceMock) SetUpdatedAt(updatedAt time.Time) *entitystore.Entity {
+	if mock.SetUpdatedAtFunc == nil {
+		panic("EntityInterfaceMock.SetUpdatedAtFunc: method is nil but EntityInterface.SetUpdatedAt was just called")
+	}
+	callInfo := struct {
+		UpdatedAt time.Time
+	}{
+		UpdatedAt: updatedAt,
+	}
+	lockEntityInterfaceMockSetUpdatedAt.Lock()
+	mock.calls.SetUpdatedAt = append(mock.calls.SetUpdatedAt, callInfo)
+	lockEntityInterfaceMockSetUpdatedAt.Unlock()
+	return mock.SetUpdatedAtFunc(updatedAt)
+}
+
+// SetUpdatedAtCalls gets all the calls that were made to SetUpdatedAt.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetUpdatedAtCalls())
+func (mock *EntityInterfaceMock) SetUpdatedAtCalls() []struct {
+	UpdatedAt time.Time
+} {
+	var calls []struct {
+		UpdatedAt time.Time
+	}
+	lockEntityInterfaceMockSetUpdatedAt.RLock()
+	calls = mock.calls.SetUpdatedAt
+	lockEntityInterfaceMockSetUpdatedAt.RUnlock()
+	return calls
+}
+
+// SetVersion calls SetVersionFunc.
+func (mock *EntityInterfaceMock) SetVersion(version int64) *entitystore.Entity {
+	if mock.SetVersionFunc == nil {
+		panic("EntityInterfaceMock.SetVersionFunc: method is nil but EntityInterface.SetVersion was just called")
+	}
+	callInfo := struct {
+		Version int64
+	}{
+		Version: version,
+	}
+	lockEntityInterfaceMockSetVersion.Lock()
+	mock.calls.SetVersion = append(mock.calls.SetVersion, callInfo)
+	lockEntityInterfaceMockSetVersion.Unlock()
+	return mock.SetVersionFunc(version)
+}
+
+// SetVersionCalls gets all the calls that were made to SetVersion.
+// Check the length with:
+//
+//	len(mockedEntityInterface.SetVersionCalls())
+func (mock *EntityInterfaceMock) SetVersionCalls() []struct {
+	Version int64
+} {
+	var calls []struct {
+		Version int64
+	}
+	lockEntityInterfaceMockSetVersion.RLock()
+	calls = mock.calls.SetVersion
+	lockEntityInterfaceMockSetVersion.RUnlock()
+	return calls
+}
+
+// ToMap calls ToMapFunc.
+func (mock *EntityInterfaceMock) ToMap() map[string]any {
+	if mock.ToMapFunc == nil {
+		panic("EntityInterfaceMock.ToMapFunc: method is nil but EntityInterface.ToMap was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockToMap.Lock()
+	mock.calls.ToMap = append(mock.calls.ToMap, callInfo)
+	lockEntityInterfaceMockToMap.Unlock()
+	return mock.ToMapFunc()
+}
+
+// ToMapCalls gets all the calls that were made to ToMap.
+// Check the length with:
+//
+//	len(mockedEntityInterface.ToMapCalls())
+func (mock *EntityInterfaceMock) ToMapCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockToMap.RLock()
+	calls = mock.calls.ToMap
+	lockEntityInterfaceMockToMap.RUnlock()
+	return calls
+}
+
+// Type calls TypeFunc.
+func (mock *EntityInterfaceMock) Type() string {
+	if mock.TypeFunc == nil {
+		panic("EntityInterfaceMock.TypeFunc: method is nil but EntityInterface.Type was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockType.Lock()
+	mock.calls.Type = append(mock.calls.Type, callInfo)
+	lockEntityInterfaceMockType.Unlock()
+	return mock.TypeFunc()
+}
+
+// TypeCalls gets all the calls that were made to Type.
+// Check the length with:
+//
+//	len(mockedEntityInterface.TypeCalls())
+func (mock *EntityInterfaceMock) TypeCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockType.RLock()
+	calls = mock.calls.Type
+	lockEntityInterfaceMockType.RUnlock()
+	return calls
+}
+
+// UpdatedAt calls UpdatedAtFunc.
+func (mock *EntityInterfaceMock) UpdatedAt() time.Time {
+	if mock.UpdatedAtFunc == nil {
+		panic("EntityInterfaceMock.UpdatedAtFunc: method is nil but EntityInterface.UpdatedAt was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockUpdatedAt.Lock()
+	mock.calls.UpdatedAt = append(mock.calls.UpdatedAt, callInfo)
+	lockEntityInterfaceMockUpdatedAt.Unlock()
+	return mock.UpdatedAtFunc()
+}
+
+// UpdatedAtCalls gets all the calls that were made to UpdatedAt.
+// Check the length with:
+//
+//	len(mockedEntityInterface.UpdatedAtCalls())
+func (mock *EntityInterfaceMock) UpdatedAtCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockUpdatedAt.RLock()
+	calls = mock.calls.UpdatedAt
+	lockEntityInterfaceMockUpdatedAt.RUnlock()
+	return calls
+}
+
+// Version calls VersionFunc.
+func (mock *EntityInterfaceMock) Version() int64 {
+	if mock.VersionFunc == nil {
+		panic("EntityInterfaceMock.VersionFunc: method is nil but EntityInterface.Version was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockEntityInterfaceMockVersion.Lock()
+	mock.calls.Version = append(mock.calls.Version, callInfo)
+	lockEntityInterfaceMockVersion.Unlock()
+	return mock.VersionFunc()
+}
+
+// VersionCalls gets all the calls that were made to Version.
+// Check the length with:
+//
+//	len(mockedEntityInterface.VersionCalls())
+func (mock *EntityInterfaceMock) VersionCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockEntityInterfaceMockVersion.RLock()
+	calls = mock.calls.Version
+	lockEntityInterfaceMockVersion.RUnlock()
+	return calls
+}
+
+var (
+	lockAttributeInterfaceMockAttributeKey      sync.RWMutex
+	lockAttributeInterfaceMockAttributeValue    sync.RWMutex
+	lockAttributeInterfaceMockCreatedAt         sync.RWMutex
+	lockAttributeInterfaceMockEntityID          sync.RWMutex
+	lockAttributeInterfaceMockGetFloat          sync.RWMutex
+	lockAttributeInterfaceMockGetInt            sync.RWMutex
+	lockAttributeInterfaceMockGetString         sync.RWMutex
+	lockAttributeInterfaceMockID                sync.RWMutex
+	lockAttributeInterfaceMockSetAttributeKey   sync.RWMutex
+	lockAttributeInterfaceMockSetAttributeValue sync.RWMutex
+	lockAttributeInterfaceMockSetCreatedAt      sync.RWMutex
+	lockAttributeInterfaceMockSetEntityID       sync.RWMutex
+	lockAttributeInterfaceMockSetFloat          sync.RWMutex
+	lockAttributeInterfaceMockSetID             sync.RWMutex
+	lockAttributeInterfaceMockSetInt            sync.RWMutex
+	lockAttributeInterfaceMockSetString         sync.RWMutex
+	lockAttributeInterfaceMockSetUpdatedAt      sync.RWMutex
+	lockAttributeInterfaceMockToMap             sync.RWMutex
+	lockAttributeInterfaceMockUpdatedAt         sync.RWMutex
+)
+
+// Ensure, that AttributeInterfaceMock does implement AttributeInterface.
+// If this is not the case, regenerate this file with moq.
+var _ entitystore.AttributeInterface = &AttributeInterfaceMock{}
+
+// AttributeInterfaceMock is a mock implementation of AttributeInterface.
+//
+//	    func TestSomethingThatUsesAttributeInterface(t *testing.T) {
+//
+//	        // make and configure a mocked AttributeInterface
+//	        mockedAttributeInterface := &AttributeInterfaceMock{
+//	            AttributeKeyFunc: func() string {
+//		               panic("mock out the AttributeKey method")
+//	            },
+//	            AttributeValueFunc: func() string {
+//		               panic("mock out the AttributeValue method")
+//	            },
+//	            CreatedAtFunc: func() time.Time {
+//		               panic("mock out the CreatedAt method")
+//	            },
+//	            EntityIDFunc: func() string {
+//		               panic("mock out the EntityID method")
+//	            },
+//	            GetFloatFunc: func() (float64, error) {
+//		               panic("mock out the GetFloat method")
+//	            },
+//	            GetIntFunc: func() (int64, error) {
+//		               panic("mock out the GetInt method")
+//	            },
+//	            GetStringFunc: func() string {
+//		               panic("mock out the GetString method")
+//	            },
+//	            IDFunc: func() string {
+//		               panic("mock out the ID method")
+//	            },
+//	            SetAttributeKeyFunc: func(attributeKey string) *entitystore.Attribute {
+//		               panic("mock out the SetAttributeKey method")
+//	            },
+//	            SetAttributeValueFunc: func(attributeValue string) *entitystore.Attribute {
+//		               panic("mock out the SetAttributeValue method")
+//	            },
+//	            SetCreatedAtFunc: func(createdAt time.Time) *entitystore.Attribute {
+//		               panic("mock out the SetCreatedAt method")
+//	            },
+//	            SetEntityIDFunc: func(entityID string) *entitystore.Attribute {
+//		               panic("mock out the SetEntityID method")
+//	            },
+//	            SetFloatFunc: func(value float64) bool {
+//		               panic("mock out the SetFloat method")
+//	            },
+//	            SetIDFunc: func(id string) *entitystore.Attribute {
+//		               panic("mock out the SetID method")
+//	            },
+//	            SetIntFunc: func(value int64) bool {
+//		               panic("mock out the SetInt method")
+//	            },
+//	            SetStringFunc: func(value string) bool {
+//		               panic("mock out the SetString method")
+//	            },
+//	            SetUpdatedAtFunc: func(updatedAt time.Time) *entitystore.Attribute {
+//		               panic("mock out the SetUpdatedAt method")
+//	            },
+//	            ToMapFunc: func() map[string]any {
+//		               panic("mock out the ToMap method")
+//	            },
+//	            UpdatedAtFunc: func() time.Time {
+//		               panic("mock out the UpdatedAt method")
+//	            },
+//	        }
+//
+//	        // use mockedAttributeInterface in code that requires AttributeInterface
+//	        // and then make assertions.
+//
+//	    }
+type AttributeInterfaceMock struct {
+	// AttributeKeyFunc mocks the AttributeKey method.
+	AttributeKeyFunc func() string
+
+	// AttributeValueFunc mocks the AttributeValue method.
+	AttributeValueFunc func() string
+
+	// CreatedAtFunc mocks the CreatedAt method.
+	CreatedAtFunc func() time.Time
+
+	// EntityIDFunc mocks the EntityID method.
+	EntityIDFunc func() string
+
+	// GetFloatFunc mocks the GetFloat method.
+	GetFloatFunc func() (float64, error)
+
+	// GetIntFunc mocks the GetInt method.
+	GetIntFunc func() (int64, error)
+
+	// GetStringFunc mocks the GetString method.
+	GetStringFunc func() string
+
+	// IDFunc mocks the ID method.
+	IDFunc func() string
+
+	// SetAttributeKeyFunc mocks the SetAttributeKey method.
+	SetAttributeKeyFunc func(attributeKey string) *entitystore.Attribute
+
+	// SetAttributeValueFunc mocks the SetAttributeValue method.
+	SetAttributeValueFunc func(attributeValue string) *entitystore.Attribute
+
+	// SetCreatedAtFunc mocks the SetCreatedAt method.
+	SetCreatedAtFunc func(createdAt time.Time) *entitystore.Attribute
+
+	// SetEntityIDFunc mocks the SetEntityID method.
+	SetEntityIDFunc func(entityID string) *entitystore.Attribute
+
+	// SetFloatFunc mocks the SetFloat method.
+	SetFloatFunc func(value float64) bool
+
+	// SetIDFunc mocks the SetID method.
+	SetIDFunc func(id string) *entitystore.Attribute
+
+	// SetIntFunc mocks the SetInt method.
+	SetIntFunc func(value int64) bool
+
+	// SetStringFunc mocks the SetString method.
+	SetStringFunc func(value string) bool
+
+	// SetUpdatedAtFunc mocks the SetUpdatedAt method.
+	SetUpdatedAtFunc func(updatedAt time.Time) *entitystore.Attribute
+
+	// ToMapFunc mocks the ToMap method.
+	ToMapFunc func() map[string]any
+
+	// UpdatedAtFunc mocks the UpdatedAt method.
+	UpdatedAtFunc func() time.Time
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AttributeKey holds details about calls to the AttributeKey method.
+		AttributeKey []struct {
+		}
+		// AttributeValue holds details about calls to the AttributeValue method.
+		AttributeValue []struct {
+		}
+		// CreatedAt holds details about calls to the CreatedAt method.
+		CreatedAt []struct {
+		}
+		// EntityID holds details about calls to the EntityID method.
+		EntityID []struct {
+		}
+		// GetFloat holds details about calls to the GetFloat method.
+		GetFloat []struct {
+		}
+		// GetInt holds details about calls to the GetInt method.
+		GetInt []struct {
+		}
+		// GetString holds details about calls to the GetString method.
+		GetString []struct {
+		}
+		// ID holds details about calls to the ID method.
+		ID []struct {
+		}
+		// SetAttributeKey holds details about calls to the SetAttributeKey method.
+		SetAttributeKey []struct {
+			// AttributeKey is the attributeKey argument value.
+			AttributeKey string
+		}
+		// SetAttributeValue holds details about calls to the SetAttributeValue method.
+		SetAttributeValue []struct {
+			// AttributeValue is the attributeValue argument value.
+			AttributeValue string
+		}
+		// SetCreatedAt holds details about calls to the SetCreatedAt method.
+		SetCreatedAt []struct {
+			// CreatedAt is the createdAt argument value.
+			CreatedAt time.Time
+		}
+		// SetEntityID holds details about calls to the SetEntityID method.
+		SetEntityID []struct {
+			// EntityID is the entityID argument value.
+			EntityID string
+		}
+		// SetFloat holds details about calls to the SetFloat method.
+		SetFloat []struct {
+			// Value is the value argument value.
+			Value float64
+		}
+		// SetID holds details about calls to the SetID method.
+		SetID []struct {
+			// ID is the id argument value.
+			ID string
+		}
+		// SetInt holds details about calls to the SetInt method.
+		SetInt []struct {
+			// Value is the value argument value.
+			Value int64
+		}
+		// SetString holds details about calls to the SetString method.
+		SetString []struct {
+			// Value is the value argument value.
+			Value string
+		}
+		// SetUpdatedAt holds details about calls to the SetUpdatedAt method.
+		SetUpdatedAt []struct {
+			// UpdatedAt is the updatedAt argument value.
+			UpdatedAt time.Time
+		}
+		// ToMap holds details about calls to the ToMap method.
+		ToMap []struct {
+		}
+		// UpdatedAt holds details about calls to the UpdatedAt method.
+		UpdatedAt []struct {
+		}
+	}
+}
+
+// AttributeKey calls AttributeKeyFunc.
+func (mock *AttributeInterfaceMock) AttributeKey() string {
+	if mock.AttributeKeyFunc == nil {
+		panic("AttributeInterfaceMock.AttributeKeyFunc: method is nil but AttributeInterface.AttributeKey was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockAttributeKey.Lock()
+	mock.calls.AttributeKey = append(mock.calls.AttributeKey, callInfo)
+	lockAttributeInterfaceMockAttributeKey.Unlock()
+	return mock.AttributeKeyFunc()
+}
+
+// AttributeKeyCalls gets all the calls that were made to AttributeKey.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.AttributeKeyCalls())
+func (mock *AttributeInterfaceMock) AttributeKeyCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockAttributeKey.RLock()
+	calls = mock.calls.AttributeKey
+	lockAttributeInterfaceMockAttributeKey.RUnlock()
+	return calls
+}
+
+// AttributeValue calls AttributeValueFunc.
+func (mock *AttributeInterfaceMock) AttributeValue() string {
+	if mock.AttributeValueFunc == nil {
+		panic("AttributeInterfaceMock.AttributeValueFunc: method is nil but AttributeInterface.AttributeValue was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockAttributeValue.Lock()
+	mock.calls.AttributeValue = append(mock.calls.AttributeValue, callInfo)
+	lockAttributeInterfaceMockAttributeValue.Unlock()
+	return mock.AttributeValueFunc()
+}
+
+// AttributeValueCalls gets all the calls that were made to AttributeValue.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.AttributeValueCalls())
+func (mock *AttributeInterfaceMock) AttributeValueCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockAttributeValue.RLock()
+	calls = mock.calls.AttributeValue
+	lockAttributeInterfaceMockAttributeValue.RUnlock()
+	return calls
+}
+
+// CreatedAt calls CreatedAtFunc.
+func (mock *AttributeInterfaceMock) CreatedAt() time.Time {
+	if mock.CreatedAtFunc == nil {
+		panic("AttributeInterfaceMock.CreatedAtFunc: method is nil but AttributeInterface.CreatedAt was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockCreatedAt.Lock()
+	mock.calls.CreatedAt = append(mock.calls.CreatedAt, callInfo)
+	lockAttributeInterfaceMockCreatedAt.Unlock()
+	return mock.CreatedAtFunc()
+}
+
+// CreatedAtCalls gets all the calls that were made to CreatedAt.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.CreatedAtCalls())
+func (mock *AttributeInterfaceMock) CreatedAtCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockCreatedAt.RLock()
+	calls = mock.calls.CreatedAt
+	lockAttributeInterfaceMockCreatedAt.RUnlock()
+	return calls
+}
+
+// EntityID calls EntityIDFunc.
+func (mock *AttributeInterfaceMock) EntityID() string {
+	if mock.EntityIDFunc == nil {
+		panic("AttributeInterfaceMock.EntityIDFunc: method is nil but AttributeInterface.EntityID was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockEntityID.Lock()
+	mock.calls.EntityID = append(mock.calls.EntityID, callInfo)
+	lockAttributeInterfaceMockEntityID.Unlock()
+	return mock.EntityIDFunc()
+}
+
+// EntityIDCalls gets all the calls that were made to EntityID.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.EntityIDCalls())
+func (mock *AttributeInterfaceMock) EntityIDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockEntityID.RLock()
+	calls = mock.calls.EntityID
+	lockAttributeInterfaceMockEntityID.RUnlock()
+	return calls
+}
+
+// GetFloat calls GetFloatFunc.
+func (mock *AttributeInterfaceMock) GetFloat() (float64, error) {
+	if mock.GetFloatFunc == nil {
+		panic("AttributeInterfaceMock.GetFloatFunc: method is nil but AttributeInterface.GetFloat was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockGetFloat.Lock()
+	mock.calls.GetFloat = append(mock.calls.GetFloat, callInfo)
+	lockAttributeInterfaceMockGetFloat.Unlock()
+	return mock.GetFloatFunc()
+}
+
+// GetFloatCalls gets all the calls that were made to GetFloat.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.GetFloatCalls())
+func (mock *AttributeInterfaceMock) GetFloatCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockGetFloat.RLock()
+	calls = mock.calls.GetFloat
+	lockAttributeInterfaceMockGetFloat.RUnlock()
+	return calls
+}
+
+// GetInt calls GetIntFunc.
+func (mock *AttributeInterfaceMock) GetInt() (int64, error) {
+	if mock.GetIntFunc == nil {
+		panic("AttributeInterfaceMock.GetIntFunc: method is nil but AttributeInterface.GetInt was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockGetInt.Lock()
+	mock.calls.GetInt = append(mock.calls.GetInt, callInfo)
+	lockAttributeInterfaceMockGetInt.Unlock()
+	return mock.GetIntFunc()
+}
+
+// GetIntCalls gets all the calls that were made to GetInt.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.GetIntCalls())
+func (mock *AttributeInterfaceMock) GetIntCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockGetInt.RLock()
+	calls = mock.calls.GetInt
+	lockAttributeInterfaceMockGetInt.RUnlock()
+	return calls
+}
+
+// GetString calls GetStringFunc.
+func (mock *AttributeInterfaceMock) GetString() string {
+	if mock.GetStringFunc == nil {
+		panic("AttributeInterfaceMock.GetStringFunc: method is nil but AttributeInterface.GetString was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockGetString.Lock()
+	mock.calls.GetString = append(mock.calls.GetString, callInfo)
+	lockAttributeInterfaceMockGetString.Unlock()
+	return mock.GetStringFunc()
+}
+
+// GetStringCalls gets all the calls that were made to GetString.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.GetStringCalls())
+func (mock *AttributeInterfaceMock) GetStringCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockGetString.RLock()
+	calls = mock.calls.GetString
+	lockAttributeInterfaceMockGetString.RUnlock()
+	return calls
+}
+
+// ID calls IDFunc.
+func (mock *AttributeInterfaceMock) ID() string {
+	if mock.IDFunc == nil {
+		panic("AttributeInterfaceMock.IDFunc: method is nil but AttributeInterface.ID was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockID.Lock()
+	mock.calls.ID = append(mock.calls.ID, callInfo)
+	lockAttributeInterfaceMockID.Unlock()
+	return mock.IDFunc()
+}
+
+// IDCalls gets all the calls that were made to ID.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.IDCalls())
+func (mock *AttributeInterfaceMock) IDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockID.RLock()
+	calls = mock.calls.ID
+	lockAttributeInterfaceMockID.RUnlock()
+	return calls
+}
+
+// SetAttributeKey calls SetAttributeKeyFunc.
+func (mock *AttributeInterfaceMock) SetAttributeKey(attributeKey string) *entitystore.Attribute {
+	if mock.SetAttributeKeyFunc == nil {
+		panic("AttributeInterfaceMock.SetAttributeKeyFunc: method is nil but AttributeInterface.SetAttributeKey was just called")
+	}
+	callInfo := struct {
+		AttributeKey string
+	}{
+		AttributeKey: attributeKey,
+	}
+	lockAttributeInterfaceMockSetAttributeKey.Lock()
+	mock.calls.SetAttributeKey = append(mock.calls.SetAttributeKey, callInfo)
+	lockAttributeInterfaceMockSetAttributeKey.Unlock()
+	return mock.SetAttributeKeyFunc(attributeKey)
+}
+
+// SetAttributeKeyCalls gets all the calls that were made to SetAttributeKey.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetAttributeKeyCalls())
+func (mock *AttributeInterfaceMock) SetAttributeKeyCalls() []struct {
+	AttributeKey string
+} {
+	var calls []struct {
+		AttributeKey string
+	}
+	lockAttributeInterfaceMockSetAttributeKey.RLock()
+	calls = mock.calls.SetAttributeKey
+	lockAttributeInterfaceMockSetAttributeKey.RUnlock()
+	return calls
+}
+
+// SetAttributeValue calls SetAttributeValueFunc.
+func (mock *AttributeInterfaceMock) SetAttributeValue(attributeValue string) *entitystore.Attribute {
+	if mock.SetAttributeValueFunc == nil {
+		panic("AttributeInterfaceMock.SetAttributeValueFunc: method is nil but AttributeInterface.SetAttributeValue was just called")
+	}
+	callInfo := struct {
+		AttributeValue string
+	}{
+		AttributeValue: attributeValue,
+	}
+	lockAttributeInterfaceMockSetAttributeValue.Lock()
+	mock.calls.SetAttributeValue = append(mock.calls.SetAttributeValue, callInfo)
+	lockAttributeInterfaceMockSetAttributeValue.Unlock()
+	return mock.SetAttributeValueFunc(attributeValue)
+}
+
+// SetAttributeValueCalls gets all the calls that were made to SetAttributeValue.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetAttributeValueCalls())
+func (mock *AttributeInterfaceMock) SetAttributeValueCalls() []struct {
+	AttributeValue string
+} {
+	var calls []struct {
+		AttributeValue string
+	}
+	lockAttributeInterfaceMockSetAttributeValue.RLock()
+	calls = mock.calls.SetAttributeValue
+	lockAttributeInterfaceMockSetAttributeValue.RUnlock()
+	return calls
+}
+
+// SetCreatedAt calls SetCreatedAtFunc.
+func (mock *AttributeInterfaceMock) SetCreatedAt(createdAt time.Time) *entitystore.Attribute {
+	if mock.SetCreatedAtFunc == nil {
+		panic("AttributeInterfaceMock.SetCreatedAtFunc: method is nil but AttributeInterface.SetCreatedAt was just called")
+	}
+	callInfo := struct {
+		CreatedAt time.Time
+	}{
+		CreatedAt: createdAt,
+	}
+	lockAttributeInterfaceMockSetCreatedAt.Lock()
+	mock.calls.SetCreatedAt = append(mock.calls.SetCreatedAt, callInfo)
+	lockAttributeInterfaceMockSetCreatedAt.Unlock()
+	return mock.SetCreatedAtFunc(createdAt)
+}
+
+// SetCreatedAtCalls gets all the calls that were made to SetCreatedAt.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetCreatedAtCalls())
+func (mock *AttributeInterfaceMock) SetCreatedAtCalls() []struct {
+	CreatedAt time.Time
+} {
+	var calls []struct {
+		CreatedAt time.Time
+	}
+	lockAttributeInterfaceMockSetCreatedAt.RLock()
+	calls = mock.calls.SetCreatedAt
+	lockAttributeInterfaceMockSetCreatedAt.RUnlock()
+	return calls
+}
+
+// SetEntityID calls SetEntityIDFunc.
+func (mock *AttributeInterfaceMock) SetEntityID(entityID string) *entitystore.Attribute {
+	if mock.SetEntityIDFunc == nil {
+		panic("AttributeInterfaceMock.SetEntityIDFunc: method is nil but AttributeInterface.SetEntityID was just called")
+	}
+	callInfo := struct {
+		EntityID string
+	}{
+		EntityID: entityID,
+	}
+	lockAttributeInterfaceMockSetEntityID.Lock()
+	mock.calls.SetEntityID = append(mock.calls.SetEntityID, callInfo)
+	lockAttributeInterfaceMockSetEntityID.Unlock()
+	return mock.SetEntityIDFunc(entityID)
+}
+
+// SetEntityIDCalls gets all the calls that were made to SetEntityID.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetEntityIDCalls())
+func (mock *AttributeInterfaceMock) SetEntityIDCalls() []struct {
+	EntityID string
+} {
+	var calls []struct {
+		EntityID string
+	}
+	lockAttributeInterfaceMockSetEntityID.RLock()
+	calls = mock.calls.SetEntityID
+	lockAttributeInterfaceMockSetEntityID.RUnlock()
+	return calls
+}
+
+// SetFloat calls SetFloatFunc.
+func (mock *AttributeInterfaceMock) SetFloat(value float64) bool {
+	if mock.SetFloatFunc == nil {
+		panic("AttributeInterfaceMock.SetFloatFunc: method is nil but AttributeInterface.SetFloat was just called")
+	}
+	callInfo := struct {
+		Value float64
+	}{
+		Value: value,
+	}
+	lockAttributeInterfaceMockSetFloat.Lock()
+	mock.calls.SetFloat = append(mock.calls.SetFloat, callInfo)
+	lockAttributeInterfaceMockSetFloat.Unlock()
+	return mock.SetFloatFunc(value)
+}
+
+// SetFloatCalls gets all the calls that were made to SetFloat.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetFloatCalls())
+func (mock *AttributeInterfaceMock) SetFloatCalls() []struct {
+	Value float64
+} {
+	var calls []struct {
+		Value float64
+	}
+	lockAttributeInterfaceMockSetFloat.RLock()
+	calls = mock.calls.SetFloat
+	lockAttributeInterfaceMockSetFloat.RUnlock()
+	return calls
+}
+
+// SetID calls SetIDFunc.
+func (mock *AttributeInterfaceMock) SetID(id string) *entitystore.Attribute {
+	if mock.SetIDFunc == nil {
+		panic("AttributeInterfaceMock.SetIDFunc: method is nil but AttributeInterface.SetID was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	lockAttributeInterfaceMockSetID.Lock()
+	mock.calls.SetID = append(mock.calls.SetID, callInfo)
+	lockAttributeInterfaceMockSetID.Unlock()
+	return mock.SetIDFunc(id)
+}
+
+// SetIDCalls gets all the calls that were made to SetID.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetIDCalls())
+func (mock *AttributeInterfaceMock) SetIDCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockAttributeInterfaceMockSetID.RLock()
+	calls = mock.calls.SetID
+	lockAttributeInterfaceMockSetID.RUnlock()
+	return calls
+}
+
+// SetInt calls SetIntFunc.
+func (mock *AttributeInterfaceMock) SetInt(value int64) bool {
+	if mock.SetIntFunc == nil {
+		panic("AttributeInterfaceMock.SetIntFunc: method is nil but AttributeInterface.SetInt was just called")
+	}
+	callInfo := struct {
+		Value int64
+	}{
+		Value: value,
+	}
+	lockAttributeInterfaceMockSetInt.Lock()
+	mock.calls.SetInt = append(mock.calls.SetInt, callInfo)
+	lockAttributeInterfaceMockSetInt.Unlock()
+	return mock.SetIntFunc(value)
+}
+
+// SetIntCalls gets all the calls that were made to SetInt.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetIntCalls())
+func (mock *AttributeInterfaceMock) SetIntCalls() []struct {
+	Value int64
+} {
+	var calls []struct {
+		Value int64
+	}
+	lockAttributeInterfaceMockSetInt.RLock()
+	calls = mock.calls.SetInt
+	lockAttributeInterfaceMockSetInt.RUnlock()
+	return calls
+}
+
+// SetString calls SetStringFunc.
+func (mock *AttributeInterfaceMock) SetString(value string) bool {
+	if mock.SetStringFunc == nil {
+		panic("AttributeInterfaceMock.SetStringFunc: method is nil but AttributeInterface.SetString was just called")
+	}
+	callInfo := struct {
+		Value string
+	}{
+		Value: value,
+	}
+	lockAttributeInterfaceMockSetString.Lock()
+	mock.calls.SetString = append(mock.calls.SetString, callInfo)
+	lockAttributeInterfaceMockSetString.Unlock()
+	return mock.SetStringFunc(value)
+}
+
+// SetStringCalls gets all the calls that were made to SetString.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetStringCalls())
+func (mock *AttributeInterfaceMock) SetStringCalls() []struct {
+	Value string
+} {
+	var calls []struct {
+		Value string
+	}
+	lockAttributeInterfaceMockSetString.RLock()
+	calls = mock.calls.SetString
+	lockAttributeInterfaceMockSetString.RUnlock()
+	return calls
+}
+
+// SetUpdatedAt calls SetUpdatedAtFunc.
+func (mock *AttributeInterfaceMock) SetUpdatedAt(updatedAt time.Time) *entitystore.Attribute {
+	if mock.SetUpdatedAtFunc == nil {
+		panic("AttributeInterfaceMock.SetUpdatedAtFunc: method is nil but AttributeInterface.SetUpdatedAt was just called")
+	}
+	callInfo := struct {
+		UpdatedAt time.Time
+	}{
+		UpdatedAt: updatedAt,
+	}
+	lockAttributeInterfaceMockSetUpdatedAt.Lock()
+	mock.calls.SetUpdatedAt = append(mock.calls.SetUpdatedAt, callInfo)
+	lockAttributeInterfaceMockSetUpdatedAt.Unlock()
+	return mock.SetUpdatedAtFunc(updatedAt)
+}
+
+// SetUpdatedAtCalls gets all the calls that were made to SetUpdatedAt.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.SetUpdatedAtCalls())
+func (mock *AttributeInterfaceMock) SetUpdatedAtCalls() []struct {
+	UpdatedAt time.Time
+} {
+	var calls []struct {
+		UpdatedAt time.Time
+	}
+	lockAttributeInterfaceMockSetUpdatedAt.RLock()
+	calls = mock.calls.SetUpdatedAt
+	lockAttributeInterfaceMockSetUpdatedAt.RUnlock()
+	return calls
+}
+
+// ToMap calls ToMapFunc.
+func (mock *AttributeInterfaceMock) ToMap() map[string]any {
+	if mock.ToMapFunc == nil {
+		panic("AttributeInterfaceMock.ToMapFunc: method is nil but AttributeInterface.ToMap was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockToMap.Lock()
+	mock.calls.ToMap = append(mock.calls.ToMap, callInfo)
+	lockAttributeInterfaceMockToMap.Unlock()
+	return mock.ToMapFunc()
+}
+
+// ToMapCalls gets all the calls that were made to ToMap.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.ToMapCalls())
+func (mock *AttributeInterfaceMock) ToMapCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockToMap.RLock()
+	calls = mock.calls.ToMap
+	lockAttributeInterfaceMockToMap.RUnlock()
+	return calls
+}
+
+// UpdatedAt calls UpdatedAtFunc.
+func (mock *AttributeInterfaceMock) UpdatedAt() time.Time {
+	if mock.UpdatedAtFunc == nil {
+		panic("AttributeInterfaceMock.UpdatedAtFunc: method is nil but AttributeInterface.UpdatedAt was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockAttributeInterfaceMockUpdatedAt.Lock()
+	mock.calls.UpdatedAt = append(mock.calls.UpdatedAt, callInfo)
+	lockAttributeInterfaceMockUpdatedAt.Unlock()
+	return mock.UpdatedAtFunc()
+}
+
+// UpdatedAtCalls gets all the calls that were made to UpdatedAt.
+// Check the length with:
+//
+//	len(mockedAttributeInterface.UpdatedAtCalls())
+func (mock *AttributeInterfaceMock) UpdatedAtCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockAttributeInterfaceMockUpdatedAt.RLock()
+	calls = mock.calls.UpdatedAt
+	lockAttributeInterfaceMockUpdatedAt.RUnlock()
+	return calls
+}
@@ -0,0 +1,236 @@
+package entitystore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// sqlCreateOutboxTables returns the SQL creating the outbox table, keeping
+// the change events, and the table of the change feed consumer cursors
+func (st *Store) sqlCreateOutboxTables() ([]string, error) {
+	sqlMysql := []string{`
+	CREATE TABLE IF NOT EXISTS ` + st.outboxTableName + ` (
+		id bigint NOT NULL AUTO_INCREMENT PRIMARY KEY,
+		operation varchar(40) NOT NULL,
+		entity_id varchar(40) NOT NULL,
+		entity_type varchar(40) NOT NULL,
+		attributes text,
+		created_at datetime NOT NULL
+	);
+	`, `
+	CREATE TABLE IF NOT EXISTS ` + st.outboxCursorTableName() + ` (
+		consumer varchar(100) NOT NULL PRIMARY KEY,
+		position bigint NOT NULL,
+		updated_at datetime NOT NULL
+	);
+	`}
+
+	sqlPostgres := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.outboxTableName + `" (
+		"id" bigserial PRIMARY KEY,
+		"operation" varchar(40) NOT NULL,
+		"entity_id" varchar(40) NOT NULL,
+		"entity_type" varchar(40) NOT NULL,
+		"attributes" text,
+		"created_at" timestamptz(6) NOT NULL
+	);
+	`, `
+	CREATE TABLE IF NOT EXISTS "` + st.outboxCursorTableName() + `" (
+		"consumer" varchar(100) NOT NULL PRIMARY KEY,
+		"position" bigint NOT NULL,
+		"updated_at" timestamptz(6) NOT NULL
+	);
+	`}
+
+	sqlSqlite := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.outboxTableName + `" (
+		"id" integer PRIMARY KEY AUTOINCREMENT,
+		"operation" varchar(40) NOT NULL,
+		"entity_id" varchar(40) NOT NULL,
+		"entity_type" varchar(40) NOT NULL,
+		"attributes" text,
+		"created_at" datetime NOT NULL
+	);
+	`, `
+	CREATE TABLE IF NOT EXISTS "` + st.outboxCursorTableName() + `" (
+		"consumer" varchar(100) NOT NULL PRIMARY KEY,
+		"position" integer NOT NULL,
+		"updated_at" datetime NOT NULL
+	);
+	`}
+
+	sqlMssql := []string{`
+	IF OBJECT_ID(N'` + st.outboxTableName + `', N'U') IS NULL
+	CREATE TABLE [` + st.outboxTableName + `] (
+		[id] bigint IDENTITY(1,1) PRIMARY KEY,
+		[operation] nvarchar(40) NOT NULL,
+		[entity_id] nvarchar(40) NOT NULL,
+		[entity_type] nvarchar(40) NOT NULL,
+		[attributes] nvarchar(max),
+		[created_at] datetime2 NOT NULL
+	);
+	`, `
+	IF OBJECT_ID(N'` + st.outboxCursorTableName() + `', N'U') IS NULL
+	CREATE TABLE [` + st.outboxCursorTableName() + `] (
+		[consumer] nvarchar(100) NOT NULL PRIMARY KEY,
+		[position] bigint NOT NULL,
+		[updated_at] datetime2 NOT NULL
+	);
+	`}
+
+	if st.dbDriverName == "mysql" {
+		return sqlMysql, nil
+	} else if st.dbDriverName == "postgres" {
+		return sqlPostgres, nil
+	} else if st.dbDriverName == "sqlite" {
+		return sqlSqlite, nil
+	} else if st.dbDriverName == "mssql" {
+		return sqlMssql, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// outboxCursorTableName returns the name of the table keeping the change
+// feed consumer cursors
+func (st *Store) outboxCursorTableName() string {
+	return st.outboxTableName + "_cursor"
+}
+
+// outboxLockTableName returns the name of the table holding the single row
+// locked by the transactions recording change events
+func (st *Store) outboxLockTableName() string {
+	return st.outboxTableName + "_lock"
+}
+
+// sqlCreateOutboxLockTable returns the SQL creating the outbox lock table
+// with its single row
+func (st *Store) sqlCreateOutboxLockTable() ([]string, error) {
+	sqlMysql := []string{`
+	CREATE TABLE IF NOT EXISTS ` + st.outboxLockTableName() + ` (
+		id int NOT NULL PRIMARY KEY,
+		locked_at datetime NOT NULL
+	);
+	`,
+		`INSERT IGNORE INTO ` + st.outboxLockTableName() + ` (id, locked_at) VALUES (1, CURRENT_TIMESTAMP);`,
+	}
+
+	sqlPostgres := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.outboxLockTableName() + `" (
+		"id" integer NOT NULL PRIMARY KEY,
+		"locked_at" timestamptz(6) NOT NULL
+	);
+	`,
+		`INSERT INTO "` + st.outboxLockTableName() + `" ("id", "locked_at") VALUES (1, CURRENT_TIMESTAMP) ON CONFLICT DO NOTHING;`,
+	}
+
+	sqlSqlite := []string{`
+	CREATE TABLE IF NOT EXISTS "` + st.outboxLockTableName() + `" (
+		"id" integer NOT NULL PRIMARY KEY,
+		"locked_at" datetime NOT NULL
+	);
+	`,
+		`INSERT OR IGNORE INTO "` + st.outboxLockTableName() + `" ("id", "locked_at") VALUES (1, CURRENT_TIMESTAMP);`,
+	}
+
+	sqlMssql := []string{`
+	IF OBJECT_ID(N'` + st.outboxLockTableName() + `', N'U') IS NULL
+	CREATE TABLE [` + st.outboxLockTableName() + `] (
+		[id] int NOT NULL PRIMARY KEY,
+		[locked_at] datetime2 NOT NULL
+	);
+	`,
+		`IF NOT EXISTS (SELECT * FROM [` + st.outboxLockTableName() + `]) INSERT INTO [` + st.outboxLockTableName() + `] ([id], [locked_at]) VALUES (1, SYSDATETIME());`,
+	}
+
+	if st.dbDriverName == "mysql" {
+		return sqlMysql, nil
+	} else if st.dbDriverName == "postgres" {
+		return sqlPostgres, nil
+	} else if st.dbDriverName == "sqlite" {
+		return sqlSqlite, nil
+	} else if st.dbDriverName == "mssql" {
+		return sqlMssql, nil
+	}
+
+	return nil, errors.New("unsupported driver " + st.dbDriverName)
+}
+
+// changeRecord records a change event of a mutation, in the outbox when
+// enabled, within the transaction of the mutation
+func (st *Store) changeRecord(tx *sql.Tx, change *ChangeEvent) error {
+	change.CreatedAt = time.Now()
+
+	if !st.outboxEnabled {
+		return nil
+	}
+
+	return st.storage.outboxAppend(tx, *change)
+}
+
+// changeEntityType returns the entity type for the change event of a
+// mutation which does not read the entity. It is only looked up when the
+// event is recorded in the outbox or published
+func (st *Store) changeEntityType(entityID string) (string, error) {
+	if !st.outboxEnabled && !st.subscribed() {
+		return "", nil
+	}
+
+	entity, err := st.EntityFindByID(entityID, FindOptions{CacheBypass: true})
+
+	if err != nil || entity == nil {
+		return "", err
+	}
+
+	return entity.Type(), nil
+}
+
+// outboxAppend inserts a change event in the outbox.
+//
+// The IDs are assigned on insert but become visible on commit, a reader
+// could otherwise read past an event with a lower ID still to commit. The
+// row of the lock table is locked first, until the transaction completes,
+// so the transactions recording events run one after the other and commit
+// their events in the order of the IDs
+func (st *sqlStorage) outboxAppend(tx *sql.Tx, change ChangeEvent) error {
+	attributes, err := json.Marshal(change.Attributes)
+
+	if err != nil {
+		return err
+	}
+
+	sqlStr, params, errSql := st.dialect().Update(st.outboxLockTableName()).Prepared(true).
+		Set(goqu.Record{"locked_at": change.CreatedAt}).
+		Where(goqu.C("id").Eq(1)).
+		ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	if _, err := st.sqlExec("ChangeRecord", tx, false, sqlStr, params...); err != nil {
+		return err
+	}
+
+	q := st.dialect().Insert(st.outboxTableName).Prepared(true)
+	q = q.Rows(goqu.Record{
+		"operation":   change.Operation,
+		"entity_id":   change.EntityID,
+		"entity_type": change.EntityType,
+		"attributes":  string(attributes),
+		"created_at":  change.CreatedAt,
+	})
+	sqlStr, params, errSql = q.ToSQL()
+
+	if errSql != nil {
+		return errSql
+	}
+
+	_, err = st.sqlExec("ChangeRecord", tx, false, sqlStr, params...)
+
+	return err
+}
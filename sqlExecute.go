@@ -0,0 +1,155 @@
+package entitystore
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/georgysavva/scany/sqlscan"
+)
+
+// sqlExec executes a statement of the operation op with its parameters.
+// With useCache set and the statement cache enabled a cached prepared
+// statement is used
+func (st *Store) sqlExec(op string, db txOrDB, useCache bool, sqlStr string, params ...any) (sql.Result, error) {
+	ctx, event := st.queryStart(context.Background(), op, sqlStr, params)
+
+	result, err := st.sqlExecRaw(ctx, db, useCache, sqlStr, params...)
+
+	rows := int64(0)
+	if err == nil {
+		rows, _ = result.RowsAffected()
+	}
+
+	st.queryEnd(event, rows, err)
+
+	return result, err
+}
+
+// sqlSelect runs a query of the operation op and scans all rows into dest
+func (st *Store) sqlSelect(op string, db txOrDB, useCache bool, dest any, sqlStr string, params ...any) error {
+	ctx, event := st.queryStart(context.Background(), op, sqlStr, params)
+
+	rows, err := st.sqlQueryRaw(ctx, db, useCache, sqlStr, params...)
+
+	if err == nil {
+		err = sqlscan.ScanAll(dest, rows)
+	}
+
+	count := int64(0)
+	if err == nil {
+		count = int64(reflect.ValueOf(dest).Elem().Len())
+	}
+
+	st.queryEnd(event, count, err)
+
+	return err
+}
+
+// sqlGet runs a query of the operation op and scans the first row into
+// dest. A missing row is returned as sqlscan.NotFound, and not reported
+// as an error to the hooks and the logger
+func (st *Store) sqlGet(op string, db txOrDB, useCache bool, dest any, sqlStr string, params ...any) error {
+	ctx, event := st.queryStart(context.Background(), op, sqlStr, params)
+
+	rows, err := st.sqlQueryRaw(ctx, db, useCache, sqlStr, params...)
+
+	if err == nil {
+		err = sqlscan.ScanOne(dest, rows)
+	}
+
+	if sqlscan.NotFound(err) {
+		st.queryEnd(event, 0, nil)
+	} else if err != nil {
+		st.queryEnd(event, 0, err)
+	} else {
+		st.queryEnd(event, 1, nil)
+	}
+
+	return err
+}
+
+// queryStart creates the event of a statement of the operation op and runs
+// the BeforeQuery hooks, returning the context derived by them
+func (st *Store) queryStart(ctx context.Context, op string, sqlStr string, params []any) (context.Context, *QueryEvent) {
+	event := &QueryEvent{
+		Operation:  op,
+		DriverName: st.dbDriverName,
+		SQL:        sqlStr,
+		Args:       params,
+		StartedAt:  time.Now(),
+	}
+
+	for _, hook := range st.queryHooks {
+		ctx = hook.BeforeQuery(ctx, event)
+		event.hookContexts = append(event.hookContexts, ctx)
+	}
+
+	return ctx, event
+}
+
+// queryEnd completes the event of a statement, runs the AfterQuery hooks
+// in reverse order, so the first hook wraps all others, each with the
+// context its BeforeQuery returned, and logs it
+func (st *Store) queryEnd(event *QueryEvent, rows int64, err error) {
+	event.Duration = time.Since(event.StartedAt)
+	event.Rows = rows
+	event.Error = err
+
+	for i := len(event.hookContexts) - 1; i >= 0; i-- {
+		st.queryHooks[i].AfterQuery(event.hookContexts[i], event)
+	}
+
+	st.logQuery(event)
+}
+
+// sqlExecRaw executes a statement without hooks and logging
+func (st *Store) sqlExecRaw(ctx context.Context, db txOrDB, useCache bool, sqlStr string, params ...any) (sql.Result, error) {
+	stmt, err := st.sqlPrepared(db, useCache, sqlStr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt != nil {
+		return stmt.ExecContext(ctx, params...)
+	}
+
+	return db.ExecContext(ctx, sqlStr, params...)
+}
+
+// sqlQueryRaw runs a query without hooks and logging
+func (st *Store) sqlQueryRaw(ctx context.Context, db txOrDB, useCache bool, sqlStr string, params ...any) (*sql.Rows, error) {
+	stmt, err := st.sqlPrepared(db, useCache, sqlStr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt != nil {
+		return stmt.QueryContext(ctx, params...)
+	}
+
+	return db.QueryContext(ctx, sqlStr, params...)
+}
+
+// sqlPrepared returns the cached prepared statement for the SQL, bound to
+// the transaction if db is one. Returns nil if the cache is not used
+func (st *Store) sqlPrepared(db txOrDB, useCache bool, sqlStr string) (*sql.Stmt, error) {
+	if !useCache || st.statementCache == nil {
+		return nil, nil
+	}
+
+	stmt, err := st.statementCache.prepare(sqlStr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tx, isTx := db.(*sql.Tx); isTx {
+		return tx.Stmt(stmt), nil
+	}
+
+	return stmt, nil
+}
@@ -0,0 +1,57 @@
+package entitystore
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// statementCache keeps prepared statements for reuse, keyed by their SQL
+type statementCache struct {
+	db         *sql.DB
+	mutex      sync.Mutex
+	statements map[string]*sql.Stmt
+}
+
+func newStatementCache(db *sql.DB) *statementCache {
+	return &statementCache{
+		db:         db,
+		statements: map[string]*sql.Stmt{},
+	}
+}
+
+// prepare returns the cached statement for the SQL, preparing it on first use
+func (c *statementCache) prepare(sqlStr string) (*sql.Stmt, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if stmt, exists := c.statements[sqlStr]; exists {
+		return stmt, nil
+	}
+
+	stmt, err := c.db.Prepare(sqlStr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.statements[sqlStr] = stmt
+
+	return stmt, nil
+}
+
+// close closes and forgets all cached statements
+func (c *statementCache) close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var firstErr error
+
+	for sqlStr, stmt := range c.statements {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.statements, sqlStr)
+	}
+
+	return firstErr
+}
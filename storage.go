@@ -0,0 +1,81 @@
+package entitystore
+
+import (
+	"database/sql"
+	"time"
+)
+
+// storage keeps the entities and attributes of a store. The store
+// validates the arguments, runs the hooks, records the change events and
+// invalidates the cache, the storage reads and writes. It is implemented
+// by the SQL database and by memory.
+//
+// The writes get the transaction of the operation, from transaction, and
+// the reads the transaction or the database to read from. The memory
+// storage is given a nil transaction
+type storage interface {
+	// transaction runs fn in a transaction, committed when fn succeeds
+	// and rolled back otherwise
+	transaction(op string, fn func(tx *sql.Tx) error) error
+
+	// snapshot runs fn reading a consistent view of the store
+	snapshot(op string, fn func(db txOrDB) error) error
+
+	migrate() error
+	migrationStatus() ([]Migration, error)
+	repairDuplicateAttributes() (int64, error)
+
+	entityInsert(tx *sql.Tx, entity Entity, attributes map[string]string) error
+	entityList(options EntityQueryOptions, useCache bool) ([]Entity, error)
+	entityCount(options EntityQueryOptions) (int64, error)
+	entityExists(db txOrDB, entityID string) (bool, error)
+	entityUpdate(tx *sql.Tx, entity Entity, ifVersion *int64) error
+	entityVersionBump(db txOrDB, entityID string, ifVersion *int64) error
+	entityDelete(tx *sql.Tx, entityID string) error
+	entityTrashMove(tx *sql.Tx, entity Entity) (bool, error)
+	entityTrashFind(entityID string) (*Entity, []Attribute, error)
+	entityRestore(tx *sql.Tx, entity Entity, attributes []Attribute) error
+	entityIDsByAttribute(entityType string, attributeKey string, attributeValue string) ([]string, error)
+	entityTypes() ([]EntityTypeCount, error)
+	entityImport(tx *sql.Tx, record EntityRecord, options ImportOptions) (outcome string, entityID string, err error)
+
+	entityHandleFind(db txOrDB, entityType string, entityHandle string) (string, error)
+	entityHandlesWithPrefix(entityType string, prefix string) ([]string, error)
+
+	attributeInsert(db txOrDB, attr Attribute) (*Attribute, error)
+	attributeList(options AttributeQueryOptions, useCache bool) ([]Attribute, error)
+	attributeUpdate(db txOrDB, attr Attribute) error
+	attributesSet(tx *sql.Tx, entityID string, attributes map[string]string) error
+	attributeIncrement(tx *sql.Tx, entityID string, attributeKey string, delta any) (value string, inserted bool, err error)
+	attributeKeys(entityType string, sampleSize int) ([]AttributeKeyInfo, error)
+	attributeValueCounts(op string, entityType string, attributeKey string, filter map[string]string, limit int) ([]FacetValue, error)
+
+	attributeValues(db txOrDB, entityID string, attributeKey string) ([]string, error)
+	attributeValuesChange(tx *sql.Tx, entityID string, attributeKey string, change func(values []string) ([]string, bool)) ([]string, bool, error)
+	attributeValuesOfEntities(entityIDs []string) (map[string]map[string][]string, error)
+
+	aggregate(entityType string, options AggregateOptions) ([]AggregateResult, error)
+	stats() (StoreStats, error)
+
+	backupRows(db txOrDB, table backupTable, after map[string]any) ([]map[string]any, error)
+	backupRowsInsert(tx *sql.Tx, table backupTable, rows []map[string]any) error
+
+	outboxAppend(tx *sql.Tx, change ChangeEvent) error
+	outboxList(position int64, limit uint64) ([]ChangeEvent, error)
+	outboxCursorCreate(consumer string) error
+	outboxCursor(consumer string) (int64, error)
+	outboxAck(consumer string, eventID int64) error
+	outboxPrune() (int64, error)
+
+	entityLock(lease EntityLease) (*EntityLease, error)
+	entityLockRenew(entityID string, owner string, expiresAt time.Time) (*EntityLease, error)
+	entityUnlock(entityID string, owner string) error
+}
+
+// sqlStorage keeps the entities and attributes in the tables of the store
+type sqlStorage struct {
+	*Store
+}
+
+var _ storage = (*sqlStorage)(nil)
+var _ storage = (*memoryStorage)(nil)
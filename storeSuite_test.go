@@ -0,0 +1,78 @@
+package entitystore_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gouniverse/entitystore"
+	"github.com/gouniverse/entitystore/entitystoretest"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestStoreSuiteSQL(t *testing.T) {
+	entitystoretest.RunStoreSuite(t, func(t *testing.T) entitystore.StoreInterface {
+		os.Remove("test_store_suite.db")
+
+		// SQLite allows a single writer, the others wait for it
+		db, err := sql.Open("sqlite3", "test_store_suite.db?_busy_timeout=5000")
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		store, err := entitystore.NewStore(entitystore.NewStoreOptions{
+			DB:                 db,
+			EntityTableName:    "cms_entity",
+			AttributeTableName: "cms_attribute",
+			AutomigrateEnabled: true,
+		})
+
+		if err != nil {
+			t.Fatalf("Store could not be created: " + err.Error())
+		}
+
+		return store
+	})
+}
+
+func TestStoreSuiteCache(t *testing.T) {
+	entitystoretest.RunStoreSuite(t, func(t *testing.T) entitystore.StoreInterface {
+		os.Remove("test_store_suite_cache.db")
+
+		db, err := sql.Open("sqlite3", "test_store_suite_cache.db?_busy_timeout=5000")
+
+		if err != nil {
+			t.Fatalf(err.Error())
+		}
+
+		store, err := entitystore.NewStore(entitystore.NewStoreOptions{
+			DB:                 db,
+			EntityTableName:    "cms_entity",
+			AttributeTableName: "cms_attribute",
+			AutomigrateEnabled: true,
+			Cache:              entitystore.NewLRUCache(100, time.Minute),
+		})
+
+		if err != nil {
+			t.Fatalf("Store could not be created: " + err.Error())
+		}
+
+		return store
+	})
+}
+
+func TestStoreSuiteMemory(t *testing.T) {
+	entitystoretest.RunStoreSuite(t, func(t *testing.T) entitystore.StoreInterface {
+		store, err := entitystore.NewStore(entitystore.NewStoreOptions{
+			Backend: entitystore.BackendMemory,
+		})
+
+		if err != nil {
+			t.Fatalf("Store could not be created: " + err.Error())
+		}
+
+		return store
+	})
+}
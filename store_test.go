@@ -43,6 +43,47 @@ func TestStoreCreate(t *testing.T) {
 	}
 }
 
+func TestStoreTableNames(t *testing.T) {
+	db := InitDB("test_store_table_names.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                 db,
+		EntityTableName:    "cms_entity",
+		AttributeTableName: "cms_attribute",
+		AutomigrateEnabled: true,
+		OutboxEnabled:      true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	tableNames := map[string]bool{}
+	for _, tableName := range store.tableNames() {
+		tableNames[tableName] = true
+	}
+
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+
+	if err != nil {
+		t.Fatalf("Tables could not be listed: " + err.Error())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("Table could not be read: " + err.Error())
+		}
+
+		if !tableNames[name] {
+			t.Fatal("Table missing from the table names", name)
+		}
+	}
+}
+
 func TestStoreAutomigrate(t *testing.T) {
 	db := InitDB("test_entity_automigrate.db")
 
@@ -62,3 +103,63 @@ func TestStoreAutomigrate(t *testing.T) {
 		t.Fatal("Automigrate failed: ", err.Error())
 	}
 }
+
+func TestStoreStatementCache(t *testing.T) {
+	db := InitDB("test_store_statement_cache.db")
+
+	store, err := NewStore(NewStoreOptions{
+		DB:                    db,
+		EntityTableName:       "cms_entity",
+		AttributeTableName:    "cms_attribute",
+		AutomigrateEnabled:    true,
+		StatementCacheEnabled: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Store could not be created: " + err.Error())
+	}
+
+	entity, err := store.EntityCreate("post")
+
+	if err != nil {
+		t.Fatalf("Entity could not be created: " + err.Error())
+	}
+
+	for _, value := range []string{"one", "two", "three"} {
+		if err := store.AttributeSetString(entity.ID(), "title", value); err != nil {
+			t.Fatalf("Attribute could not be set: " + err.Error())
+		}
+
+		title, err := entity.GetString("title", "")
+
+		if err != nil {
+			t.Fatalf("Attribute could not be retrieved: " + err.Error())
+		}
+
+		if title != value {
+			t.Fatal("Title is incorrect", "must be", value, "found", title)
+		}
+
+		found, err := store.EntityFindByID(entity.ID())
+
+		if err != nil {
+			t.Fatalf("Entity could not be found: " + err.Error())
+		}
+
+		if found == nil {
+			t.Fatalf("Entity must be found")
+		}
+	}
+
+	if len(store.statementCache.statements) == 0 {
+		t.Fatalf("Statements must be cached")
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Store could not be closed: " + err.Error())
+	}
+
+	if len(store.statementCache.statements) != 0 {
+		t.Fatalf("Cached statements must be released on close")
+	}
+}
@@ -0,0 +1,47 @@
+package entitystore
+
+import (
+	"context"
+	"errors"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/georgysavva/scany/sqlscan"
+)
+
+// tableExists checks whether a table exists in the current database
+func (st *Store) tableExists(ctx context.Context, op string, db sqlscan.Querier, tableName string) (bool, error) {
+	var q *goqu.SelectDataset
+
+	if st.dbDriverName == "mysql" {
+		q = st.dialect().From(goqu.T("tables").Schema("information_schema")).
+			Where(goqu.C("table_schema").Eq(goqu.L("DATABASE()")), goqu.C("table_name").Eq(tableName))
+	} else if st.dbDriverName == "postgres" {
+		q = st.dialect().From(goqu.T("tables").Schema("information_schema")).
+			Where(goqu.C("table_schema").Eq(goqu.L("current_schema()")), goqu.C("table_name").Eq(tableName))
+	} else if st.dbDriverName == "mssql" {
+		q = st.dialect().From(goqu.T("tables").Schema("information_schema")).
+			Where(goqu.C("table_schema").Eq(goqu.L("SCHEMA_NAME()")), goqu.C("table_name").Eq(tableName))
+	} else if st.dbDriverName == "sqlite" {
+		q = st.dialect().From("sqlite_master").
+			Where(goqu.C("type").Eq("table"), goqu.C("name").Eq(tableName))
+	} else {
+		return false, errors.New("unsupported driver " + st.dbDriverName)
+	}
+
+	sqlStr, params, errSql := q.Prepared(true).Select(goqu.COUNT(goqu.Star()).As("count")).ToSQL()
+
+	if errSql != nil {
+		return false, errSql
+	}
+
+	queryCtx, event := st.queryStart(ctx, op, sqlStr, params)
+	var count int64
+	err := sqlscan.Get(queryCtx, db, &count, sqlStr, params...)
+	st.queryEnd(event, 1, err)
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
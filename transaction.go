@@ -0,0 +1,69 @@
+package entitystore
+
+import (
+	"context"
+	"database/sql"
+)
+
+// withTransaction runs fn in a transaction of the operation op, committed
+// when fn succeeds and rolled back otherwise
+func (st *Store) withTransaction(op string, fn func(tx *sql.Tx) error) error {
+	return st.storage.transaction(op, fn)
+}
+
+// transaction runs fn in a database transaction
+func (st *sqlStorage) transaction(op string, fn func(tx *sql.Tx) error) error {
+	tx, err := st.db.Begin()
+
+	if err != nil {
+		st.logError(op, err)
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if txErr := tx.Rollback(); txErr != nil {
+			st.logError(op, txErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		st.logError(op, err)
+		return err
+	}
+
+	return nil
+}
+
+// snapshot runs fn in a read only transaction seeing the rows committed
+// when it starts. PostgreSQL and MySQL read a snapshot in a repeatable
+// read transaction. SQL Server has no read only transactions and reads in
+// a serializable transaction, blocking the writers until fn returns.
+// SQLite reads in a plain transaction, which sees no other writes
+func (st *sqlStorage) snapshot(op string, fn func(db txOrDB) error) error {
+	options := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+	if st.dbDriverName == "mssql" {
+		options = &sql.TxOptions{Isolation: sql.LevelSerializable}
+	} else if st.dbDriverName == "sqlite" {
+		options = nil
+	}
+
+	tx, err := st.db.BeginTx(context.Background(), options)
+
+	if err != nil {
+		st.logError(op, err)
+		return err
+	}
+
+	defer tx.Rollback()
+
+	return fn(tx)
+}
@@ -1,10 +1,15 @@
 package entitystore
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 type txOrDB interface {
 	Query(string, ...interface{}) (*sql.Rows, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
 	QueryRow(string, ...interface{}) *sql.Row
 	Prepare(string) (*sql.Stmt, error)
 	Exec(string, ...interface{}) (sql.Result, error)
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
 }